@@ -41,6 +41,10 @@ func NewRootCmd() *cobra.Command {
 				os.Exit(1)
 			}
 			slog.Debug("loaded configuration", "config", cfg)
+			if err := cfg.Validate(); err != nil {
+				slog.Error("invalid configuration", slog.Any("error", err))
+				os.Exit(1)
+			}
 
 			// Initialise telemetry
 			var err error
@@ -63,6 +67,7 @@ func NewRootCmd() *cobra.Command {
 	// Add subcommands
 	cmd.AddCommand(NewInterviewCmd())
 	cmd.AddCommand(web.NewServeCmd())
+	cmd.AddCommand(cli.NewInviteCmd())
 	cmd.AddCommand(cli.NewDebugCmd())
 
 	return cmd
@@ -0,0 +1,274 @@
+package fallback
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// ErrNoProviders is returned when none of the providers in the chain could provide a response.
+var ErrNoProviders = errors.New("no provider in the fallback chain is available")
+
+// Member is a named provider in a fallback chain.
+type Member struct {
+	Name     string
+	Provider interview.QuestionProvider
+}
+
+// QuestionProvider asks questions using the first available provider in a chain, and fails over to the next
+// provider part way through an interview if the current one stops responding. The transcript so far is handed
+// to the new provider so the interview continues where it left off.
+type QuestionProvider struct {
+	members    []Member
+	health     *Health
+	current    int
+	started    bool
+	transcript domain.Transcript
+	failovers  []domain.Failover
+	// attachments are passed to whichever provider is given the next answer.
+	attachments []domain.Attachment
+}
+
+// New creates a QuestionProvider from an ordered chain of providers.
+func New(health *Health, members ...Member) *QuestionProvider {
+	return &QuestionProvider{
+		members: members,
+		health:  health,
+	}
+}
+
+// NextQuestion returns the next question from the current provider, failing over if necessary.
+func (p *QuestionProvider) NextQuestion(previousAnswer string) (string, bool) {
+	question, hasMore, err := p.TryNextQuestion(previousAnswer)
+	if err != nil {
+		slog.Error("Error getting next question", "error", err)
+		return "", false
+	}
+	return question, hasMore
+}
+
+// TryNextQuestion returns the next question from the current provider, failing over to each following
+// provider in turn. It returns an error only if every remaining provider fails.
+func (p *QuestionProvider) TryNextQuestion(previousAnswer string) (string, bool, error) {
+	if n := len(p.transcript.Entries); n > 0 {
+		p.transcript.Entries[n-1].Answer = previousAnswer
+	}
+
+	if !p.started {
+		p.start()
+	}
+
+	attachments := p.attachments
+	p.attachments = nil
+
+	var errs []error
+	for p.current < len(p.members) {
+		m := p.members[p.current]
+		if r, ok := m.Provider.(interview.AttachmentReceiver); ok && len(attachments) > 0 {
+			r.ReceiveAttachments(attachments)
+		}
+		question, hasMore, err := tryNextQuestion(m.Provider, previousAnswer)
+		if err == nil {
+			p.health.Success(m.Name)
+			if hasMore {
+				p.record(question)
+			}
+			return question, hasMore, nil
+		}
+
+		slog.Warn("Provider failed, failing over", "provider", m.Name, "error", err)
+		p.health.Failure(m.Name)
+		errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+		if !p.failover(err) {
+			break
+		}
+	}
+
+	return "", false, fmt.Errorf("%w: %w", ErrNoProviders, errors.Join(errs...))
+}
+
+// ReceiveAttachments passes the files to the provider that is given the next answer, if it can take them.
+func (p *QuestionProvider) ReceiveAttachments(attachments []domain.Attachment) {
+	p.attachments = append(p.attachments, attachments...)
+}
+
+// Summarize generates a summary with the current provider, falling back to the following providers.
+func (p *QuestionProvider) Summarize(transcript *domain.Transcript) (string, error) {
+	var errs []error
+	for i := p.current; i < len(p.members); i++ {
+		m := p.members[i]
+		if i != p.current && !p.health.Healthy(m.Name) {
+			continue
+		}
+		summary, err := m.Provider.Summarize(transcript)
+		if err == nil {
+			return summary, nil
+		}
+		slog.Warn("Provider could not summarise, trying the next one", "provider", m.Name, "error", err)
+		p.health.Failure(m.Name)
+		errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+	}
+	return "", fmt.Errorf("%w: %w", ErrNoProviders, errors.Join(errs...))
+}
+
+// Rephrase asks the current provider to rephrase a question, if it is able to.
+func (p *QuestionProvider) Rephrase(question, guidance string) (string, error) {
+	if p.current >= len(p.members) {
+		return "", ErrNoProviders
+	}
+	r, ok := p.members[p.current].Provider.(interface {
+		Rephrase(question, guidance string) (string, error)
+	})
+	if !ok {
+		return "", fmt.Errorf("provider %s cannot rephrase questions", p.members[p.current].Name)
+	}
+	return r.Rephrase(question, guidance)
+}
+
+// ReplaceQuestion replaces the last question in the transcript so far, and in the current provider's
+// conversation if it keeps one.
+func (p *QuestionProvider) ReplaceQuestion(question string) {
+	if n := len(p.transcript.Entries); n > 0 {
+		p.transcript.Entries[n-1].Question = question
+	}
+	if p.current >= len(p.members) {
+		return
+	}
+	if r, ok := p.members[p.current].Provider.(interface{ ReplaceQuestion(question string) }); ok {
+		r.ReplaceQuestion(question)
+	}
+}
+
+// Resume restores the conversation on the first available provider.
+func (p *QuestionProvider) Resume(transcript *domain.Transcript) error {
+	p.transcript.Entries = append(p.transcript.Entries[:0], transcript.Entries...)
+	p.start()
+	for p.current < len(p.members) {
+		err := resume(p.members[p.current].Provider, &p.transcript)
+		if err == nil {
+			return nil
+		}
+		p.health.Failure(p.members[p.current].Name)
+		if !p.failover(err) {
+			break
+		}
+	}
+	return ErrNoProviders
+}
+
+// Describe reports the provider that is currently in use.
+func (p *QuestionProvider) Describe() *domain.ProviderInfo {
+	if p.current < len(p.members) {
+		if d, ok := p.members[p.current].Provider.(interview.Describer); ok {
+			return d.Describe()
+		}
+		return &domain.ProviderInfo{Name: p.members[p.current].Name}
+	}
+	return nil
+}
+
+// Failovers returns every switch between providers made during the interview.
+func (p *QuestionProvider) Failovers() []domain.Failover {
+	return append([]domain.Failover(nil), p.failovers...)
+}
+
+// failover moves to the next healthy provider and resumes the interview on it. It returns false if there
+// are no providers left.
+func (p *QuestionProvider) failover(reason error) bool {
+	from := p.members[p.current].Name
+	for {
+		p.current = p.firstHealthy(p.current + 1)
+		if p.current >= len(p.members) {
+			return false
+		}
+
+		to := p.members[p.current]
+		if err := resume(to.Provider, &p.transcript); err != nil {
+			slog.Warn("Could not resume interview on provider", "provider", to.Name, "error", err)
+			p.health.Failure(to.Name)
+			continue
+		}
+
+		p.failovers = append(p.failovers, domain.Failover{
+			At:       time.Now(),
+			From:     from,
+			To:       to.Name,
+			Reason:   reason.Error(),
+			Question: len(p.transcript.Entries) + 1,
+		})
+		slog.Info("Failed over to provider", "from", from, "to", to.Name)
+		return true
+	}
+}
+
+// start picks the first healthy provider. Skipping an unhealthy primary is recorded as a failover, so the
+// interview shows it wasn't held with the provider it would have been.
+func (p *QuestionProvider) start() {
+	p.started = true
+	p.current = p.firstHealthy(0)
+	if p.current == 0 {
+		return
+	}
+
+	from, to := p.members[0].Name, p.members[p.current].Name
+	p.failovers = append(p.failovers, domain.Failover{
+		At:       time.Now(),
+		From:     from,
+		To:       to,
+		Reason:   "unhealthy",
+		Question: len(p.transcript.Entries) + 1,
+	})
+	slog.Info("Skipped unhealthy provider", "from", from, "to", to)
+}
+
+// firstHealthy returns the index of the first healthy provider at or after start. If none are healthy, it
+// returns start, so that the remaining providers are still tried rather than giving up.
+func (p *QuestionProvider) firstHealthy(start int) int {
+	for i := start; i < len(p.members); i++ {
+		if p.health.Healthy(p.members[i].Name) {
+			return i
+		}
+	}
+	return start
+}
+
+// record adds a newly asked question to the transcript so far.
+func (p *QuestionProvider) record(question string) {
+	p.transcript.Entries = append(p.transcript.Entries, struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}{Question: question})
+}
+
+// tryNextQuestion asks a provider for its next question, treating providers that cannot report failures as
+// always succeeding.
+func tryNextQuestion(provider interview.QuestionProvider, previousAnswer string) (string, bool, error) {
+	if f, ok := provider.(interview.FallibleQuestionProvider); ok {
+		return f.TryNextQuestion(previousAnswer)
+	}
+	question, hasMore := provider.NextQuestion(previousAnswer)
+	return question, hasMore, nil
+}
+
+// resume hands the transcript so far to a provider, if it is able to continue from it.
+func resume(provider interview.QuestionProvider, transcript *domain.Transcript) error {
+	if len(transcript.Entries) == 0 {
+		return nil
+	}
+	if r, ok := provider.(interview.Resumer); ok {
+		return r.Resume(transcript)
+	}
+	return nil
+}
+
+// Ensure QuestionProvider implements the domain interfaces.
+var _ interview.QuestionProvider = (*QuestionProvider)(nil)
+var _ interview.FallibleQuestionProvider = (*QuestionProvider)(nil)
+var _ interview.Resumer = (*QuestionProvider)(nil)
+var _ interview.Describer = (*QuestionProvider)(nil)
+var _ interview.FailoverReporter = (*QuestionProvider)(nil)
+var _ interview.AttachmentReceiver = (*QuestionProvider)(nil)
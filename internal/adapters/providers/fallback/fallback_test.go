@@ -0,0 +1,203 @@
+package fallback
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider asks a fixed list of questions, and fails once it has asked failAfter of them.
+type fakeProvider struct {
+	name      string
+	questions []string
+	failAfter int
+	asked     int
+	answers   []string
+	resumed   *domain.Transcript
+	received  []domain.Attachment
+}
+
+func (f *fakeProvider) NextQuestion(previousAnswer string) (string, bool) {
+	q, more, _ := f.TryNextQuestion(previousAnswer)
+	return q, more
+}
+
+func (f *fakeProvider) TryNextQuestion(previousAnswer string) (string, bool, error) {
+	f.answers = append(f.answers, previousAnswer)
+	if f.failAfter >= 0 && f.asked >= f.failAfter {
+		return "", false, errors.New(f.name + " is down")
+	}
+	if f.asked >= len(f.questions) {
+		return "", false, nil
+	}
+	f.asked++
+	return f.questions[f.asked-1], true, nil
+}
+
+func (f *fakeProvider) Resume(transcript *domain.Transcript) error {
+	f.resumed = &domain.Transcript{Entries: append(transcript.Entries[:0:0], transcript.Entries...)}
+	f.asked = len(transcript.Entries)
+	return nil
+}
+
+func (f *fakeProvider) ReceiveAttachments(attachments []domain.Attachment) {
+	f.received = append(f.received, attachments...)
+}
+
+func (f *fakeProvider) Summarize(*domain.Transcript) (string, error) {
+	if f.failAfter >= 0 && f.asked >= f.failAfter {
+		return "", errors.New(f.name + " is down")
+	}
+	return "summary from " + f.name, nil
+}
+
+func (f *fakeProvider) Describe() *domain.ProviderInfo {
+	return &domain.ProviderInfo{Name: f.name}
+}
+
+func TestQuestionProvider_FailsOverMidInterview(t *testing.T) {
+	questions := []string{"one?", "two?", "three?"}
+	primary := &fakeProvider{name: "gemini", questions: questions, failAfter: 2}
+	secondary := &fakeProvider{name: "openai", questions: questions, failAfter: -1}
+	health := NewHealth(time.Minute, time.Hour)
+	p := New(health, Member{Name: "gemini", Provider: primary}, Member{Name: "openai", Provider: secondary})
+
+	var asked []string
+	answer := ""
+	for {
+		q, more, err := p.TryNextQuestion(answer)
+		require.NoError(t, err)
+		if !more {
+			break
+		}
+		asked = append(asked, q)
+		answer = "answer " + q
+	}
+
+	assert.Equal(t, questions, asked)
+	require.NotNil(t, secondary.resumed)
+	require.Len(t, secondary.resumed.Entries, 2)
+	assert.Equal(t, "answer one?", secondary.resumed.Entries[0].Answer)
+	// The answer to the last question asked by the primary is passed on to the secondary.
+	assert.Equal(t, "answer two?", secondary.answers[0])
+
+	failovers := p.Failovers()
+	require.Len(t, failovers, 1)
+	assert.Equal(t, "gemini", failovers[0].From)
+	assert.Equal(t, "openai", failovers[0].To)
+	assert.Equal(t, 3, failovers[0].Question)
+	assert.Contains(t, failovers[0].Reason, "gemini is down")
+
+	assert.Equal(t, "openai", p.Describe().Name)
+	assert.False(t, health.Healthy("gemini"))
+
+	summary, err := p.Summarize(&domain.Transcript{})
+	require.NoError(t, err)
+	assert.Equal(t, "summary from openai", summary)
+}
+
+func TestQuestionProvider_Attachments(t *testing.T) {
+	primary := &fakeProvider{name: "gemini", questions: []string{"one?", "two?"}, failAfter: 1}
+	secondary := &fakeProvider{name: "openai", questions: []string{"one?", "two?"}, failAfter: -1}
+	p := New(NewHealth(time.Minute, time.Hour), Member{Name: "gemini", Provider: primary}, Member{Name: "openai", Provider: secondary})
+
+	_, _, err := p.TryNextQuestion("")
+	require.NoError(t, err)
+	screenshot := domain.Attachment{Name: "dashboard.png", MimeType: "image/png"}
+	p.ReceiveAttachments([]domain.Attachment{screenshot})
+	_, _, err = p.TryNextQuestion("(attached dashboard.png)")
+	require.NoError(t, err)
+
+	// The primary fails, so the secondary is given the files as well as the answer.
+	assert.Equal(t, []domain.Attachment{screenshot}, primary.received)
+	assert.Equal(t, []domain.Attachment{screenshot}, secondary.received)
+	assert.Empty(t, p.attachments)
+}
+
+func TestQuestionProvider_SkipsUnhealthyProviders(t *testing.T) {
+	health := NewHealth(time.Minute, time.Hour)
+	health.Failure("gemini")
+	primary := &fakeProvider{name: "gemini", questions: []string{"one?"}, failAfter: -1}
+	secondary := &fakeProvider{name: "static", questions: []string{"fallback?"}, failAfter: -1}
+	p := New(health, Member{Name: "gemini", Provider: primary}, Member{Name: "static", Provider: secondary})
+
+	q, more, err := p.TryNextQuestion("")
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.Equal(t, "fallback?", q)
+	assert.Empty(t, primary.answers)
+
+	// Skipping the primary is recorded, as it would be if it had failed.
+	failovers := p.Failovers()
+	require.Len(t, failovers, 1)
+	assert.Equal(t, "gemini", failovers[0].From)
+	assert.Equal(t, "static", failovers[0].To)
+	assert.Equal(t, "unhealthy", failovers[0].Reason)
+	assert.Equal(t, 1, failovers[0].Question)
+}
+
+func TestQuestionProvider_ResumesOnHealthyProvider(t *testing.T) {
+	health := NewHealth(time.Minute, time.Hour)
+	health.Failure("gemini")
+	primary := &fakeProvider{name: "gemini", questions: []string{"one?", "two?"}, failAfter: -1}
+	secondary := &fakeProvider{name: "static", questions: []string{"one?", "two?"}, failAfter: -1}
+	p := New(health, Member{Name: "gemini", Provider: primary}, Member{Name: "static", Provider: secondary})
+
+	transcript := &domain.Transcript{}
+	transcript.Entries = append(transcript.Entries, struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}{Question: "one?", Answer: "yes"})
+	require.NoError(t, p.Resume(transcript))
+
+	assert.Nil(t, primary.resumed)
+	assert.Equal(t, transcript, secondary.resumed)
+	failovers := p.Failovers()
+	require.Len(t, failovers, 1)
+	assert.Equal(t, "gemini", failovers[0].From)
+	assert.Equal(t, "static", failovers[0].To)
+	assert.Equal(t, "unhealthy", failovers[0].Reason)
+	assert.Equal(t, 2, failovers[0].Question)
+}
+
+func TestQuestionProvider_AllProvidersFail(t *testing.T) {
+	health := NewHealth(time.Minute, time.Hour)
+	p := New(health,
+		Member{Name: "gemini", Provider: &fakeProvider{name: "gemini", failAfter: 0}},
+		Member{Name: "openai", Provider: &fakeProvider{name: "openai", failAfter: 0}},
+	)
+
+	_, more, err := p.TryNextQuestion("")
+	assert.False(t, more)
+	assert.ErrorIs(t, err, ErrNoProviders)
+	assert.ErrorContains(t, err, "gemini is down")
+	assert.ErrorContains(t, err, "openai is down")
+}
+
+func TestHealth_Cooldown(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewHealth(time.Minute, 3*time.Minute)
+	h.now = func() time.Time { return now }
+
+	h.Failure("gemini")
+	assert.False(t, h.Healthy("gemini"))
+	now = now.Add(time.Minute)
+	assert.True(t, h.Healthy("gemini"))
+
+	// Consecutive failures back off, up to the maximum.
+	h.Failure("gemini")
+	now = now.Add(time.Minute)
+	assert.False(t, h.Healthy("gemini"))
+	h.Failure("gemini")
+	now = now.Add(3 * time.Minute)
+	assert.True(t, h.Healthy("gemini"))
+
+	h.Success("gemini")
+	h.Failure("gemini")
+	now = now.Add(time.Minute)
+	assert.True(t, h.Healthy("gemini"))
+}
@@ -0,0 +1,75 @@
+package fallback
+
+import (
+	"sync"
+	"time"
+)
+
+// Default cool-down settings for providers that have failed.
+const (
+	DefaultCooldown    = 30 * time.Second
+	DefaultMaxCooldown = 10 * time.Minute
+)
+
+// Health tracks which providers have failed recently, so that they can be skipped. It is safe for concurrent
+// use, and is intended to be shared by every interview in the process.
+type Health struct {
+	mu          sync.Mutex
+	cooldown    time.Duration
+	maxCooldown time.Duration
+	now         func() time.Time
+	status      map[string]*status
+}
+
+// status is the health of a single provider.
+type status struct {
+	failures  int
+	downUntil time.Time
+}
+
+// NewHealth creates a Health tracker. A provider that fails is skipped for the cool-down, which doubles with
+// each consecutive failure up to the maximum.
+func NewHealth(cooldown, maxCooldown time.Duration) *Health {
+	return &Health{
+		cooldown:    cooldown,
+		maxCooldown: maxCooldown,
+		now:         time.Now,
+		status:      make(map[string]*status),
+	}
+}
+
+// Healthy reports whether the named provider should be tried.
+func (h *Health) Healthy(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.status[name]
+	return !ok || !h.now().Before(s.downUntil)
+}
+
+// Failure records that the named provider failed.
+func (h *Health) Failure(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.status[name]
+	if !ok {
+		s = &status{}
+		h.status[name] = s
+	}
+	s.failures++
+
+	d := h.cooldown
+	for i := 1; i < s.failures && d < h.maxCooldown; i++ {
+		d *= 2
+	}
+	if d > h.maxCooldown {
+		d = h.maxCooldown
+	}
+	s.downUntil = h.now().Add(d)
+}
+
+// Success records that the named provider is working.
+func (h *Health) Success(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.status, name)
+}
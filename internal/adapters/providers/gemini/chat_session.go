@@ -9,6 +9,10 @@ import (
 // ChatSession is an interface that wraps the genai.ChatSession.
 type ChatSession interface {
 	SendMessage(ctx context.Context, parts ...genai.Part) (*genai.GenerateContentResponse, error)
+	// History returns the conversation so far.
+	History() []*genai.Content
+	// SetHistory replaces the conversation so far.
+	SetHistory(history []*genai.Content)
 }
 
 // genaiChatSessionWrapper is a concrete implementation of the ChatSession interface
@@ -29,5 +33,15 @@ func (w *genaiChatSessionWrapper) SendMessage(ctx context.Context, parts ...gena
 	return w.session.SendMessage(ctx, parts...)
 }
 
+// History returns the conversation so far.
+func (w *genaiChatSessionWrapper) History() []*genai.Content {
+	return w.session.History
+}
+
+// SetHistory replaces the conversation so far.
+func (w *genaiChatSessionWrapper) SetHistory(history []*genai.Content) {
+	w.session.History = history
+}
+
 // Ensure the wrapper implements the interface
 var _ ChatSession = (*genaiChatSessionWrapper)(nil)
@@ -2,7 +2,11 @@ package gemini
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/andrewhowdencom/vox/internal/config"
@@ -16,16 +20,66 @@ import (
 const InterviewStructure = `You are to ask maximally one question at a time, and then wait for the users response. Then, use the
 users prompt and the information supplied in the context so far to ask the next question.`
 
+// Err* are common errors
+var (
+	ErrUpstreamFailure = errors.New("gemini request failed")
+	ErrNoResponse      = errors.New("no response from Gemini")
+)
+
+// kickoff is sent in place of an answer to prompt the model for its first question.
+const kickoff = "Please begin the interview."
+
+// inlineTypes are the kinds of file Gemini accepts alongside a message. Other files are left out.
+var inlineTypes = []string{"image/", "audio/", "video/", "text/", "application/pdf"}
+
 // QuestionProvider provides questions from the Gemini API.
 type QuestionProvider struct {
 	client         GeminiClient
 	conversational ChatSession
-	questionCount  int
-	maxQuestions   int
+	history        HistoryStrategy
+	historyConfig  struct {
+		strategy string
+		turns    int
+	}
+	generation    config.Generation
+	info          *domain.ProviderInfo
+	questionCount int
+	maxQuestions  int
+	// attachments are sent with the next answer.
+	attachments []domain.Attachment
+}
+
+// Option configures optional behaviour of the QuestionProvider.
+type Option func(*QuestionProvider) error
+
+// WithHistory selects the strategy used to limit the conversation history sent with each turn.
+func WithHistory(strategy string, turns int) Option {
+	return func(p *QuestionProvider) error {
+		p.historyConfig.strategy = strategy
+		p.historyConfig.turns = turns
+		return nil
+	}
+}
+
+// WithGeneration sets the generation and safety settings used when asking questions.
+func WithGeneration(g config.Generation) Option {
+	return func(p *QuestionProvider) error {
+		p.generation = g
+		return nil
+	}
 }
 
 // New creates a new GeminiQuestionProvider.
-func New(cfg *config.Config, model Model, apiKey APIKey, prompt Prompt) (interview.QuestionProvider, error) {
+func New(cfg *config.Config, model Model, apiKey APIKey, prompt Prompt, opts ...Option) (interview.QuestionProvider, error) {
+	p := &QuestionProvider{
+		maxQuestions: 20,
+	}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
+		}
+	}
+
 	ctx := context.Background()
 	httpClient := http.NewClient(cfg.DNSServer)
 	client, err := genai.NewClient(ctx, option.WithAPIKey(string(apiKey)), option.WithHTTPClient(httpClient))
@@ -33,65 +87,186 @@ func New(cfg *config.Config, model Model, apiKey APIKey, prompt Prompt) (intervi
 		return nil, err
 	}
 
-	generativeModel := client.GenerativeModel(string(model))
-	wrappedModel := &generativeModelWrapper{generativeModel}
+	safety, err := safetySettings(p.generation.Safety)
+	if err != nil {
+		return nil, err
+	}
+
+	// The interviewer is configured with the prompt as its system instruction, and the tuned generation
+	// settings. One-off requests, such as summaries, use the model's defaults.
+	interviewer := client.GenerativeModel(string(model))
+	interviewer.SystemInstruction = &genai.Content{
+		Parts: []genai.Part{
+			genai.Text(prompt),
+			genai.Text(InterviewStructure),
+		},
+	}
+	interviewer.SafetySettings = safety
+	if p.generation.Temperature != nil {
+		interviewer.SetTemperature(*p.generation.Temperature)
+	}
+	if p.generation.TopP != nil {
+		interviewer.SetTopP(*p.generation.TopP)
+	}
+	if p.generation.MaxOutputTokens != nil {
+		interviewer.SetMaxOutputTokens(*p.generation.MaxOutputTokens)
+	}
+	interviewer.StopSequences = p.generation.StopSequences
+
+	utility := client.GenerativeModel(string(model))
+	utility.SafetySettings = safety
 
-	// The chat session needs to be initialized with history.
-	// We'll do this by accessing the underlying genai.ChatSession.
-	chat := wrappedModel.GenerativeModel.StartChat()
-	chat.History = []*genai.Content{
-		{
-			Parts: []genai.Part{
-				genai.Text(prompt),
-				genai.Text(InterviewStructure),
-			},
-			Role: "model",
+	p.client = &generativeModelWrapper{utility}
+	p.conversational = NewGenaiChatSessionWrapper(interviewer.StartChat())
+	p.history, err = NewHistoryStrategy(p.historyConfig.strategy, p.historyConfig.turns, p.client)
+	if err != nil {
+		return nil, err
+	}
+
+	p.info = &domain.ProviderInfo{
+		Name:  "gemini",
+		Model: string(model),
+		Generation: &domain.Generation{
+			Temperature:     p.generation.Temperature,
+			TopP:            p.generation.TopP,
+			MaxOutputTokens: p.generation.MaxOutputTokens,
+			StopSequences:   p.generation.StopSequences,
+			Safety:          p.generation.Safety,
 		},
 	}
 
-	// Now, we wrap the initialized chat session.
-	cs := NewGenaiChatSessionWrapper(chat)
+	return p, nil
+}
+
+// Describe reports the model and generation settings used for the interview.
+func (p *QuestionProvider) Describe() *domain.ProviderInfo {
+	return p.info
+}
+
+// safetySettings converts the configured safety thresholds into their Gemini equivalents.
+func safetySettings(safety map[string]string) ([]*genai.SafetySetting, error) {
+	categories := map[string]genai.HarmCategory{
+		"harassment":        genai.HarmCategoryHarassment,
+		"hate_speech":       genai.HarmCategoryHateSpeech,
+		"sexually_explicit": genai.HarmCategorySexuallyExplicit,
+		"dangerous_content": genai.HarmCategoryDangerousContent,
+	}
+	thresholds := map[string]genai.HarmBlockThreshold{
+		"block_none":             genai.HarmBlockNone,
+		"block_only_high":        genai.HarmBlockOnlyHigh,
+		"block_medium_and_above": genai.HarmBlockMediumAndAbove,
+		"block_low_and_above":    genai.HarmBlockLowAndAbove,
+	}
+
+	names := make([]string, 0, len(safety))
+	for name := range safety {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	return &QuestionProvider{
-		client:         wrappedModel,
-		conversational: cs,
-		maxQuestions:   20,
-	}, nil
+	var settings []*genai.SafetySetting
+	for _, name := range names {
+		category, ok := categories[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown safety category %q", name)
+		}
+		threshold, ok := thresholds[strings.ToLower(safety[name])]
+		if !ok {
+			return nil, fmt.Errorf("unknown safety threshold %q", safety[name])
+		}
+		settings = append(settings, &genai.SafetySetting{Category: category, Threshold: threshold})
+	}
+	return settings, nil
 }
 
 // NextQuestion returns the next question from the Gemini API.
 func (p *QuestionProvider) NextQuestion(previousAnswer string) (string, bool) {
-	if p.questionCount >= p.maxQuestions {
+	question, hasMore, err := p.TryNextQuestion(previousAnswer)
+	if err != nil {
+		slog.Error("Error getting next question", "error", err)
 		return "", false
 	}
+	return question, hasMore
+}
+
+// TryNextQuestion returns the next question from the Gemini API, or an error if the API could not provide one.
+func (p *QuestionProvider) TryNextQuestion(previousAnswer string) (string, bool, error) {
+	if p.questionCount >= p.maxQuestions {
+		return "", false, nil
+	}
 
 	ctx := context.Background()
 	var parts []genai.Part
 	if previousAnswer != "" {
 		parts = append(parts, genai.Text(previousAnswer))
+	} else if p.questionCount == 0 {
+		parts = append(parts, genai.Text(kickoff))
+	}
+	parts = append(parts, p.attachmentParts()...)
+	p.attachments = nil
+
+	if p.history != nil {
+		history, err := p.history.Apply(ctx, p.conversational.History())
+		if err != nil {
+			slog.Warn("Could not apply history strategy, sending the full history", "error", err)
+		} else {
+			p.conversational.SetHistory(history)
+		}
 	}
 
 	resp, err := p.conversational.SendMessage(ctx, parts...)
 	if err != nil {
-		fmt.Println("Error getting next question:", err)
-		return "", false
+		return "", false, fmt.Errorf("%w: %w", ErrUpstreamFailure, err)
 	}
 
-	if len(resp.Candidates) > 0 {
+	if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
 		content := resp.Candidates[0].Content
 		if len(content.Parts) > 0 {
 			if text, ok := content.Parts[0].(genai.Text); ok {
 				question := string(text)
 				if strings.Contains(question, "INTERVIEW_COMPLETE") {
-					return "", false
+					return "", false, nil
 				}
 				p.questionCount++
-				return question, true
+				return question, true, nil
 			}
 		}
 	}
 
-	return "", false
+	return "", false, ErrNoResponse
+}
+
+// ReceiveAttachments sends the files, such as screenshots, to the model with the next answer.
+func (p *QuestionProvider) ReceiveAttachments(attachments []domain.Attachment) {
+	p.attachments = append(p.attachments, attachments...)
+}
+
+// attachmentParts returns the files waiting to be sent that Gemini accepts.
+func (p *QuestionProvider) attachmentParts() []genai.Part {
+	var parts []genai.Part
+	for _, attachment := range p.attachments {
+		if !slices.ContainsFunc(inlineTypes, func(prefix string) bool { return strings.HasPrefix(attachment.MimeType, prefix) }) {
+			slog.Debug("Leaving out attachment Gemini doesn't accept", "name", attachment.Name, "mime_type", attachment.MimeType)
+			continue
+		}
+		parts = append(parts, genai.Blob{MIMEType: attachment.MimeType, Data: attachment.Data})
+	}
+	return parts
+}
+
+// Resume rebuilds the conversation from a transcript, so the interview can continue with this provider.
+func (p *QuestionProvider) Resume(transcript *domain.Transcript) error {
+	history := []*genai.Content{genai.NewUserContent(genai.Text(kickoff))}
+	for i, entry := range transcript.Entries {
+		history = append(history, &genai.Content{Role: "model", Parts: []genai.Part{genai.Text(entry.Question)}})
+		// The last answer is sent with the next call to NextQuestion.
+		if i < len(transcript.Entries)-1 {
+			history = append(history, genai.NewUserContent(genai.Text(entry.Answer)))
+		}
+	}
+	p.conversational.SetHistory(history)
+	p.questionCount = len(transcript.Entries)
+	return nil
 }
 
 // Summarize generates a summary of the interview transcript.
@@ -117,5 +292,37 @@ func (p *QuestionProvider) Summarize(transcript *domain.Transcript) (string, err
 	return fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0]), nil
 }
 
+// Rephrase rewrites a question to address the supplied guidance, without changing what it asks about.
+func (p *QuestionProvider) Rephrase(question, guidance string) (string, error) {
+	prompt := fmt.Sprintf(`Rewrite the following interview question so that it avoids these problems:
+
+%s
+Keep the intent of the question the same. Respond with only the rewritten question.
+
+%s`, guidance, question)
+
+	resp, err := p.client.GenerateContent(context.Background(), genai.Text(prompt))
+	if err != nil {
+		return "", fmt.Errorf("could not rephrase question: %w", err)
+	}
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no rephrase response from Gemini")
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])), nil
+}
+
+// ReplaceQuestion replaces the last question in the conversation, such as with a rephrased version of it.
+func (p *QuestionProvider) ReplaceQuestion(question string) {
+	history := p.conversational.History()
+	if n := len(history); n > 0 && history[n-1].Role == "model" {
+		history[n-1] = &genai.Content{Role: "model", Parts: []genai.Part{genai.Text(question)}}
+		p.conversational.SetHistory(history)
+	}
+}
+
 var _ interview.QuestionProvider = (*QuestionProvider)(nil)
 var _ interview.Summarizer = (*QuestionProvider)(nil)
+var _ interview.Describer = (*QuestionProvider)(nil)
+var _ interview.FallibleQuestionProvider = (*QuestionProvider)(nil)
+var _ interview.Resumer = (*QuestionProvider)(nil)
@@ -35,6 +35,14 @@ func (m *MockChatSession) SendMessage(ctx context.Context, parts ...genai.Part)
 	return args.Get(0).(*genai.GenerateContentResponse), args.Error(1)
 }
 
+func (m *MockChatSession) History() []*genai.Content {
+	args := m.Called()
+	return args.Get(0).([]*genai.Content)
+}
+
+func (m *MockChatSession) SetHistory(history []*genai.Content) {
+	m.Called(history)
+}
 
 func TestGeminiQuestionProvider_Summarize(t *testing.T) {
 	// Create a mock GeminiClient
@@ -81,3 +89,60 @@ func TestGeminiQuestionProvider_Summarize(t *testing.T) {
 	// Assert that the mock client's GenerateContent method was called
 	mockClient.AssertExpectations(t)
 }
+
+func TestGeminiQuestionProvider_Rephrase(t *testing.T) {
+	mockClient := new(MockGeminiClient)
+	provider := &QuestionProvider{
+		client: mockClient,
+	}
+
+	mockResponse := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{
+			{
+				Content: &genai.Content{
+					Parts: []genai.Part{
+						genai.Text("How often do you deploy?\n"),
+					},
+				},
+			},
+		},
+	}
+	mockClient.On("GenerateContent", mock.Anything, mock.Anything).Return(mockResponse, nil)
+
+	question, err := provider.Rephrase("Do you deploy daily?", "- closed: can be answered with yes or no")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "How often do you deploy?", question)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGeminiQuestionProvider_Attachments(t *testing.T) {
+	session := new(MockChatSession)
+	provider := &QuestionProvider{
+		conversational: session,
+		maxQuestions:   20,
+		questionCount:  1,
+	}
+
+	mockResponse := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{
+			{Content: &genai.Content{Parts: []genai.Part{genai.Text("What stands out on it?")}}},
+		},
+	}
+	session.On("SendMessage", mock.Anything, []genai.Part{
+		genai.Text("(attached dashboard.png, data.zip)"),
+		genai.Blob{MIMEType: "image/png", Data: []byte("\x89PNG")},
+	}).Return(mockResponse, nil).Once()
+
+	provider.ReceiveAttachments([]domain.Attachment{
+		{Name: "dashboard.png", MimeType: "image/png", Data: []byte("\x89PNG")},
+		{Name: "data.zip", MimeType: "application/zip", Data: []byte("PK")},
+	})
+	question, hasMore, err := provider.TryNextQuestion("(attached dashboard.png, data.zip)")
+
+	assert.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, "What stands out on it?", question)
+	assert.Empty(t, provider.attachments)
+	session.AssertExpectations(t)
+}
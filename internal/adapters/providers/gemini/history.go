@@ -0,0 +1,163 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// Names of the available history strategies.
+const (
+	HistoryFull              = "full"
+	HistorySlidingWindow     = "sliding-window"
+	HistorySummarizingWindow = "summarizing-window"
+)
+
+// DefaultHistoryTurns is the number of recent turns kept by the windowed strategies if none is configured.
+const DefaultHistoryTurns = 10
+
+// ErrUnknownHistoryStrategy is returned when a history strategy is not recognised.
+var ErrUnknownHistoryStrategy = errors.New("unknown history strategy")
+
+// HistoryStrategy decides which parts of the conversation history are sent to the model with each turn.
+type HistoryStrategy interface {
+	// Apply returns the history to use for the next turn. The interviewer's instructions and research goals are
+	// held in the system instruction rather than the history, so they are always kept.
+	Apply(ctx context.Context, history []*genai.Content) ([]*genai.Content, error)
+}
+
+// NewHistoryStrategy creates the named history strategy. The client is used by strategies that summarise
+// older turns.
+func NewHistoryStrategy(name string, turns int, client GeminiClient) (HistoryStrategy, error) {
+	if turns <= 0 {
+		turns = DefaultHistoryTurns
+	}
+	switch strings.ToLower(name) {
+	case "", HistoryFull:
+		return FullHistory{}, nil
+	case HistorySlidingWindow:
+		return SlidingWindow{Turns: turns}, nil
+	case HistorySummarizingWindow:
+		return &SummarizingWindow{Window: SlidingWindow{Turns: turns}, Client: client}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHistoryStrategy, name)
+	}
+}
+
+// FullHistory sends the entire history with every turn.
+type FullHistory struct{}
+
+// Apply returns the history unchanged.
+func (FullHistory) Apply(_ context.Context, history []*genai.Content) ([]*genai.Content, error) {
+	return history, nil
+}
+
+// SlidingWindow keeps the most recent turns, and drops everything before them.
+type SlidingWindow struct {
+	// Turns is the number of question and answer exchanges to keep.
+	Turns int
+}
+
+// Apply returns the most recent turns.
+func (w SlidingWindow) Apply(_ context.Context, history []*genai.Content) ([]*genai.Content, error) {
+	_, window := w.split(history)
+	return window, nil
+}
+
+// split divides the history into the entries that have fallen out of the window, and the window itself. The
+// window always starts on a user turn so the roles continue to alternate.
+func (w SlidingWindow) split(history []*genai.Content) (overflow, window []*genai.Content) {
+	cut := len(history) - w.Turns*2
+	if cut <= 0 {
+		return nil, history
+	}
+	for cut < len(history) && history[cut].Role != "user" {
+		cut++
+	}
+	return history[:cut], history[cut:]
+}
+
+// SummarizingWindow behaves like SlidingWindow, but rather than dropping older turns it folds them into a
+// rolling summary that is sent ahead of them.
+type SummarizingWindow struct {
+	Window SlidingWindow
+	Client GeminiClient
+
+	summary string
+	// sent is the summary entry last returned by Apply, which the history starts with on the next turn.
+	sent *genai.Content
+}
+
+// Apply returns the rolling summary, if there is one, followed by the most recent turns.
+func (s *SummarizingWindow) Apply(ctx context.Context, history []*genai.Content) ([]*genai.Content, error) {
+	// Set aside the summary from a previous turn, so it is not summarised again.
+	if s.sent != nil && len(history) > 0 && history[0] == s.sent {
+		history = history[1:]
+	}
+
+	overflow, window := s.Window.split(history)
+	if len(overflow) == 0 {
+		return append(s.head(), window...), nil
+	}
+
+	summary, err := s.summarize(ctx, overflow)
+	if err != nil {
+		// Fall back to a plain sliding window, keeping the previous summary.
+		slog.Warn("Could not summarise conversation history", "error", err)
+	} else {
+		s.summary = summary
+	}
+
+	return append(s.head(), window...), nil
+}
+
+// head returns the rolling summary as an entry of its own, or nothing if there isn't one yet.
+func (s *SummarizingWindow) head() []*genai.Content {
+	if s.summary == "" {
+		return nil
+	}
+	s.sent = &genai.Content{Role: "model", Parts: []genai.Part{genai.Text("Summary of the conversation so far:\n" + s.summary)}}
+	return []*genai.Content{s.sent}
+}
+
+// summarize folds the given turns into the rolling summary.
+func (s *SummarizingWindow) summarize(ctx context.Context, turns []*genai.Content) (string, error) {
+	var b strings.Builder
+	if s.summary != "" {
+		fmt.Fprintf(&b, "Summary so far:\n%s\n\n", s.summary)
+	}
+	b.WriteString("Conversation:\n")
+	for _, c := range turns {
+		for _, p := range c.Parts {
+			if text, ok := p.(genai.Text); ok {
+				fmt.Fprintf(&b, "%s: %s\n", c.Role, text)
+			}
+		}
+	}
+
+	prompt := fmt.Sprintf(`Update the summary of this interview with the conversation below. Keep every fact the
+participant has shared about their problem, and note which topics have already been covered. Respond with only
+the summary.
+
+%s`, b.String())
+
+	resp, err := s.Client.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no summary response from Gemini")
+	}
+	return fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0]), nil
+}
+
+// Ensure the strategies implement the interface.
+var (
+	_ HistoryStrategy = FullHistory{}
+	_ HistoryStrategy = SlidingWindow{}
+	_ HistoryStrategy = (*SummarizingWindow)(nil)
+)
@@ -0,0 +1,164 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// conversation builds a history of the given number of turns.
+func conversation(turns int) []*genai.Content {
+	var history []*genai.Content
+	for i := 1; i <= turns; i++ {
+		history = append(history,
+			&genai.Content{Role: "user", Parts: []genai.Part{genai.Text(fmt.Sprintf("answer %d", i))}},
+			&genai.Content{Role: "model", Parts: []genai.Part{genai.Text(fmt.Sprintf("question %d", i))}},
+		)
+	}
+	return history
+}
+
+func textResponse(text string) *genai.GenerateContentResponse {
+	return &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{Content: &genai.Content{Parts: []genai.Part{genai.Text(text)}}}},
+	}
+}
+
+// nextQuestionWithStrategy runs a single NextQuestion against the given history, and returns the history
+// that the provider set on the chat session before sending the message.
+func nextQuestionWithStrategy(t *testing.T, strategy HistoryStrategy, history []*genai.Content) []*genai.Content {
+	mockSession := new(MockChatSession)
+	provider := &QuestionProvider{
+		conversational: mockSession,
+		history:        strategy,
+		maxQuestions:   20,
+	}
+
+	var applied []*genai.Content
+	mockSession.On("History").Return(history)
+	mockSession.On("SetHistory", mock.Anything).Run(func(args mock.Arguments) {
+		applied = args.Get(0).([]*genai.Content)
+	})
+	mockSession.On("SendMessage", mock.Anything, mock.Anything).Return(textResponse("What next?"), nil)
+
+	question, hasMore := provider.NextQuestion("an answer")
+	require.True(t, hasMore)
+	assert.Equal(t, "What next?", question)
+	mockSession.AssertExpectations(t)
+
+	return applied
+}
+
+func TestFullHistory(t *testing.T) {
+	history := conversation(5)
+	applied := nextQuestionWithStrategy(t, FullHistory{}, history)
+	assert.Equal(t, history, applied)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Run("should keep everything within the window", func(t *testing.T) {
+		history := conversation(2)
+		applied := nextQuestionWithStrategy(t, SlidingWindow{Turns: 3}, history)
+		assert.Equal(t, history, applied)
+	})
+
+	t.Run("should keep the most recent turns", func(t *testing.T) {
+		history := conversation(5)
+		applied := nextQuestionWithStrategy(t, SlidingWindow{Turns: 2}, history)
+
+		require.Len(t, applied, 4)
+		assert.Equal(t, history[6:], applied)
+		assert.Equal(t, "user", applied[0].Role)
+	})
+}
+
+func TestSummarizingWindow(t *testing.T) {
+	t.Run("should fold older turns into a summary ahead of the most recent turns", func(t *testing.T) {
+		mockClient := new(MockGeminiClient)
+		mockClient.On("GenerateContent", mock.Anything, mock.Anything).Return(textResponse("They deploy weekly."), nil)
+		strategy := &SummarizingWindow{Window: SlidingWindow{Turns: 2}, Client: mockClient}
+
+		history := conversation(5)
+		applied := nextQuestionWithStrategy(t, strategy, history)
+
+		require.Len(t, applied, 5)
+		assert.Equal(t, "model", applied[0].Role)
+		assert.Equal(t, []genai.Part{genai.Text("Summary of the conversation so far:\nThey deploy weekly.")}, applied[0].Parts)
+		assert.Equal(t, history[6:], applied[1:])
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should roll the previous summary into the next one", func(t *testing.T) {
+		mockClient := new(MockGeminiClient)
+		mockClient.On("GenerateContent", mock.Anything, mock.Anything).Return(textResponse("first"), nil).Once()
+		mockClient.On("GenerateContent", mock.Anything, mock.MatchedBy(func(parts []genai.Part) bool {
+			return len(parts) == 1 && containsText(parts[0], "Summary so far:\nfirst")
+		})).Return(textResponse("second"), nil).Once()
+		strategy := &SummarizingWindow{Window: SlidingWindow{Turns: 1}, Client: mockClient}
+
+		applied := nextQuestionWithStrategy(t, strategy, conversation(3))
+		applied = append(applied, conversation(4)[6:]...)
+		applied = nextQuestionWithStrategy(t, strategy, applied)
+
+		require.Len(t, applied, 3)
+		assert.Equal(t, []genai.Part{genai.Text("Summary of the conversation so far:\nsecond")}, applied[0].Parts)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should fall back to a sliding window if summarising fails", func(t *testing.T) {
+		mockClient := new(MockGeminiClient)
+		mockClient.On("GenerateContent", mock.Anything, mock.Anything).Return((*genai.GenerateContentResponse)(nil), errors.New("quota exceeded"))
+		strategy := &SummarizingWindow{Window: SlidingWindow{Turns: 2}, Client: mockClient}
+
+		history := conversation(5)
+		applied := nextQuestionWithStrategy(t, strategy, history)
+
+		assert.Equal(t, history[6:], applied)
+	})
+}
+
+func TestSummarizingWindow_Apply(t *testing.T) {
+	mockClient := new(MockGeminiClient)
+	mockClient.On("GenerateContent", mock.Anything, mock.Anything).Return(textResponse("They deploy weekly."), nil)
+	strategy := &SummarizingWindow{Window: SlidingWindow{Turns: 1}, Client: mockClient}
+
+	history := conversation(3)
+	applied, err := strategy.Apply(context.Background(), history)
+	require.NoError(t, err)
+
+	require.Len(t, applied, 3)
+	assert.Equal(t, "model", applied[0].Role)
+	assert.Equal(t, []genai.Part{genai.Text("Summary of the conversation so far:\nThey deploy weekly.")}, applied[0].Parts)
+	assert.Equal(t, history[4:], applied[1:])
+
+	// The summary is not summarised again while the window has room.
+	applied, err = strategy.Apply(context.Background(), applied)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	mockClient.AssertNumberOfCalls(t, "GenerateContent", 1)
+}
+
+func TestNewHistoryStrategy(t *testing.T) {
+	s, err := NewHistoryStrategy("", 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, FullHistory{}, s)
+
+	s, err = NewHistoryStrategy("sliding-window", 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SlidingWindow{Turns: DefaultHistoryTurns}, s)
+
+	_, err = NewHistoryStrategy("everything-ever", 0, nil)
+	assert.ErrorIs(t, err, ErrUnknownHistoryStrategy)
+}
+
+func containsText(part genai.Part, s string) bool {
+	text, ok := part.(genai.Text)
+	return ok && strings.Contains(string(text), s)
+}
@@ -0,0 +1,247 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/andrewhowdencom/vox/internal/http"
+)
+
+// InterviewStructure is appended to the prompt to describe how the interview should be conducted.
+const InterviewStructure = `You are to ask maximally one question at a time, and then wait for the users response. Then, use the
+users prompt and the information supplied in the context so far to ask the next question. When the interview is
+over, respond with INTERVIEW_COMPLETE.`
+
+// kickoff is sent in place of an answer to prompt the model for its first question.
+const kickoff = "Please begin the interview."
+
+// requestTimeout bounds each request to the API. Local models can be slow to respond.
+const requestTimeout = 60 * time.Second
+
+// Err* are common errors
+var (
+	ErrUpstreamFailure = errors.New("openai-compatible request failed")
+	ErrNoResponse      = errors.New("no response from the model")
+)
+
+// message is a single message in a chat completion request.
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// completionRequest is the body of a chat completion request.
+type completionRequest struct {
+	Model       string    `json:"model"`
+	Messages    []message `json:"messages"`
+	Temperature *float32  `json:"temperature,omitempty"`
+	TopP        *float32  `json:"top_p,omitempty"`
+	MaxTokens   *int32    `json:"max_tokens,omitempty"`
+	Stop        []string  `json:"stop,omitempty"`
+}
+
+// completionResponse is the part of a chat completion response that the provider uses.
+type completionResponse struct {
+	Choices []struct {
+		Message message `json:"message"`
+	} `json:"choices"`
+}
+
+// QuestionProvider provides questions from any API compatible with the OpenAI chat completions API, such as
+// a locally hosted model.
+type QuestionProvider struct {
+	client        *nethttp.Client
+	baseURL       string
+	model         string
+	apiKey        string
+	generation    config.Generation
+	messages      []message
+	questionCount int
+	maxQuestions  int
+}
+
+// New creates a new QuestionProvider for an OpenAI-compatible API.
+func New(cfg *config.Config, baseURL BaseURL, model Model, apiKey APIKey, prompt Prompt, generation config.Generation) (*QuestionProvider, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("base-url is required for the openai provider")
+	}
+
+	return &QuestionProvider{
+		client:     http.NewClient(cfg.DNSServer),
+		baseURL:    strings.TrimSuffix(string(baseURL), "/"),
+		model:      string(model),
+		apiKey:     string(apiKey),
+		generation: generation,
+		messages: []message{
+			{Role: "system", Content: fmt.Sprintf("%s\n\n%s", prompt, InterviewStructure)},
+		},
+		maxQuestions: 20,
+	}, nil
+}
+
+// NextQuestion returns the next question from the model.
+func (p *QuestionProvider) NextQuestion(previousAnswer string) (string, bool) {
+	question, hasMore, err := p.TryNextQuestion(previousAnswer)
+	if err != nil {
+		slog.Error("Error getting next question", "error", err)
+		return "", false
+	}
+	return question, hasMore
+}
+
+// TryNextQuestion returns the next question from the model, or an error if the model could not provide one.
+func (p *QuestionProvider) TryNextQuestion(previousAnswer string) (string, bool, error) {
+	if p.questionCount >= p.maxQuestions {
+		return "", false, nil
+	}
+
+	content := previousAnswer
+	if content == "" && p.questionCount == 0 {
+		content = kickoff
+	}
+	messages := append(p.messages, message{Role: "user", Content: content})
+
+	question, err := p.complete(messages, p.generation)
+	if err != nil {
+		return "", false, err
+	}
+	if strings.Contains(question, "INTERVIEW_COMPLETE") {
+		return "", false, nil
+	}
+
+	p.messages = append(messages, message{Role: "assistant", Content: question})
+	p.questionCount++
+	return question, true, nil
+}
+
+// Resume rebuilds the conversation from a transcript, so the interview can continue with this provider.
+func (p *QuestionProvider) Resume(transcript *domain.Transcript) error {
+	messages := []message{p.messages[0], {Role: "user", Content: kickoff}}
+	for i, entry := range transcript.Entries {
+		messages = append(messages, message{Role: "assistant", Content: entry.Question})
+		// The last answer is sent with the next call to NextQuestion.
+		if i < len(transcript.Entries)-1 {
+			messages = append(messages, message{Role: "user", Content: entry.Answer})
+		}
+	}
+	p.messages = messages
+	p.questionCount = len(transcript.Entries)
+	return nil
+}
+
+// Summarize generates a summary of the interview transcript.
+func (p *QuestionProvider) Summarize(transcript *domain.Transcript) (string, error) {
+	var transcriptText string
+	for _, entry := range transcript.Entries {
+		transcriptText += fmt.Sprintf("Q: %s\nA: %s\n\n", entry.Question, entry.Answer)
+	}
+
+	prompt := fmt.Sprintf("Please summarize the following interview transcript:\n\n%s", transcriptText)
+	summary, err := p.complete([]message{{Role: "user", Content: prompt}}, config.Generation{})
+	if err != nil {
+		return "", fmt.Errorf("could not generate summary: %w", err)
+	}
+	return summary, nil
+}
+
+// Rephrase rewrites a question to address the supplied guidance, without changing what it asks about.
+func (p *QuestionProvider) Rephrase(question, guidance string) (string, error) {
+	prompt := fmt.Sprintf(`Rewrite the following interview question so that it avoids these problems:
+
+%s
+Keep the intent of the question the same. Respond with only the rewritten question.
+
+%s`, guidance, question)
+
+	rephrased, err := p.complete([]message{{Role: "user", Content: prompt}}, config.Generation{})
+	if err != nil {
+		return "", fmt.Errorf("could not rephrase question: %w", err)
+	}
+	return strings.TrimSpace(rephrased), nil
+}
+
+// ReplaceQuestion replaces the last question in the conversation, such as with a rephrased version of it.
+func (p *QuestionProvider) ReplaceQuestion(question string) {
+	if n := len(p.messages); n > 0 && p.messages[n-1].Role == "assistant" {
+		p.messages[n-1].Content = question
+	}
+}
+
+// Describe reports the model and generation settings used for the interview.
+func (p *QuestionProvider) Describe() *domain.ProviderInfo {
+	return &domain.ProviderInfo{
+		Name:  "openai",
+		Model: p.model,
+		Generation: &domain.Generation{
+			Temperature:     p.generation.Temperature,
+			TopP:            p.generation.TopP,
+			MaxOutputTokens: p.generation.MaxOutputTokens,
+			StopSequences:   p.generation.StopSequences,
+		},
+	}
+}
+
+// complete sends a chat completion request and returns the content of the first choice.
+func (p *QuestionProvider) complete(messages []message, generation config.Generation) (string, error) {
+	body, err := json.Marshal(completionRequest{
+		Model:       p.model,
+		Messages:    messages,
+		Temperature: generation.Temperature,
+		TopP:        generation.TopP,
+		MaxTokens:   generation.MaxOutputTokens,
+		Stop:        generation.StopSequences,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not marshal request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUpstreamFailure, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("%w: %s: %s", ErrUpstreamFailure, resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	var completion completionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("%w: could not decode response: %w", ErrUpstreamFailure, err)
+	}
+	if len(completion.Choices) == 0 || completion.Choices[0].Message.Content == "" {
+		return "", ErrNoResponse
+	}
+
+	return completion.Choices[0].Message.Content, nil
+}
+
+// Ensure QuestionProvider implements the domain interfaces.
+var _ interview.QuestionProvider = (*QuestionProvider)(nil)
+var _ interview.FallibleQuestionProvider = (*QuestionProvider)(nil)
+var _ interview.Resumer = (*QuestionProvider)(nil)
+var _ interview.Describer = (*QuestionProvider)(nil)
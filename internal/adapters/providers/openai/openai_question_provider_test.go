@@ -0,0 +1,118 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer starts a fake chat completions API that replies with each response in turn, and records the
+// requests it receives.
+func newServer(t *testing.T, status int, responses ...string) (*httptest.Server, *[]completionRequest) {
+	t.Helper()
+	var requests []completionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var req completionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		if status != http.StatusOK {
+			http.Error(w, "overloaded", status)
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%q}}]}`, responses[len(requests)-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestQuestionProvider_NextQuestion(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK, "What do you do?", "Why?", "INTERVIEW_COMPLETE")
+	temperature := float32(0.3)
+	p, err := New(&config.Config{}, BaseURL(server.URL+"/"), "llama3", "test-key", "Be kind.", config.Generation{Temperature: &temperature})
+	require.NoError(t, err)
+
+	q, more := p.NextQuestion("")
+	assert.True(t, more)
+	assert.Equal(t, "What do you do?", q)
+
+	q, more = p.NextQuestion("I build things.")
+	assert.True(t, more)
+	assert.Equal(t, "Why?", q)
+
+	_, more = p.NextQuestion("Fun.")
+	assert.False(t, more)
+
+	require.Len(t, *requests, 3)
+	second := (*requests)[1]
+	assert.Equal(t, "llama3", second.Model)
+	assert.Equal(t, float32(0.3), *second.Temperature)
+	require.Len(t, second.Messages, 4)
+	assert.Equal(t, "system", second.Messages[0].Role)
+	assert.Contains(t, second.Messages[0].Content, "Be kind.")
+	assert.Equal(t, message{Role: "user", Content: kickoff}, second.Messages[1])
+	assert.Equal(t, message{Role: "assistant", Content: "What do you do?"}, second.Messages[2])
+	assert.Equal(t, message{Role: "user", Content: "I build things."}, second.Messages[3])
+}
+
+func TestQuestionProvider_TryNextQuestionError(t *testing.T) {
+	server, _ := newServer(t, http.StatusServiceUnavailable)
+	p, err := New(&config.Config{}, BaseURL(server.URL), "llama3", "test-key", "", config.Generation{})
+	require.NoError(t, err)
+
+	_, more, err := p.TryNextQuestion("")
+	assert.False(t, more)
+	assert.ErrorIs(t, err, ErrUpstreamFailure)
+	assert.ErrorContains(t, err, "overloaded")
+}
+
+func TestQuestionProvider_Resume(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK, "And then?")
+	p, err := New(&config.Config{}, BaseURL(server.URL), "llama3", "test-key", "", config.Generation{})
+	require.NoError(t, err)
+
+	transcript := &domain.Transcript{}
+	transcript.Entries = append(transcript.Entries, struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}{Question: "What do you do?", Answer: "I build things."})
+	require.NoError(t, p.Resume(transcript))
+
+	q, more := p.NextQuestion("I build things.")
+	assert.True(t, more)
+	assert.Equal(t, "And then?", q)
+
+	messages := (*requests)[0].Messages
+	require.Len(t, messages, 4)
+	assert.Equal(t, message{Role: "assistant", Content: "What do you do?"}, messages[2])
+	assert.Equal(t, message{Role: "user", Content: "I build things."}, messages[3])
+}
+
+func TestQuestionProvider_ReplaceQuestion(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK, "Do you deploy daily?", "Why?")
+	p, err := New(&config.Config{}, BaseURL(server.URL), "llama3", "test-key", "", config.Generation{})
+	require.NoError(t, err)
+
+	_, _ = p.NextQuestion("")
+	p.ReplaceQuestion("How often do you deploy?")
+	_, _ = p.NextQuestion("Weekly.")
+
+	messages := (*requests)[1].Messages
+	require.Len(t, messages, 4)
+	assert.Equal(t, message{Role: "assistant", Content: "How often do you deploy?"}, messages[2])
+}
+
+func TestNew_RequiresBaseURL(t *testing.T) {
+	_, err := New(&config.Config{}, "", "llama3", "", "", config.Generation{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,13 @@
+package openai
+
+// BaseURL is a type for the base URL of an OpenAI-compatible API, such as "http://localhost:11434/v1".
+type BaseURL string
+
+// Model is a type for the model name.
+type Model string
+
+// APIKey is a type for the API key. Local models often do not need one.
+type APIKey string
+
+// Prompt is a type for the interview prompt.
+type Prompt string
@@ -9,6 +9,7 @@ import (
 type QuestionProvider struct {
 	questions    []string
 	currentIndex int
+	asked        map[string]bool
 }
 
 // New creates a new StaticQuestionProvider.
@@ -21,17 +22,44 @@ func New(questions []string) *QuestionProvider {
 // NextQuestion returns the next question from the predefined list.
 // It returns the question and a boolean indicating if there are more questions.
 func (p *QuestionProvider) NextQuestion(previousAnswer string) (string, bool) {
-	if p.currentIndex < len(p.questions) {
+	for p.currentIndex < len(p.questions) {
 		question := p.questions[p.currentIndex]
 		p.currentIndex++
+		if p.asked[question] {
+			continue
+		}
 		return question, true
 	}
 	return "", false
 }
 
+// Resume skips any questions that have already been asked in the transcript. This works both when restoring
+// a static interview, and when taking over from another provider that asked different questions.
+func (p *QuestionProvider) Resume(transcript *domain.Transcript) error {
+	p.asked = make(map[string]bool, len(transcript.Entries))
+	for _, entry := range transcript.Entries {
+		p.asked[entry.Question] = true
+	}
+	p.currentIndex = 0
+	return nil
+}
+
 // Ensure QuestionProvider implements the domain interface.
 var _ interview.QuestionProvider = (*QuestionProvider)(nil)
 var _ interview.Summarizer = (*QuestionProvider)(nil)
+var _ interview.Describer = (*QuestionProvider)(nil)
+var _ interview.Resumer = (*QuestionProvider)(nil)
+var _ interview.QuestionCounter = (*QuestionProvider)(nil)
+
+// QuestionCount returns the number of questions in the list.
+func (p *QuestionProvider) QuestionCount() int {
+	return len(p.questions)
+}
+
+// Describe reports the provider used for the interview.
+func (p *QuestionProvider) Describe() *domain.ProviderInfo {
+	return &domain.ProviderInfo{Name: "static"}
+}
 
 // Summarize returns an empty string, as static interviews do not have summaries.
 func (p *QuestionProvider) Summarize(transcript *domain.Transcript) (string, error) {
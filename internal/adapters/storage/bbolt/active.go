@@ -0,0 +1,53 @@
+package bbolt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"go.etcd.io/bbolt"
+)
+
+// SaveActiveInterview saves an interview in progress to the database.
+func (r *bboltRepository) SaveActiveInterview(active *domain.ActiveInterview) error {
+	buf, err := json.Marshal(active)
+	if err != nil {
+		return fmt.Errorf("could not marshal active interview: %w", err)
+	}
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(activeInterviewsBucket).Put([]byte(active.ID), buf); err != nil {
+			return fmt.Errorf("could not save active interview: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListActiveInterviews retrieves every interview in progress from the database.
+func (r *bboltRepository) ListActiveInterviews() ([]*domain.ActiveInterview, error) {
+	var actives []*domain.ActiveInterview
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(activeInterviewsBucket).ForEach(func(k, v []byte) error {
+			var active domain.ActiveInterview
+			if err := json.Unmarshal(v, &active); err != nil {
+				return fmt.Errorf("could not unmarshal active interview data: %w", err)
+			}
+			actives = append(actives, &active)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return actives, nil
+}
+
+// DeleteActiveInterview removes an interview in progress from the database.
+func (r *bboltRepository) DeleteActiveInterview(id string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(activeInterviewsBucket).Delete([]byte(id))
+	})
+}
+
+// Ensure the repository implements the domain interface.
+var _ storage.ActiveInterviewRepository = (*bboltRepository)(nil)
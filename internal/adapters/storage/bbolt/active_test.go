@@ -0,0 +1,50 @@
+package bbolt
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltRepository_ActiveInterviews(t *testing.T) {
+	f, err := os.CreateTemp("", "test.db")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	repo, err := NewTestRepository(f.Name())
+	require.NoError(t, err)
+	defer repo.Close()
+
+	active := &domain.ActiveInterview{
+		ID:        "C123/1700000000.000100",
+		TopicID:   "feedback",
+		UserID:    "U123",
+		ChannelID: "C123",
+		ThreadTS:  "1700000000.000100",
+		Observers: []string{"D999"},
+		StartedAt: time.Now().UTC(),
+	}
+	require.NoError(t, repo.SaveActiveInterview(active))
+
+	active.Transcript.Entries = append(active.Transcript.Entries, struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}{Question: "What do you like?", Answer: "The speed."})
+	active.UpdatedAt = time.Now().UTC()
+	require.NoError(t, repo.SaveActiveInterview(active))
+
+	actives, err := repo.ListActiveInterviews()
+	require.NoError(t, err)
+	require.Len(t, actives, 1)
+	assert.Equal(t, active, actives[0])
+
+	require.NoError(t, repo.DeleteActiveInterview(active.ID))
+	require.NoError(t, repo.DeleteActiveInterview("missing"))
+	actives, err = repo.ListActiveInterviews()
+	require.NoError(t, err)
+	assert.Empty(t, actives)
+}
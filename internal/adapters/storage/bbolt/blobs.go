@@ -0,0 +1,38 @@
+package bbolt
+
+import (
+	"fmt"
+
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// SaveBlob saves the content of a file to the database.
+func (r *bboltRepository) SaveBlob(data []byte) (string, error) {
+	id := uuid.New().String()
+	return id, r.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(blobsBucket).Put([]byte(id), data); err != nil {
+			return fmt.Errorf("could not save blob: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetBlob retrieves the content of a file from the database.
+func (r *bboltRepository) GetBlob(id string) ([]byte, error) {
+	var data []byte
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(blobsBucket).Get([]byte(id))
+		if v == nil {
+			return fmt.Errorf("blob %w", storage.ErrNotFound)
+		}
+		// The value is only valid for the life of the transaction.
+		data = append([]byte(nil), v...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
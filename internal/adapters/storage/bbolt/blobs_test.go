@@ -0,0 +1,59 @@
+package bbolt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltRepository_Blobs(t *testing.T) {
+	f, err := os.CreateTemp("", "test.db")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	repo, err := NewTestRepository(f.Name())
+	require.NoError(t, err)
+	defer repo.Close()
+
+	blobID, err := repo.SaveBlob([]byte("\x89PNG"))
+	require.NoError(t, err)
+	data, err := repo.GetBlob(blobID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x89PNG"), data)
+
+	_, err = repo.GetBlob("missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	t.Run("should delete the attachments with the interview", func(t *testing.T) {
+		transcript := &domain.Transcript{Attachments: []domain.Attachment{{Name: "dashboard.png", MimeType: "image/png", Size: 4, BlobID: blobID}}}
+		id, err := repo.SaveInterview(&domain.Interview{}, transcript, &domain.Summary{})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteInterview(id))
+		_, err = repo.GetBlob(blobID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("should keep the attachments of a resumed interview when the abandoned one is deleted", func(t *testing.T) {
+		blobID, err := repo.SaveBlob([]byte("\x89PNG"))
+		require.NoError(t, err)
+		transcript := &domain.Transcript{Attachments: []domain.Attachment{{Name: "dashboard.png", MimeType: "image/png", Size: 4, BlobID: blobID}}}
+		abandoned, err := repo.SaveInterview(&domain.Interview{Abandoned: true}, transcript, &domain.Summary{})
+		require.NoError(t, err)
+		resumed, err := repo.SaveInterview(&domain.Interview{}, transcript, &domain.Summary{})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteInterview(abandoned))
+		data, err := repo.GetBlob(blobID)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("\x89PNG"), data)
+
+		require.NoError(t, repo.DeleteInterview(resumed))
+		_, err = repo.GetBlob(blobID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -0,0 +1,50 @@
+package bbolt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"go.etcd.io/bbolt"
+)
+
+// SaveInstallation saves a Slack installation to the database.
+func (r *bboltRepository) SaveInstallation(installation *domain.Installation) error {
+	buf, err := json.Marshal(installation)
+	if err != nil {
+		return fmt.Errorf("could not marshal installation: %w", err)
+	}
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(installationsBucket).Put([]byte(installation.TeamID), buf); err != nil {
+			return fmt.Errorf("could not save installation: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetInstallation retrieves the installation for a Slack workspace from the database.
+func (r *bboltRepository) GetInstallation(teamID string) (*domain.Installation, error) {
+	var installation domain.Installation
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(installationsBucket).Get([]byte(teamID))
+		if v == nil {
+			return fmt.Errorf("installation %w", storage.ErrNotFound)
+		}
+		if err := json.Unmarshal(v, &installation); err != nil {
+			return fmt.Errorf("could not unmarshal installation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &installation, nil
+}
+
+// DeleteInstallation removes the installation for a Slack workspace from the database.
+func (r *bboltRepository) DeleteInstallation(teamID string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(installationsBucket).Delete([]byte(teamID))
+	})
+}
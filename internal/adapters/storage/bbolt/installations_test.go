@@ -0,0 +1,41 @@
+package bbolt
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltRepository_Installations(t *testing.T) {
+	f, err := os.CreateTemp("", "test.db")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	repo, err := NewTestRepository(f.Name())
+	require.NoError(t, err)
+	defer repo.Close()
+
+	installation := &domain.Installation{
+		TeamID:      "T123",
+		TeamName:    "Acme",
+		BotUserID:   "U0BOT",
+		BotToken:    "sealed-token",
+		InstalledBy: "U123",
+		InstalledAt: time.Now().UTC(),
+	}
+	require.NoError(t, repo.SaveInstallation(installation))
+
+	got, err := repo.GetInstallation("T123")
+	require.NoError(t, err)
+	assert.Equal(t, installation, got)
+
+	require.NoError(t, repo.DeleteInstallation("T123"))
+	require.NoError(t, repo.DeleteInstallation("T123"))
+	_, err = repo.GetInstallation("T123")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
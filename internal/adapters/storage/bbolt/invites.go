@@ -0,0 +1,100 @@
+package bbolt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"go.etcd.io/bbolt"
+)
+
+// SaveInvite saves an invite to the database.
+func (r *bboltRepository) SaveInvite(invite *domain.Invite) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return putInvite(tx, invite)
+	})
+}
+
+// GetInvite retrieves an invite from the database.
+func (r *bboltRepository) GetInvite(id string) (*domain.Invite, error) {
+	var invite *domain.Invite
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		invite, err = getInvite(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// ListInvites retrieves every invite from the database.
+func (r *bboltRepository) ListInvites() ([]*domain.Invite, error) {
+	var invites []*domain.Invite
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(invitesBucket).ForEach(func(k, v []byte) error {
+			var invite domain.Invite
+			if err := json.Unmarshal(v, &invite); err != nil {
+				return fmt.Errorf("could not unmarshal invite data: %w", err)
+			}
+			invites = append(invites, &invite)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// UpdateInvite applies the update to an invite within a single transaction, so concurrent updates can't
+// both see the same state.
+func (r *bboltRepository) UpdateInvite(id string, update func(invite *domain.Invite) error) (*domain.Invite, error) {
+	var invite *domain.Invite
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		invite, err = getInvite(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := update(invite); err != nil {
+			return err
+		}
+		return putInvite(tx, invite)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// getInvite reads an invite within a transaction.
+func getInvite(tx *bbolt.Tx, id string) (*domain.Invite, error) {
+	v := tx.Bucket(invitesBucket).Get([]byte(id))
+	if v == nil {
+		return nil, fmt.Errorf("invite %w", storage.ErrNotFound)
+	}
+	var invite domain.Invite
+	if err := json.Unmarshal(v, &invite); err != nil {
+		return nil, fmt.Errorf("could not unmarshal invite: %w", err)
+	}
+	return &invite, nil
+}
+
+// putInvite writes an invite within a transaction.
+func putInvite(tx *bbolt.Tx, invite *domain.Invite) error {
+	buf, err := json.Marshal(invite)
+	if err != nil {
+		return fmt.Errorf("could not marshal invite: %w", err)
+	}
+	if err := tx.Bucket(invitesBucket).Put([]byte(invite.ID), buf); err != nil {
+		return fmt.Errorf("could not save invite: %w", err)
+	}
+	return nil
+}
+
+// Ensure the repository implements the domain interfaces.
+var _ storage.Repository = (*bboltRepository)(nil)
+var _ storage.InviteRepository = (*bboltRepository)(nil)
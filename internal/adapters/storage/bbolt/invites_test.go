@@ -0,0 +1,68 @@
+package bbolt
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltRepository_Invites(t *testing.T) {
+	f, err := os.CreateTemp("", "test.db")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	repo, err := NewTestRepository(f.Name())
+	require.NoError(t, err)
+	defer repo.Close()
+
+	invite := &domain.Invite{
+		ID:         "invite-1",
+		TopicID:    "feedback",
+		Attributes: map[string]string{"company": "Acme"},
+		ExpiresAt:  time.Now().Add(time.Hour).UTC(),
+		MaxUses:    1,
+	}
+	require.NoError(t, repo.SaveInvite(invite))
+
+	got, err := repo.GetInvite("invite-1")
+	require.NoError(t, err)
+	assert.Equal(t, invite, got)
+
+	_, err = repo.GetInvite("missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	t.Run("should save successful updates", func(t *testing.T) {
+		updated, err := repo.UpdateInvite("invite-1", func(i *domain.Invite) error {
+			i.Uses++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated.Uses)
+
+		got, err := repo.GetInvite("invite-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Uses)
+	})
+
+	t.Run("should discard failed updates", func(t *testing.T) {
+		_, err := repo.UpdateInvite("invite-1", func(i *domain.Invite) error {
+			i.Uses++
+			return errors.New("used up")
+		})
+		assert.EqualError(t, err, "used up")
+
+		got, err := repo.GetInvite("invite-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Uses)
+	})
+
+	invites, err := repo.ListInvites()
+	require.NoError(t, err)
+	assert.Len(t, invites, 1)
+}
@@ -0,0 +1,39 @@
+package bbolt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"go.etcd.io/bbolt"
+)
+
+// SaveOptOut records that a phone number has opted out, with when it did.
+func (r *bboltRepository) SaveOptOut(number string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(optOutsBucket).Put([]byte(number), []byte(time.Now().UTC().Format(time.RFC3339))); err != nil {
+			return fmt.Errorf("could not save opt out: %w", err)
+		}
+		return nil
+	})
+}
+
+// IsOptedOut reports whether a phone number has opted out.
+func (r *bboltRepository) IsOptedOut(number string) (bool, error) {
+	var optedOut bool
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		optedOut = tx.Bucket(optOutsBucket).Get([]byte(number)) != nil
+		return nil
+	})
+	return optedOut, err
+}
+
+// DeleteOptOut removes the opt out of a phone number from the database.
+func (r *bboltRepository) DeleteOptOut(number string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(optOutsBucket).Delete([]byte(number))
+	})
+}
+
+// Ensure the repository implements the domain interface.
+var _ storage.OptOutRepository = (*bboltRepository)(nil)
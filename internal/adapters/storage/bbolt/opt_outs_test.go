@@ -0,0 +1,34 @@
+package bbolt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltRepository_OptOuts(t *testing.T) {
+	f, err := os.CreateTemp("", "test.db")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	repo, err := NewTestRepository(f.Name())
+	require.NoError(t, err)
+	defer repo.Close()
+
+	optedOut, err := repo.IsOptedOut("+15550100")
+	require.NoError(t, err)
+	assert.False(t, optedOut)
+
+	require.NoError(t, repo.SaveOptOut("+15550100"))
+	optedOut, err = repo.IsOptedOut("+15550100")
+	require.NoError(t, err)
+	assert.True(t, optedOut)
+
+	require.NoError(t, repo.DeleteOptOut("+15550100"))
+	require.NoError(t, repo.DeleteOptOut("+15550100"))
+	optedOut, err = repo.IsOptedOut("+15550100")
+	require.NoError(t, err)
+	assert.False(t, optedOut)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/adrg/xdg"
 	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
 	"github.com/google/uuid"
 	"go.etcd.io/bbolt"
 )
@@ -16,11 +17,29 @@ type bboltRepository struct {
 }
 
 var (
-	interviewsBucket = []byte("interviews")
-	transcriptsBucket = []byte("transcripts")
-	summariesBucket   = []byte("summaries")
+	interviewsBucket       = []byte("interviews")
+	transcriptsBucket      = []byte("transcripts")
+	summariesBucket        = []byte("summaries")
+	invitesBucket          = []byte("invites")
+	activeInterviewsBucket = []byte("active_interviews")
+	summaryCardsBucket     = []byte("summary_cards")
+	installationsBucket    = []byte("installations")
+	blobsBucket            = []byte("blobs")
+	optOutsBucket          = []byte("opt_outs")
 )
 
+// createBuckets creates every bucket used by the repository, if they don't already exist.
+func createBuckets(db *bbolt.DB) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{interviewsBucket, transcriptsBucket, summariesBucket, invitesBucket, activeInterviewsBucket, summaryCardsBucket, installationsBucket, blobsBucket, optOutsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // NewRepository creates a new bbolt repository, opening the database file
 // at the appropriate XDG data path.
 func NewRepository() (*bboltRepository, error) {
@@ -35,19 +54,7 @@ func NewRepository() (*bboltRepository, error) {
 	}
 
 	// Create buckets if they don't exist
-	err = db.Update(func(tx *bbolt.Tx) error {
-		if _, err := tx.CreateBucketIfNotExists(interviewsBucket); err != nil {
-			return err
-		}
-		if _, err := tx.CreateBucketIfNotExists(transcriptsBucket); err != nil {
-			return err
-		}
-		if _, err := tx.CreateBucketIfNotExists(summariesBucket); err != nil {
-			return err
-		}
-		return nil
-	})
-	if err != nil {
+	if err := createBuckets(db); err != nil {
 		return nil, fmt.Errorf("could not create buckets: %w", err)
 	}
 
@@ -109,7 +116,7 @@ func (r *bboltRepository) GetInterview(id string) (*domain.Interview, error) {
 		b := tx.Bucket(interviewsBucket)
 		v := b.Get([]byte(id))
 		if v == nil {
-			return fmt.Errorf("interview not found")
+			return fmt.Errorf("interview %w", storage.ErrNotFound)
 		}
 		if err := json.Unmarshal(v, &interview); err != nil {
 			return fmt.Errorf("could not unmarshal interview: %w", err)
@@ -129,7 +136,7 @@ func (r *bboltRepository) GetTranscript(interviewID string) (*domain.Transcript,
 		b := tx.Bucket(transcriptsBucket)
 		v := b.Get([]byte(interviewID))
 		if v == nil {
-			return fmt.Errorf("transcript not found")
+			return fmt.Errorf("transcript %w", storage.ErrNotFound)
 		}
 		if err := json.Unmarshal(v, &transcript); err != nil {
 			return fmt.Errorf("could not unmarshal transcript: %w", err)
@@ -149,7 +156,7 @@ func (r *bboltRepository) GetSummary(interviewID string) (*domain.Summary, error
 		b := tx.Bucket(summariesBucket)
 		v := b.Get([]byte(interviewID))
 		if v == nil {
-			return fmt.Errorf("summary not found")
+			return fmt.Errorf("summary %w", storage.ErrNotFound)
 		}
 		if err := json.Unmarshal(v, &summary); err != nil {
 			return fmt.Errorf("could not unmarshal summary: %w", err)
@@ -182,3 +189,91 @@ func (r *bboltRepository) ListInterviews() ([]*domain.Interview, error) {
 	}
 	return interviews, nil
 }
+
+// UpdateInterview applies the update to the stored interview metadata within a single transaction.
+func (r *bboltRepository) UpdateInterview(id string, update func(interview *domain.Interview) error) (*domain.Interview, error) {
+	var interview domain.Interview
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(interviewsBucket)
+		v := b.Get([]byte(id))
+		if v == nil {
+			return fmt.Errorf("interview %w", storage.ErrNotFound)
+		}
+		if err := json.Unmarshal(v, &interview); err != nil {
+			return fmt.Errorf("could not unmarshal interview: %w", err)
+		}
+		if err := update(&interview); err != nil {
+			return err
+		}
+		buf, err := json.Marshal(&interview)
+		if err != nil {
+			return fmt.Errorf("could not marshal interview: %w", err)
+		}
+		if err := b.Put([]byte(id), buf); err != nil {
+			return fmt.Errorf("could not save interview: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &interview, nil
+}
+
+// DeleteInterview removes an interview, its transcript, its summary and its attachments from the database.
+// Attachments are kept if another transcript still has them, as a resumed interview does.
+func (r *bboltRepository) DeleteInterview(id string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket(interviewsBucket).Get([]byte(id)) == nil {
+			return fmt.Errorf("interview %w", storage.ErrNotFound)
+		}
+		blobIDs, err := attachedBlobs(tx.Bucket(transcriptsBucket).Get([]byte(id)))
+		if err != nil {
+			return err
+		}
+		if len(blobIDs) > 0 {
+			err := tx.Bucket(transcriptsBucket).ForEach(func(k, v []byte) error {
+				if string(k) == id {
+					return nil
+				}
+				shared, err := attachedBlobs(v)
+				for blobID := range shared {
+					delete(blobIDs, blobID)
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for blobID := range blobIDs {
+			if err := tx.Bucket(blobsBucket).Delete([]byte(blobID)); err != nil {
+				return fmt.Errorf("could not delete attachment: %w", err)
+			}
+		}
+		for _, name := range [][]byte{interviewsBucket, transcriptsBucket, summariesBucket} {
+			if err := tx.Bucket(name).Delete([]byte(id)); err != nil {
+				return fmt.Errorf("could not delete interview: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// attachedBlobs returns the IDs of the blobs holding the attachments in an encoded transcript.
+func attachedBlobs(v []byte) (map[string]bool, error) {
+	blobIDs := make(map[string]bool)
+	if v == nil {
+		return blobIDs, nil
+	}
+	var transcript domain.Transcript
+	if err := json.Unmarshal(v, &transcript); err != nil {
+		return nil, fmt.Errorf("could not unmarshal transcript: %w", err)
+	}
+	for _, attachment := range transcript.Attachments {
+		if attachment.BlobID != "" {
+			blobIDs[attachment.BlobID] = true
+		}
+	}
+	return blobIDs, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/bbolt"
@@ -63,6 +64,42 @@ func TestBoltRepository_SaveAndGetInterview(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, id, retrievedSummary.InterviewID)
 	assert.Equal(t, summary.Text, retrievedSummary.Text)
+
+	// Missing records are reported as not found
+	_, err = repo.GetInterview("missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = repo.GetTranscript("missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = repo.GetSummary("missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Updates are saved, unless they fail
+	updated, err := repo.UpdateInterview(id, func(interview *domain.Interview) error {
+		interview.Triage = append(interview.Triage, domain.Triage{Label: "reviewed", UserID: "UPM"})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, updated.Triage, 1)
+	_, err = repo.UpdateInterview(id, func(interview *domain.Interview) error {
+		interview.Triage = nil
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	retrievedInterview, err = repo.GetInterview(id)
+	require.NoError(t, err)
+	assert.Equal(t, updated.Triage, retrievedInterview.Triage)
+	_, err = repo.UpdateInterview("missing", func(*domain.Interview) error { return nil })
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Deleting the interview removes every part of it
+	require.NoError(t, repo.DeleteInterview(id))
+	_, err = repo.GetInterview(id)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = repo.GetTranscript(id)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = repo.GetSummary(id)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, repo.DeleteInterview(id), storage.ErrNotFound)
 }
 
 // NewTestRepository creates a new repository using a temporary file path.
@@ -72,19 +109,7 @@ func NewTestRepository(path string) (*bboltRepository, error) {
 		return nil, err
 	}
 
-	err = db.Update(func(tx *bbolt.Tx) error {
-		if _, err := tx.CreateBucketIfNotExists(interviewsBucket); err != nil {
-			return err
-		}
-		if _, err := tx.CreateBucketIfNotExists(transcriptsBucket); err != nil {
-			return err
-		}
-		if _, err := tx.CreateBucketIfNotExists(summariesBucket); err != nil {
-			return err
-		}
-		return nil
-	})
-	if err != nil {
+	if err := createBuckets(db); err != nil {
 		return nil, err
 	}
 
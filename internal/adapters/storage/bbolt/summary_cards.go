@@ -0,0 +1,48 @@
+package bbolt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"go.etcd.io/bbolt"
+)
+
+// summaryCardKey identifies a summary card by the message it was posted in.
+func summaryCardKey(channelID, timestamp string) []byte {
+	return []byte(channelID + "/" + timestamp)
+}
+
+// SaveSummaryCard saves a summary card to the database.
+func (r *bboltRepository) SaveSummaryCard(card *domain.SummaryCard) error {
+	buf, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("could not marshal summary card: %w", err)
+	}
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(summaryCardsBucket).Put(summaryCardKey(card.ChannelID, card.Timestamp), buf); err != nil {
+			return fmt.Errorf("could not save summary card: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetSummaryCard retrieves the summary card posted in a message from the database.
+func (r *bboltRepository) GetSummaryCard(channelID, timestamp string) (*domain.SummaryCard, error) {
+	var card domain.SummaryCard
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(summaryCardsBucket).Get(summaryCardKey(channelID, timestamp))
+		if v == nil {
+			return fmt.Errorf("summary card %w", storage.ErrNotFound)
+		}
+		if err := json.Unmarshal(v, &card); err != nil {
+			return fmt.Errorf("could not unmarshal summary card: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
@@ -0,0 +1,31 @@
+package bbolt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltRepository_SummaryCards(t *testing.T) {
+	f, err := os.CreateTemp("", "test.db")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	repo, err := NewTestRepository(f.Name())
+	require.NoError(t, err)
+	defer repo.Close()
+
+	card := &domain.SummaryCard{InterviewID: "interview-1", ChannelID: "C123", Timestamp: "1700000000.000100"}
+	require.NoError(t, repo.SaveSummaryCard(card))
+
+	got, err := repo.GetSummaryCard("C123", "1700000000.000100")
+	require.NoError(t, err)
+	assert.Equal(t, card, got)
+
+	_, err = repo.GetSummaryCard("C456", "1700000000.000100")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,103 @@
+// Package chat provides the plumbing shared by the UIs that hold interviews in chat tools, such as Teams or
+// text messages, where the participant's answers arrive separately from the questions they answer and the
+// interview can be cancelled from elsewhere.
+package chat
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// CancelledText tells the participant their interview was cancelled.
+const CancelledText = "This interview was cancelled, and your answers have been discarded."
+
+// Cancellation lets an interview be cancelled while a question is waiting for an answer. Embed it in a UI to
+// give it a Cancel method.
+type Cancellation struct {
+	cancelled chan struct{}
+	once      sync.Once
+	// silent is set when the interview is cancelled without telling the participant.
+	silent bool
+}
+
+// NewCancellation creates a Cancellation for an interview that hasn't been cancelled.
+func NewCancellation() *Cancellation {
+	return &Cancellation{cancelled: make(chan struct{})}
+}
+
+// Cancel ends the interview without saving it. A question waiting for an answer returns straight away, and
+// any later question isn't asked.
+func (c *Cancellation) Cancel() {
+	c.once.Do(func() { close(c.cancelled) })
+}
+
+// CancelSilently cancels the interview like Cancel, but without telling the participant, such as when they
+// have opted out of messages.
+func (c *Cancellation) CancelSilently() {
+	c.once.Do(func() {
+		c.silent = true
+		close(c.cancelled)
+	})
+}
+
+// Cancelled returns a channel that is closed once the interview is cancelled.
+func (c *Cancellation) Cancelled() <-chan struct{} {
+	return c.cancelled
+}
+
+// EndCancelled tells the participant the interview was cancelled with say, unless it was cancelled silently,
+// and returns interview.ErrCancelled. A failure to tell them is logged with the attributes.
+func (c *Cancellation) EndCancelled(say func(text string) error, attrs ...any) error {
+	if c.silent {
+		return interview.ErrCancelled
+	}
+	if err := say(CancelledText); err != nil {
+		slog.Error("Error ending cancelled interview", append([]any{"error", err}, attrs...)...)
+	}
+	return interview.ErrCancelled
+}
+
+// Inbox passes the participant's messages to the question waiting for an answer, until the interview is
+// cancelled or closed. Embed it in a UI to give it Answer, Cancel and Close methods.
+type Inbox struct {
+	*Cancellation
+
+	answers   chan string
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewInbox creates an Inbox for an interview that is waiting for its first answer.
+func NewInbox() *Inbox {
+	return &Inbox{
+		Cancellation: NewCancellation(),
+		answers:      make(chan string),
+		closed:       make(chan struct{}),
+	}
+}
+
+// Answer passes a message from the participant to the question waiting for an answer, or to the next question
+// to be asked. It reports false if the interview is cancelled or closed before the message is taken.
+func (i *Inbox) Answer(text string) bool {
+	select {
+	case i.answers <- strings.TrimSpace(text):
+		return true
+	case <-i.cancelled:
+		return false
+	case <-i.closed:
+		return false
+	}
+}
+
+// Answers returns the channel the participant's messages are passed on, as given to Answer.
+func (i *Inbox) Answers() <-chan string {
+	return i.answers
+}
+
+// Close marks the interview as finished, so messages still waiting to be passed to it are dropped.
+func (i *Inbox) Close() {
+	i.closeOnce.Do(func() { close(i.closed) })
+}
@@ -0,0 +1,63 @@
+package chat_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/chat"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancellation_EndCancelled(t *testing.T) {
+	t.Run("should tell the participant", func(t *testing.T) {
+		c := chat.NewCancellation()
+		c.Cancel()
+		c.Cancel()
+		<-c.Cancelled()
+
+		var said []string
+		err := c.EndCancelled(func(text string) error {
+			said = append(said, text)
+			return nil
+		})
+		assert.ErrorIs(t, err, interview.ErrCancelled)
+		assert.Equal(t, []string{chat.CancelledText}, said)
+	})
+
+	t.Run("should not tell the participant if cancelled silently", func(t *testing.T) {
+		c := chat.NewCancellation()
+		c.CancelSilently()
+		c.Cancel()
+		<-c.Cancelled()
+
+		err := c.EndCancelled(func(string) error { return errors.New("should not be called") })
+		assert.ErrorIs(t, err, interview.ErrCancelled)
+	})
+}
+
+func TestInbox_Answer(t *testing.T) {
+	t.Run("should pass the answer to the question waiting for it", func(t *testing.T) {
+		inbox := chat.NewInbox()
+		go func() { assert.True(t, inbox.Answer("  The speed.  ")) }()
+
+		select {
+		case answer := <-inbox.Answers():
+			assert.Equal(t, "The speed.", answer)
+		case <-time.After(time.Second):
+			assert.Fail(t, "the answer was not passed on")
+		}
+	})
+
+	t.Run("should drop the answer once the interview is cancelled or closed", func(t *testing.T) {
+		cancelled := chat.NewInbox()
+		cancelled.Cancel()
+		assert.False(t, cancelled.Answer("The speed."))
+
+		closed := chat.NewInbox()
+		closed.Close()
+		closed.Close()
+		assert.False(t, closed.Answer("The speed."))
+	})
+}
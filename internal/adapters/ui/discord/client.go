@@ -0,0 +1,169 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPIURL is the address of the Discord REST API.
+	DefaultAPIURL = "https://discord.com/api/v10"
+	// maxRetries is the number of times a request is retried after Discord rate limits it.
+	maxRetries = 3
+	// threadArchiveMinutes is how long a thread with no activity stays open, which is the longest Discord
+	// allows.
+	threadArchiveMinutes = 10080
+)
+
+// ErrAPI is returned when the Discord API rejects a request.
+var ErrAPI = errors.New("discord api request failed")
+
+// Message is a message sent by the bot.
+type Message struct {
+	Content    string      `json:"content"`
+	Components []Component `json:"components,omitempty"`
+	// AllowedMentions limits who is notified by mentions in the content. Only the users listed are.
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+}
+
+// AllowedMentions lists the users that can be notified by mentions in a message.
+type AllowedMentions struct {
+	Parse []string `json:"parse"`
+	Users []string `json:"users,omitempty"`
+}
+
+// Command is a slash command registered for the application.
+type Command struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        int       `json:"type,omitempty"`
+	Required    bool      `json:"required,omitempty"`
+	Options     []Command `json:"options,omitempty"`
+}
+
+// Types of slash command option.
+const (
+	OptionSubcommand = 1
+	OptionString     = 3
+	OptionBoolean    = 5
+)
+
+// Messenger sends messages to Discord channels.
+type Messenger interface {
+	CreateMessage(channelID string, message Message) (string, error)
+}
+
+// Client calls the Discord REST API as the bot.
+type Client struct {
+	token      string
+	apiURL     string
+	httpClient *http.Client
+	// after waits for the duration, and is replaced in tests.
+	after func(time.Duration) <-chan time.Time
+}
+
+// ClientOption configures optional behaviour of a Client.
+type ClientOption func(*Client)
+
+// WithAPIURL calls the API at the URL instead of DefaultAPIURL, such as a local stand-in.
+func WithAPIURL(apiURL string) ClientOption {
+	return func(c *Client) {
+		c.apiURL = strings.TrimSuffix(apiURL, "/")
+	}
+}
+
+// NewClient creates a client that authenticates with the bot token.
+func NewClient(token string, opts ...ClientOption) *Client {
+	c := &Client{
+		token:      token,
+		apiURL:     DefaultAPIURL,
+		httpClient: http.DefaultClient,
+		after:      time.After,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CreateDM opens a direct message channel with the user, returning its ID.
+func (c *Client) CreateDM(userID string) (string, error) {
+	var channel struct {
+		ID string `json:"id"`
+	}
+	err := c.do(http.MethodPost, "/users/@me/channels", map[string]string{"recipient_id": userID}, &channel)
+	return channel.ID, err
+}
+
+// StartThread starts a public thread with the name in the channel, returning its ID.
+func (c *Client) StartThread(channelID, name string) (string, error) {
+	var thread struct {
+		ID string `json:"id"`
+	}
+	body := map[string]any{"name": name, "type": 11, "auto_archive_duration": threadArchiveMinutes}
+	err := c.do(http.MethodPost, "/channels/"+channelID+"/threads", body, &thread)
+	return thread.ID, err
+}
+
+// CreateMessage sends the message to the channel, returning its ID.
+func (c *Client) CreateMessage(channelID string, message Message) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	err := c.do(http.MethodPost, "/channels/"+channelID+"/messages", message, &created)
+	return created.ID, err
+}
+
+// RegisterCommands replaces the application's global slash commands with the commands.
+func (c *Client) RegisterCommands(applicationID string, commands []Command) error {
+	return c.do(http.MethodPut, "/applications/"+applicationID+"/commands", commands, nil)
+}
+
+// do sends the request with the body encoded as JSON, and decodes the response into out if it is set. Requests
+// that are rate limited are retried once Discord says they can be.
+func (c *Client) do(method, path string, body, out any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not encode request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, c.apiURL+path, bytes.NewReader(encoded))
+		if err != nil {
+			return fmt.Errorf("could not create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bot "+c.token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("could not call discord: %w", err)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			resp.Body.Close()
+			wait, _ := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+			<-c.after(time.Duration(wait * float64(time.Second)))
+			continue
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("%w: %s %s: %s", ErrAPI, method, path, resp.Status)
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("could not decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+// Ensure Client implements Messenger.
+var _ Messenger = (*Client)(nil)
@@ -0,0 +1,290 @@
+package discord
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/chat"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// Prefixes of the custom IDs of the buttons posted with each question, and of the form used to answer it.
+// Each custom ID is followed by the question's number, so buttons on earlier questions are ignored, and a
+// choice's custom ID by its position.
+const (
+	ActionAnswer = "vox_answer"
+	ActionChoice = "vox_choice"
+	ActionSkip   = "vox_skip"
+	ActionWrapUp = "vox_wrap_up"
+	FormAnswer   = "vox_form"
+)
+
+// FieldAnswer is the custom ID of the text field in the form used to answer a question.
+const FieldAnswer = "answer"
+
+const (
+	// maxContentLength is the longest message Discord accepts.
+	maxContentLength = 2000
+	// maxLabelLength is the longest button label Discord accepts.
+	maxLabelLength = 80
+)
+
+// action is the outcome of a button pressed or form submitted by the participant.
+type action struct {
+	answer string
+	err    error
+}
+
+// UI handles the user interface for an interview in Discord, held in a direct message or a thread.
+type UI struct {
+	Client    Messenger
+	ChannelID string
+	// UserID is the participant. Only their button presses and answers are taken.
+	UserID string
+
+	*chat.Cancellation
+
+	actions chan action
+
+	mu sync.Mutex
+	// asked counts the questions posted, so each can be given its own custom IDs.
+	asked int
+	// waiting is the number of the question waiting for an answer, or zero if there is none.
+	waiting int
+	// choices are the choices offered by the question waiting for an answer.
+	choices []string
+}
+
+// New creates a UI for an interview with the user in the channel.
+func New(client Messenger, channelID, userID string) *UI {
+	return &UI{
+		Client:       client,
+		ChannelID:    channelID,
+		UserID:       userID,
+		actions:      make(chan action, 1),
+		Cancellation: chat.NewCancellation(),
+	}
+}
+
+// Say sends a message to the participant that doesn't need an answer.
+func (u *UI) Say(text string) error {
+	for _, content := range split(text, maxContentLength) {
+		if _, err := u.Client.CreateMessage(u.ChannelID, Message{Content: content, AllowedMentions: u.mentions()}); err != nil {
+			return fmt.Errorf("failed to send message to discord: %w", err)
+		}
+	}
+	return nil
+}
+
+// mentions only lets the participant be notified by mentions in the bot's messages.
+func (u *UI) mentions() *AllowedMentions {
+	return &AllowedMentions{Parse: []string{}, Users: []string{u.UserID}}
+}
+
+// Ask sends a question to the participant in Discord and waits for their answer.
+func (u *UI) Ask(question string) (string, error) {
+	return u.AskQuestion(interview.Question{Text: question})
+}
+
+// AskQuestion sends a question to the participant in Discord, with buttons to answer it, pick one of its choices,
+// skip it or wrap up the interview, and waits for them to press one.
+func (u *UI) AskQuestion(question interview.Question) (string, error) {
+	select {
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	default:
+	}
+
+	// The question waits for an answer before it is sent, so a button pressed as soon as it appears is taken.
+	u.mu.Lock()
+	u.asked++
+	number := u.asked
+	u.waiting = number
+	u.choices = question.Choices
+	u.mu.Unlock()
+	defer func() {
+		u.mu.Lock()
+		u.waiting = 0
+		u.mu.Unlock()
+	}()
+
+	slog.Debug("Asking question on discord", "channel_id", u.ChannelID, "user_id", u.UserID, "question", question.Text)
+	message := questionMessage(number, question)
+	message.AllowedMentions = u.mentions()
+	if _, err := u.Client.CreateMessage(u.ChannelID, message); err != nil {
+		slog.Error("Failed to send message to discord", "error", err, "channel_id", u.ChannelID, "user_id", u.UserID)
+		return "", fmt.Errorf("failed to send message to discord: %w", err)
+	}
+
+	select {
+	case a := <-u.actions:
+		slog.Debug("Received answer from user", "channel_id", u.ChannelID, "user_id", u.UserID, "answer", a.answer, "error", a.err)
+		return a.answer, a.err
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	}
+}
+
+// questionMessage builds the message for a question: the question itself and its progress through the
+// interview, with a button to answer it, a button for each of its choices, and buttons to skip it or wrap up.
+func questionMessage(number int, question interview.Question) Message {
+	content := question.Text
+	if question.Number > 0 {
+		progress := fmt.Sprintf("Question %d", question.Number)
+		if question.Total > 0 {
+			progress += fmt.Sprintf(" of %d", question.Total)
+		}
+		content += "\n-# " + progress
+	}
+
+	var rows []Component
+	// Discord allows five buttons in a row, and five rows in a message, so the controls take the last row.
+	var choices []Component
+	for i, choice := range question.Choices {
+		if i == 20 {
+			break
+		}
+		choices = append(choices, button(ButtonPrimary, choice, fmt.Sprintf("%s:%d:%d", ActionChoice, number, i)))
+		if len(choices) == 5 {
+			rows = append(rows, Component{Type: ComponentActionRow, Components: choices})
+			choices = nil
+		}
+	}
+	if len(choices) > 0 {
+		rows = append(rows, Component{Type: ComponentActionRow, Components: choices})
+	}
+	rows = append(rows, Component{Type: ComponentActionRow, Components: []Component{
+		button(ButtonPrimary, "Answer", fmt.Sprintf("%s:%d", ActionAnswer, number)),
+		button(ButtonSecondary, "Skip", fmt.Sprintf("%s:%d", ActionSkip, number)),
+		button(ButtonSecondary, "Wrap up", fmt.Sprintf("%s:%d", ActionWrapUp, number)),
+	}})
+	return Message{Content: truncate(content, maxContentLength), Components: rows}
+}
+
+// button creates a button with the label, shortened to fit.
+func button(style int, label, customID string) Component {
+	return Component{Type: ComponentButton, Style: style, Label: truncate(label, maxLabelLength), CustomID: customID}
+}
+
+// AnswerForm returns the form that opens when the participant presses the button to answer a question, or
+// false if the custom ID isn't for that button.
+func AnswerForm(customID string) (*InteractionResponse, bool) {
+	number, ok := strings.CutPrefix(customID, ActionAnswer+":")
+	if !ok {
+		return nil, false
+	}
+	return &InteractionResponse{Type: ResponseModal, Data: &ResponseData{
+		CustomID: FormAnswer + ":" + number,
+		Title:    "Your answer",
+		Components: []Component{{Type: ComponentActionRow, Components: []Component{{
+			Type:      ComponentTextInput,
+			Style:     TextParagraph,
+			Label:     "Answer",
+			CustomID:  FieldAnswer,
+			Required:  true,
+			MaxLength: 4000,
+		}}}},
+	}}, true
+}
+
+// HandleInteraction handles a button pressed on a question, or the form submitted to answer it. It reports
+// whether the interaction was for the question waiting for an answer; interactions with earlier questions are
+// ignored. Pressing the button to answer a question only opens the form, so it isn't handled here.
+func (u *UI) HandleInteraction(interaction Interaction) bool {
+	if interaction.Data == nil || interaction.UserID() != u.UserID {
+		return false
+	}
+	parts := strings.Split(interaction.Data.CustomID, ":")
+	if len(parts) < 2 {
+		return false
+	}
+	number, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.waiting == 0 || number != u.waiting {
+		return false
+	}
+
+	var a action
+	switch parts[0] {
+	case FormAnswer:
+		a.answer = strings.TrimSpace(interaction.FormValue(FieldAnswer))
+	case ActionChoice:
+		if len(parts) != 3 {
+			return false
+		}
+		i, err := strconv.Atoi(parts[2])
+		if err != nil || i < 0 || i >= len(u.choices) {
+			return false
+		}
+		a.answer = u.choices[i]
+	case ActionSkip:
+		a.err = interview.ErrSkipped
+	case ActionWrapUp:
+		a.err = interview.ErrStopped
+	default:
+		return false
+	}
+
+	// Only the first answer counts.
+	u.waiting = 0
+	u.actions <- a
+	return true
+}
+
+// Close marks the interview as finished, so buttons on its questions are ignored.
+func (u *UI) Close() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.waiting = 0
+}
+
+// endCancelled tells the participant the interview was cancelled.
+func (u *UI) endCancelled() error {
+	return u.EndCancelled(u.Say, "channel_id", u.ChannelID, "user_id", u.UserID)
+}
+
+// DisplaySummary sends the interview summary to the participant in Discord.
+func (u *UI) DisplaySummary(summary string) {
+	if summary == "" {
+		return
+	}
+	if err := u.Say("**Interview Summary**\n" + summary); err != nil {
+		slog.Error("Error displaying summary", "error", err, "channel_id", u.ChannelID, "user_id", u.UserID)
+	}
+}
+
+// truncate shortens the text to at most n characters.
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// split splits the text into parts of at most n characters, breaking at a new line where it can.
+func split(text string, n int) []string {
+	var parts []string
+	for utf8.RuneCountInString(text) > n {
+		cut := len(string([]rune(text)[:n]))
+		if i := strings.LastIndex(text[:cut], "\n"); i > 0 {
+			cut = i
+		}
+		parts = append(parts, text[:cut])
+		text = strings.TrimLeft(text[cut:], "\n")
+	}
+	return append(parts, text)
+}
+
+// Ensure UI implements the domain interface.
+var _ interview.InterviewUI = (*UI)(nil)
+var _ interview.QuestionUI = (*UI)(nil)
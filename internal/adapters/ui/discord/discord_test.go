@@ -0,0 +1,195 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI is a Discord API that records the messages sent to each channel. The first limited requests are
+// rate limited.
+type fakeAPI struct {
+	mu       sync.Mutex
+	limited  int
+	requests int
+	messages map[string][]Message
+}
+
+func newFakeAPI(t *testing.T, limited int) (*fakeAPI, *Client) {
+	t.Helper()
+	api := &fakeAPI{limited: limited, messages: make(map[string][]Message)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		api.requests++
+		if api.requests <= api.limited {
+			w.Header().Set("Retry-After", "1.5")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		assert.Equal(t, "Bot token", r.Header.Get("Authorization"))
+		channelID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/channels/"), "/messages")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var message Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		api.messages[channelID] = append(api.messages[channelID], message)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": strconv.Itoa(api.requests)})
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("token", WithAPIURL(server.URL+"/"))
+	client.after = func(time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+	return api, client
+}
+
+func (f *fakeAPI) sent(channelID string) []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.messages[channelID]...)
+}
+
+func TestClient_CreateMessage(t *testing.T) {
+	t.Run("should retry rate limited requests", func(t *testing.T) {
+		api, client := newFakeAPI(t, 2)
+		id, err := client.CreateMessage("C1", Message{Content: "hello"})
+		require.NoError(t, err)
+		assert.Equal(t, "3", id)
+		assert.Len(t, api.sent("C1"), 1)
+	})
+
+	t.Run("should give up once the retries are exhausted", func(t *testing.T) {
+		_, client := newFakeAPI(t, maxRetries+1)
+		_, err := client.CreateMessage("C1", Message{Content: "hello"})
+		assert.ErrorIs(t, err, ErrAPI)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	parsed, err := ParsePublicKey(hex.EncodeToString(publicKey))
+	require.NoError(t, err)
+	body := []byte(`{"type":1}`)
+	sign := func(timestamp string, body []byte) string {
+		return hex.EncodeToString(ed25519.Sign(privateKey, append([]byte(timestamp), body...)))
+	}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	t.Run("should accept a request signed by Discord", func(t *testing.T) {
+		assert.NoError(t, Verify(parsed, sign(now, body), now, body))
+	})
+
+	t.Run("should reject a request with a different body", func(t *testing.T) {
+		assert.ErrorIs(t, Verify(parsed, sign(now, body), now, []byte(`{"type":2}`)), ErrInvalidSignature)
+	})
+
+	t.Run("should reject an old request", func(t *testing.T) {
+		old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+		assert.ErrorIs(t, Verify(parsed, sign(old, body), old, body), ErrInvalidSignature)
+	})
+
+	t.Run("should reject a malformed signature", func(t *testing.T) {
+		assert.ErrorIs(t, Verify(parsed, "zz", now, body), ErrInvalidSignature)
+	})
+}
+
+func TestUI_AskQuestion(t *testing.T) {
+	// press returns the interaction of the user pressing the button, or submitting the form, with the custom ID.
+	press := func(userID, customID, answer string) Interaction {
+		data := &InteractionData{CustomID: customID}
+		if answer != "" {
+			data.Components = []Component{{Type: ComponentActionRow, Components: []Component{{Type: ComponentTextInput, CustomID: FieldAnswer, Value: answer}}}}
+		}
+		return Interaction{Type: InteractionMessageComponent, User: &User{ID: userID}, Data: data}
+	}
+	// ask asks the question in the background, and waits for it to be sent.
+	ask := func(t *testing.T, api *fakeAPI, ui *UI, question interview.Question) <-chan action {
+		t.Helper()
+		sent := len(api.sent("D1"))
+		result := make(chan action, 1)
+		go func() {
+			answer, err := ui.AskQuestion(question)
+			result <- action{answer: answer, err: err}
+		}()
+		require.Eventually(t, func() bool {
+			ui.mu.Lock()
+			defer ui.mu.Unlock()
+			return len(api.sent("D1")) > sent && ui.waiting != 0
+		}, time.Second, time.Millisecond)
+		return result
+	}
+
+	t.Run("should send the question with its buttons", func(t *testing.T) {
+		api, client := newFakeAPI(t, 0)
+		ui := New(client, "D1", "U1")
+		result := ask(t, api, ui, interview.Question{Text: "Which plan?", Choices: []string{"Free", "Pro"}, Number: 1, Total: 2})
+
+		message := api.sent("D1")[0]
+		assert.Equal(t, "Which plan?\n-# Question 1 of 2", message.Content)
+		require.Len(t, message.Components, 2)
+		assert.Equal(t, "Pro", message.Components[0].Components[1].Label)
+		assert.Equal(t, "vox_choice:1:1", message.Components[0].Components[1].CustomID)
+		assert.Equal(t, []string{"U1"}, message.AllowedMentions.Users)
+
+		t.Run("should ignore other users", func(t *testing.T) {
+			assert.False(t, ui.HandleInteraction(press("U2", "vox_choice:1:0", "")))
+		})
+
+		assert.True(t, ui.HandleInteraction(press("U1", "vox_choice:1:1", "")))
+		assert.Equal(t, action{answer: "Pro"}, <-result)
+	})
+
+	t.Run("should take the answer from the form", func(t *testing.T) {
+		api, client := newFakeAPI(t, 0)
+		ui := New(client, "D1", "U1")
+		ui.asked = 1
+		result := ask(t, api, ui, interview.Question{Text: "Why?"})
+
+		t.Run("should ignore buttons on earlier questions", func(t *testing.T) {
+			assert.False(t, ui.HandleInteraction(press("U1", "vox_form:1", "Old answer")))
+		})
+
+		form, ok := AnswerForm("vox_answer:2")
+		require.True(t, ok)
+		assert.Equal(t, "vox_form:2", form.Data.CustomID)
+		assert.True(t, ui.HandleInteraction(press("U1", form.Data.CustomID, " Because. ")))
+		assert.Equal(t, action{answer: "Because."}, <-result)
+	})
+
+	t.Run("should skip the question or wrap up", func(t *testing.T) {
+		api, client := newFakeAPI(t, 0)
+		ui := New(client, "D1", "U1")
+		result := ask(t, api, ui, interview.Question{Text: "Why?"})
+		assert.True(t, ui.HandleInteraction(press("U1", "vox_skip:1", "")))
+		assert.ErrorIs(t, (<-result).err, interview.ErrSkipped)
+
+		result = ask(t, api, ui, interview.Question{Text: "Why not?"})
+		assert.True(t, ui.HandleInteraction(press("U1", "vox_wrap_up:2", "")))
+		assert.ErrorIs(t, (<-result).err, interview.ErrStopped)
+	})
+}
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, []string{"short"}, split("short", 10))
+	assert.Equal(t, []string{"first", "second"}, split("first\nsecond", 10))
+	assert.Equal(t, []string{"abcde", "fghij", "k"}, split("abcdefghijk", 5))
+}
@@ -0,0 +1,204 @@
+// Package discord provides an InterviewUI for Discord. Discord sends the bot interactions, such as slash
+// commands and button presses, over HTTP, and the bot sends its messages with the Discord REST API. As the bot
+// doesn't receive ordinary messages, participants answer each question with a button that opens a form.
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Types of interaction.
+const (
+	InteractionPing               = 1
+	InteractionApplicationCommand = 2
+	InteractionMessageComponent   = 3
+	InteractionModalSubmit        = 5
+)
+
+// Types of response to an interaction.
+const (
+	ResponsePong = 1
+	// ResponseMessage replies to the interaction with a message.
+	ResponseMessage = 4
+	// ResponseDeferredUpdate acknowledges a button press without changing the message it was on.
+	ResponseDeferredUpdate = 6
+	// ResponseModal replies to the interaction with a form.
+	ResponseModal = 9
+)
+
+// FlagEphemeral makes a reply to an interaction visible only to the user who used it.
+const FlagEphemeral = 1 << 6
+
+// maxTimestampAge is how old the timestamp of an interaction can be, so a captured request can't be replayed
+// later.
+const maxTimestampAge = 5 * time.Minute
+
+// ErrInvalidSignature is returned for a request that wasn't signed by Discord.
+var ErrInvalidSignature = errors.New("invalid discord request signature")
+
+// Interaction is a slash command, button press or form submission sent to the bot, with the fields vox uses.
+type Interaction struct {
+	ID        string           `json:"id"`
+	Type      int              `json:"type"`
+	Data      *InteractionData `json:"data,omitempty"`
+	GuildID   string           `json:"guild_id,omitempty"`
+	ChannelID string           `json:"channel_id,omitempty"`
+	// Member is set for interactions in a server, and User for those in a direct message.
+	Member *Member `json:"member,omitempty"`
+	User   *User   `json:"user,omitempty"`
+	Token  string  `json:"token"`
+}
+
+// InteractionData is the command, button or form an interaction is for.
+type InteractionData struct {
+	// Name and Options are set for slash commands.
+	Name    string              `json:"name,omitempty"`
+	Options []InteractionOption `json:"options,omitempty"`
+	// CustomID is set for buttons and forms.
+	CustomID string `json:"custom_id,omitempty"`
+	// Components holds the fields of a form.
+	Components []Component `json:"components,omitempty"`
+}
+
+// InteractionOption is a subcommand or option of a slash command.
+type InteractionOption struct {
+	Name    string              `json:"name"`
+	Type    int                 `json:"type"`
+	Value   any                 `json:"value,omitempty"`
+	Options []InteractionOption `json:"options,omitempty"`
+}
+
+// Member is a member of a Discord server.
+type Member struct {
+	User *User `json:"user"`
+}
+
+// User is a Discord user.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+// InteractionResponse is the reply to an interaction.
+type InteractionResponse struct {
+	Type int           `json:"type"`
+	Data *ResponseData `json:"data,omitempty"`
+}
+
+// ResponseData is the message or form replied with.
+type ResponseData struct {
+	Content    string      `json:"content,omitempty"`
+	Flags      int         `json:"flags,omitempty"`
+	CustomID   string      `json:"custom_id,omitempty"`
+	Title      string      `json:"title,omitempty"`
+	Components []Component `json:"components,omitempty"`
+	// AllowedMentions limits who is notified by mentions in the content.
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+}
+
+// Component is part of a message or form, such as a row of buttons or a text field.
+type Component struct {
+	Type       int         `json:"type"`
+	Style      int         `json:"style,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	CustomID   string      `json:"custom_id,omitempty"`
+	Value      string      `json:"value,omitempty"`
+	Required   bool        `json:"required,omitempty"`
+	MaxLength  int         `json:"max_length,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+// Types and styles of component.
+const (
+	ComponentActionRow = 1
+	ComponentButton    = 2
+	ComponentTextInput = 4
+
+	ButtonPrimary   = 1
+	ButtonSecondary = 2
+	TextParagraph   = 2
+)
+
+// UserID returns the ID of the user who used the interaction, wherever it was used.
+func (i Interaction) UserID() string {
+	switch {
+	case i.Member != nil && i.Member.User != nil:
+		return i.Member.User.ID
+	case i.User != nil:
+		return i.User.ID
+	}
+	return ""
+}
+
+// Option returns the value of the named option of the slash command's subcommand, or nil if it wasn't given.
+func (i Interaction) Option(name string) any {
+	if i.Data == nil || len(i.Data.Options) == 0 {
+		return nil
+	}
+	for _, option := range i.Data.Options[0].Options {
+		if option.Name == name {
+			return option.Value
+		}
+	}
+	return nil
+}
+
+// Subcommand returns the name of the slash command's subcommand, such as "start".
+func (i Interaction) Subcommand() string {
+	if i.Data == nil || len(i.Data.Options) == 0 {
+		return ""
+	}
+	return i.Data.Options[0].Name
+}
+
+// FormValue returns the value of the text field in a submitted form with the custom ID.
+func (i Interaction) FormValue(customID string) string {
+	if i.Data == nil {
+		return ""
+	}
+	for _, row := range i.Data.Components {
+		for _, field := range row.Components {
+			if field.CustomID == customID {
+				return field.Value
+			}
+		}
+	}
+	return ""
+}
+
+// ParsePublicKey parses the hex-encoded public key of a Discord application.
+func ParsePublicKey(key string) (ed25519.PublicKey, error) {
+	decoded, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode public key: %w", err)
+	}
+	if len(decoded) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be %d bytes, not %d", ed25519.PublicKeySize, len(decoded))
+	}
+	return ed25519.PublicKey(decoded), nil
+}
+
+// Verify checks the X-Signature-Ed25519 and X-Signature-Timestamp headers Discord sends with each request
+// against the request body.
+func Verify(publicKey ed25519.PublicKey, signature, timestamp string, body []byte) error {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	if !ed25519.Verify(publicKey, append([]byte(timestamp), body...), sig) {
+		return fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > maxTimestampAge || age < -maxTimestampAge {
+		return fmt.Errorf("%w: timestamp is too old", ErrInvalidSignature)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+// Package email provides an InterviewUI that holds interviews over email. Each question is sent as an email
+// over SMTP, and the participant answers by replying to it, so an interview can carry on over days. Replies are
+// fetched over IMAP or delivered by a webhook, and matched to their interview and question by the Message-IDs
+// they refer back to.
+package email
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/chat"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// Replies that skip a question or wrap up the interview.
+const (
+	ReplySkip   = "skip"
+	ReplyWrapUp = "wrap up"
+)
+
+var (
+	// ErrNotWaiting is returned for a reply to a question that isn't waiting for an answer yet, such as while
+	// an interview is being picked up after a restart. It can be passed on again later.
+	ErrNotWaiting = errors.New("question is not waiting for an answer yet")
+	// ErrStale is returned for a reply to a question that has already been answered.
+	ErrStale = errors.New("question has already been answered")
+)
+
+// UI handles the user interface for an interview over email. Every email it sends is part of one thread.
+type UI struct {
+	Sender Sender
+	// From is the address the emails are sent from, such as "Vox <research@example.com>".
+	From string
+	// Address is the participant's email address.
+	Address string
+	// InterviewID identifies the interview in the Message-ID of each email, so replies can be matched to it.
+	InterviewID string
+	Subject     string
+
+	fromAddress string
+	domain      string
+	intro       string
+	// pending is the question that was already sent when the interview was picked up. See WithResume.
+	pending string
+	onAsk   func(n int, question string)
+
+	*chat.Cancellation
+
+	answers chan string
+
+	mu sync.Mutex
+	// asked counts the questions sent, and answered is the number of the last one answered.
+	asked    int
+	answered int
+	// waiting is the number of the question waiting for an answer, or zero if there is none.
+	waiting int
+	// question is the text of the last question asked, and choices are the choices offered by the question
+	// waiting for an answer.
+	question string
+	choices  []string
+}
+
+// Option configures optional behaviour of a UI.
+type Option func(*UI)
+
+// WithIntro adds the text above the first question, to introduce the interview.
+func WithIntro(text string) Option {
+	return func(u *UI) {
+		u.intro = text
+	}
+}
+
+// WithResume picks up an interview that had sent the given number of questions. If there is a pending question,
+// the last one sent, the next question asked is taken to be it, and isn't sent again, as the participant may
+// already be replying to it. The provider may not generate it again with the same text, so the pending question
+// is reported by AskedQuestion in its place.
+func WithResume(asked int, pending string) Option {
+	return func(u *UI) {
+		u.asked = asked
+		u.answered = asked
+		u.pending = pending
+		if pending != "" {
+			u.answered = asked - 1
+		}
+	}
+}
+
+// WithAskHook calls the hook with the number and text of each question once it has been sent, so the interview
+// can be picked up with WithResume.
+func WithAskHook(hook func(n int, question string)) Option {
+	return func(u *UI) {
+		u.onAsk = hook
+	}
+}
+
+// New creates a UI for an interview with the participant at the address, sent from the from address.
+func New(sender Sender, from, address, interviewID, subject string, opts ...Option) *UI {
+	fromAddress := from
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		fromAddress = parsed.Address
+	}
+	u := &UI{
+		Sender:       sender,
+		From:         from,
+		Address:      address,
+		InterviewID:  interviewID,
+		Subject:      subject,
+		fromAddress:  fromAddress,
+		domain:       Domain(fromAddress),
+		answers:      make(chan string, 1),
+		Cancellation: chat.NewCancellation(),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// send sends an email in the interview's thread, in reply to the email for the parent question.
+func (u *UI) send(messageID string, parent int, text string) error {
+	message := Outgoing{
+		From:      u.From,
+		To:        u.Address,
+		Subject:   u.Subject,
+		MessageID: messageID,
+		Text:      text,
+	}
+	if parent > 0 {
+		message.Subject = "Re: " + u.Subject
+		message.InReplyTo = MessageID(u.InterviewID, parent, u.domain)
+		message.References = []string{MessageID(u.InterviewID, 1, u.domain)}
+		if parent > 1 {
+			message.References = append(message.References, message.InReplyTo)
+		}
+	}
+	return u.Sender.Send(u.fromAddress, []string{u.Address}, message.Bytes())
+}
+
+// Say sends an email to the participant that doesn't need an answer.
+func (u *UI) Say(text string) error {
+	u.mu.Lock()
+	parent := u.asked
+	u.mu.Unlock()
+	if err := u.send(noteID(u.InterviewID, parent, u.domain), parent, text); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// Ask sends a question to the participant by email and waits for their reply.
+func (u *UI) Ask(question string) (string, error) {
+	return u.AskQuestion(interview.Question{Text: question})
+}
+
+// AskQuestion sends a question to the participant by email, listing its choices, and waits for their reply.
+// Replying "skip" skips it, and "wrap up" wraps up the interview.
+func (u *UI) AskQuestion(question interview.Question) (string, error) {
+	select {
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	default:
+	}
+
+	// The question waits for an answer before it is sent, so a reply that arrives straight away is taken.
+	u.mu.Lock()
+	number, resend := u.asked+1, true
+	if u.pending != "" {
+		if question.Text != u.pending {
+			// The choices of the question that was sent aren't known, so the reply is taken as it is.
+			question = interview.Question{Text: u.pending}
+		}
+		number, resend = u.asked, false
+		u.pending = ""
+	}
+	u.asked = number
+	u.waiting = number
+	u.question = question.Text
+	u.choices = question.Choices
+	u.mu.Unlock()
+	defer func() {
+		u.mu.Lock()
+		u.waiting = 0
+		u.mu.Unlock()
+	}()
+
+	if resend {
+		slog.Debug("Asking question by email", "interview_id", u.InterviewID, "address", u.Address, "question", question.Text)
+		intro := ""
+		if number == 1 {
+			intro = u.intro
+		}
+		if err := u.send(MessageID(u.InterviewID, number, u.domain), number-1, questionText(intro, question)); err != nil {
+			slog.Error("Failed to send email", "error", err, "interview_id", u.InterviewID, "address", u.Address)
+			return "", fmt.Errorf("failed to send email: %w", err)
+		}
+		if u.onAsk != nil {
+			u.onAsk(number, question.Text)
+		}
+	}
+
+	select {
+	case answer := <-u.answers:
+		slog.Debug("Received answer from user", "interview_id", u.InterviewID, "address", u.Address, "answer", answer)
+		switch {
+		case strings.EqualFold(answer, ReplySkip):
+			return "", interview.ErrSkipped
+		case strings.EqualFold(answer, ReplyWrapUp):
+			return "", interview.ErrStopped
+		}
+		return choice(answer, question.Choices), nil
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	}
+}
+
+// AskedQuestion returns the text of the last question asked, which is the pending question for the first one
+// asked after the interview was picked up.
+func (u *UI) AskedQuestion() string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.question
+}
+
+// questionText builds the email for a question: the introduction, if there is one, the question itself and
+// its choices, how to skip it or wrap up, and its progress through the interview.
+func questionText(intro string, question interview.Question) string {
+	var b strings.Builder
+	if intro != "" {
+		b.WriteString(intro + "\n\n")
+	}
+	b.WriteString(question.Text + "\n")
+	if len(question.Choices) > 0 {
+		b.WriteString("\nReply with one of:\n")
+		for i, choice := range question.Choices {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, choice)
+		}
+	}
+	fmt.Fprintf(&b, "\nReply %q to skip this question, or %q to finish the interview.\n", ReplySkip, ReplyWrapUp)
+	if question.Number > 0 {
+		fmt.Fprintf(&b, "\nQuestion %d", question.Number)
+		if question.Total > 0 {
+			fmt.Fprintf(&b, " of %d", question.Total)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// choice returns the choice the answer picks, by its number or its text, or the answer itself if it doesn't
+// pick one.
+func choice(answer string, choices []string) string {
+	if i, err := strconv.Atoi(strings.TrimSuffix(answer, ".")); err == nil && i >= 1 && i <= len(choices) {
+		return choices[i-1]
+	}
+	for _, choice := range choices {
+		if strings.EqualFold(answer, choice) {
+			return choice
+		}
+	}
+	return answer
+}
+
+// Answer passes a reply from the participant to the nth question. It returns ErrNotWaiting if the question
+// hasn't been asked again yet after the interview was picked up, and ErrStale if it has already been answered.
+func (u *UI) Answer(n int, text string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	switch {
+	case n != 0 && n == u.waiting:
+		// Only the first answer counts.
+		u.waiting = 0
+		u.answered = n
+		u.answers <- strings.TrimSpace(text)
+		return nil
+	case n > u.answered && n <= u.asked:
+		return ErrNotWaiting
+	}
+	return ErrStale
+}
+
+// Close marks the interview as finished, so later replies are ignored.
+func (u *UI) Close() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.waiting = 0
+	u.answered = u.asked
+}
+
+// endCancelled tells the participant the interview was cancelled.
+func (u *UI) endCancelled() error {
+	return u.EndCancelled(u.Say, "interview_id", u.InterviewID, "address", u.Address)
+}
+
+// DisplaySummary emails the interview summary to the participant.
+func (u *UI) DisplaySummary(summary string) {
+	if summary == "" {
+		return
+	}
+	if err := u.Say("Thank you for taking part. Here is a summary of the interview:\n\n" + summary); err != nil {
+		slog.Error("Error displaying summary", "error", err, "interview_id", u.InterviewID, "address", u.Address)
+	}
+}
+
+// Ensure UI implements the domain interface.
+var _ interview.InterviewUI = (*UI)(nil)
+var _ interview.QuestionUI = (*UI)(nil)
+var _ interview.AskedQuestionUI = (*UI)(nil)
@@ -0,0 +1,209 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP is a local stand-in for an SMTP server, that records the messages delivered to it.
+type fakeSMTP struct {
+	address string
+
+	mu       sync.Mutex
+	messages []*mail.Message
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	f := &fakeSMTP{address: listener.Addr().String()}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+// serve speaks just enough SMTP to take a message.
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "AUTH"):
+			reply("235 2.7.0 Authentication successful")
+		case strings.HasPrefix(command, "DATA"):
+			reply("354 Go ahead")
+			var data bytes.Buffer
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			if msg, err := mail.ReadMessage(&data); err == nil {
+				f.mu.Lock()
+				f.messages = append(f.messages, msg)
+				f.mu.Unlock()
+			}
+			reply("250 OK")
+		case strings.HasPrefix(command, "QUIT"):
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// received returns the messages delivered so far.
+func (f *fakeSMTP) received() []*mail.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*mail.Message(nil), f.messages...)
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	server := newFakeSMTP(t)
+	sender := NewSMTPSender(server.address, "vox", "secret")
+
+	message := Outgoing{From: "Vox <research@example.com>", To: "ada@example.org", Subject: "Interview: Feedback", MessageID: "<vox.a1.1@example.com>", Text: "What do you like?"}
+	require.NoError(t, sender.Send("research@example.com", []string{"ada@example.org"}, message.Bytes()))
+
+	received := server.received()
+	require.Len(t, received, 1)
+	assert.Equal(t, "<vox.a1.1@example.com>", received[0].Header.Get("Message-ID"))
+}
+
+func TestUI_AskQuestion(t *testing.T) {
+	// ask asks the question in the background, and waits for it to be waiting for an answer.
+	ask := func(t *testing.T, ui *UI, question interview.Question) <-chan error {
+		t.Helper()
+		result := make(chan error, 1)
+		answers := make(chan string, 1)
+		go func() {
+			answer, err := ui.AskQuestion(question)
+			answers <- answer
+			result <- err
+		}()
+		require.Eventually(t, func() bool {
+			ui.mu.Lock()
+			defer ui.mu.Unlock()
+			return ui.waiting != 0
+		}, time.Second, time.Millisecond)
+		t.Cleanup(func() { <-answers })
+		return result
+	}
+
+	t.Run("should send each question in one thread", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		var hooked []int
+		ui := New(NewSMTPSender(server.address, "", ""), "Vox <research@example.com>", "ada@example.org", "a1", "Interview: Feedback",
+			WithIntro("Hello."), WithAskHook(func(n int, _ string) { hooked = append(hooked, n) }))
+
+		result := ask(t, ui, interview.Question{Text: "Which plan?", Choices: []string{"Free", "Pro"}, Number: 1, Total: 2})
+		require.Eventually(t, func() bool { return len(server.received()) == 1 }, time.Second, time.Millisecond)
+		first := server.received()[0]
+		assert.Equal(t, "Interview: Feedback", first.Header.Get("Subject"))
+		assert.Equal(t, "<vox.a1.1@example.com>", first.Header.Get("Message-ID"))
+		assert.Empty(t, first.Header.Get("In-Reply-To"))
+
+		t.Run("should ignore replies to questions that haven't been asked", func(t *testing.T) {
+			assert.ErrorIs(t, ui.Answer(2, "Pro"), ErrStale)
+		})
+		require.NoError(t, ui.Answer(1, "2"))
+		require.NoError(t, <-result)
+
+		t.Run("should ignore a second reply to the same question", func(t *testing.T) {
+			assert.ErrorIs(t, ui.Answer(1, "Free"), ErrStale)
+		})
+
+		result = ask(t, ui, interview.Question{Text: "Why?", Number: 2, Total: 2})
+		require.Eventually(t, func() bool { return len(server.received()) == 2 }, time.Second, time.Millisecond)
+		second := server.received()[1]
+		assert.Equal(t, "Re: Interview: Feedback", second.Header.Get("Subject"))
+		assert.Equal(t, "<vox.a1.1@example.com>", second.Header.Get("In-Reply-To"))
+		assert.Equal(t, "<vox.a1.1@example.com>", second.Header.Get("References"))
+		require.NoError(t, ui.Answer(2, ReplyWrapUp))
+		assert.ErrorIs(t, <-result, interview.ErrStopped)
+		assert.Equal(t, []int{1, 2}, hooked)
+	})
+
+	t.Run("should pick a choice by its number", func(t *testing.T) {
+		assert.Equal(t, "Pro", choice("2", []string{"Free", "Pro"}))
+		assert.Equal(t, "Pro", choice("pro", []string{"Free", "Pro"}))
+		assert.Equal(t, "3", choice("3", []string{"Free", "Pro"}))
+	})
+
+	t.Run("should not send the pending question again when resumed", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		ui := New(NewSMTPSender(server.address, "", ""), "research@example.com", "ada@example.org", "a1", "Interview: Feedback",
+			WithResume(2, "Why?"))
+
+		assert.ErrorIs(t, ui.Answer(2, "Because."), ErrNotWaiting)
+		result := ask(t, ui, interview.Question{Text: "Why?"})
+		require.NoError(t, ui.Answer(2, "Because."))
+		require.NoError(t, <-result)
+		assert.Empty(t, server.received())
+	})
+
+	t.Run("should take the reply to the pending question when the provider rephrases it", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		ui := New(NewSMTPSender(server.address, "", ""), "research@example.com", "ada@example.org", "a1", "Interview: Feedback",
+			WithResume(2, "Why?"))
+
+		result := ask(t, ui, interview.Question{Text: "What made you choose that?", Choices: []string{"Price", "Speed"}})
+		require.NoError(t, ui.Answer(2, "1"))
+		require.NoError(t, <-result)
+		assert.Empty(t, server.received())
+		assert.Equal(t, "Why?", ui.AskedQuestion())
+
+		t.Run("should send the next question as usual", func(t *testing.T) {
+			result := ask(t, ui, interview.Question{Text: "Anything else?"})
+			require.Eventually(t, func() bool { return len(server.received()) == 1 }, time.Second, time.Millisecond)
+			assert.Equal(t, "<vox.a1.3@example.com>", server.received()[0].Header.Get("Message-ID"))
+			require.NoError(t, ui.Answer(3, "No."))
+			require.NoError(t, <-result)
+			assert.Equal(t, "Anything else?", ui.AskedQuestion())
+		})
+	})
+}
+
+func TestQuestionText(t *testing.T) {
+	text := questionText("Hello.", interview.Question{Text: "Which plan?", Choices: []string{"Free", "Pro"}, Number: 1, Total: 3})
+	assert.Equal(t, "Hello.\n\nWhich plan?\n\nReply with one of:\n1. Free\n2. Pro\n\n"+
+		`Reply "skip" to skip this question, or "wrap up" to finish the interview.`+"\n\nQuestion 1 of 3\n", text)
+}
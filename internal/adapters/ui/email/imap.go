@@ -0,0 +1,234 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMailbox is the mailbox replies are fetched from.
+	DefaultMailbox = "INBOX"
+	// pollTimeout is how long a single poll of the mailbox can take.
+	pollTimeout = 2 * time.Minute
+)
+
+// ErrIMAP is returned when the mailbox can't be read.
+var ErrIMAP = errors.New("imap request failed")
+
+// literalSuffix matches the end of a response line that is followed by a literal of the given size.
+var literalSuffix = regexp.MustCompile(`\{(\d+)\}$`)
+
+// IMAPClient fetches the unread emails in a mailbox over IMAP, marking each one as read once it has been
+// handled. It should be given a mailbox that only vox reads.
+type IMAPClient struct {
+	address   string
+	username  string
+	password  string
+	mailbox   string
+	plaintext bool
+}
+
+// IMAPOption configures optional behaviour of an IMAPClient.
+type IMAPOption func(*IMAPClient)
+
+// WithMailbox fetches emails from the named mailbox instead of DefaultMailbox.
+func WithMailbox(mailbox string) IMAPOption {
+	return func(c *IMAPClient) {
+		c.mailbox = mailbox
+	}
+}
+
+// WithPlaintext connects without TLS. It should only be used with a local stand-in for the mail server.
+func WithPlaintext() IMAPOption {
+	return func(c *IMAPClient) {
+		c.plaintext = true
+	}
+}
+
+// NewIMAPClient creates a client for the IMAP server at the address, in host:port form, that logs in with the
+// username and password.
+func NewIMAPClient(address, username, password string, opts ...IMAPOption) *IMAPClient {
+	c := &IMAPClient{address: address, username: username, password: password, mailbox: DefaultMailbox}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Watch fetches the unread emails every interval until the context is done, passing each to handle. See
+// Fetch.
+func (c *IMAPClient) Watch(ctx context.Context, interval time.Duration, handle func(raw []byte) bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Fetch(handle); err != nil {
+			slog.Error("Error fetching emails", "error", err, "address", c.address, "mailbox", c.mailbox)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Fetch passes each unread email in the mailbox to handle, which reports whether it has been dealt with.
+// Emails that have been are marked as read; the others are left to be fetched again next time.
+func (c *IMAPClient) Fetch(handle func(raw []byte) bool) error {
+	conn, err := c.dial()
+	if err != nil {
+		return fmt.Errorf("%w: could not connect: %w", ErrIMAP, err)
+	}
+	defer conn.close()
+
+	if _, err := conn.command("LOGIN %s %s", quote(c.username), quote(c.password)); err != nil {
+		return err
+	}
+	if _, err := conn.command("SELECT %s", quote(c.mailbox)); err != nil {
+		return err
+	}
+	responses, err := conn.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return err
+	}
+	var uids []string
+	for _, response := range responses {
+		if rest, ok := strings.CutPrefix(response.line, "* SEARCH"); ok {
+			uids = append(uids, strings.Fields(rest)...)
+		}
+	}
+
+	for _, uid := range uids {
+		responses, err := conn.command("UID FETCH %s (BODY.PEEK[])", uid)
+		if err != nil {
+			return err
+		}
+		for _, response := range responses {
+			if len(response.literals) == 0 || !handle(response.literals[0]) {
+				continue
+			}
+			if _, err := conn.command(`UID STORE %s +FLAGS.SILENT (\Seen)`, uid); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = conn.command("LOGOUT")
+	return err
+}
+
+// imapConn is a connection to an IMAP server.
+type imapConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+// imapResponse is an untagged response from the server, with any literals sent with it.
+type imapResponse struct {
+	line     string
+	literals [][]byte
+}
+
+// dial connects to the server and reads its greeting.
+func (c *IMAPClient) dial() (*imapConn, error) {
+	var conn net.Conn
+	var err error
+	if c.plaintext {
+		conn, err = net.DialTimeout("tcp", c.address, dialTimeout)
+	} else {
+		host, _, _ := net.SplitHostPort(c.address)
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", c.address, &tls.Config{ServerName: host})
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(pollTimeout))
+
+	ic := &imapConn{conn: conn, reader: bufio.NewReader(conn)}
+	greeting, err := ic.readResponse()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting.line, "* OK") && !strings.HasPrefix(greeting.line, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected greeting %q", greeting.line)
+	}
+	return ic, nil
+}
+
+// command sends a command and returns the untagged responses to it, or an error if it didn't succeed.
+func (c *imapConn) command(format string, args ...any) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+	command := fmt.Sprintf(format, args...)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, command); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIMAP, err)
+	}
+	name, _, _ := strings.Cut(command, " ")
+
+	var responses []imapResponse
+	for {
+		response, err := c.readResponse()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrIMAP, err)
+		}
+		status, ok := strings.CutPrefix(response.line, tag+" ")
+		if !ok {
+			responses = append(responses, response)
+			continue
+		}
+		if !strings.HasPrefix(status, "OK") {
+			return nil, fmt.Errorf("%w: %s: %s", ErrIMAP, name, status)
+		}
+		return responses, nil
+	}
+}
+
+// readResponse reads a line from the server, along with the literals it contains.
+func (c *imapConn) readResponse() (imapResponse, error) {
+	var response imapResponse
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return response, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		response.line += line
+
+		match := literalSuffix.FindStringSubmatch(line)
+		if match == nil {
+			return response, nil
+		}
+		size, err := strconv.Atoi(match[1])
+		if err != nil {
+			return response, err
+		}
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.reader, literal); err != nil {
+			return response, err
+		}
+		response.literals = append(response.literals, literal)
+	}
+}
+
+// close closes the connection. Any emails not yet marked as read are left unread.
+func (c *imapConn) close() {
+	c.conn.Close()
+}
+
+// quote quotes a string for an IMAP command.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,118 @@
+package email
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIMAP is a local stand-in for an IMAP server, with a single mailbox of messages keyed by UID.
+type fakeIMAP struct {
+	address string
+
+	mu       sync.Mutex
+	messages map[string]string
+	seen     map[string]bool
+}
+
+func newFakeIMAP(t *testing.T, messages map[string]string) *fakeIMAP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	f := &fakeIMAP{address: listener.Addr().String(), messages: messages, seen: make(map[string]bool)}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+// serve speaks just enough IMAP to search for, fetch and mark messages.
+func (f *fakeIMAP) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return
+		}
+		tag, command := fields[0], strings.ToUpper(strings.Join(fields[1:], " "))
+
+		f.mu.Lock()
+		switch {
+		case strings.HasPrefix(command, "LOGIN"):
+			if fields[2] != `"vox"` || fields[3] != `"secret"` {
+				fmt.Fprintf(conn, "%s NO LOGIN failed\r\n", tag)
+				f.mu.Unlock()
+				continue
+			}
+		case strings.HasPrefix(command, "SELECT"):
+			fmt.Fprintf(conn, "* %d EXISTS\r\n", len(f.messages))
+		case strings.HasPrefix(command, "UID SEARCH UNSEEN"):
+			var unseen []string
+			for uid := range f.messages {
+				if !f.seen[uid] {
+					unseen = append(unseen, uid)
+				}
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(unseen, " "))
+		case strings.HasPrefix(command, "UID FETCH"):
+			uid := fields[3]
+			message := f.messages[uid]
+			fmt.Fprintf(conn, "* 1 FETCH (UID %s BODY[] {%d}\r\n%s)\r\n", uid, len(message), message)
+		case strings.HasPrefix(command, "UID STORE"):
+			f.seen[fields[3]] = true
+		case strings.HasPrefix(command, "LOGOUT"):
+			fmt.Fprint(conn, "* BYE\r\n")
+		}
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+		f.mu.Unlock()
+	}
+}
+
+func TestIMAPClient_Fetch(t *testing.T) {
+	server := newFakeIMAP(t, map[string]string{
+		"1": "From: ada@example.org\r\nIn-Reply-To: <vox.a1.1@example.com>\r\n\r\nThe reports.\r\n",
+		"2": "From: grace@example.org\r\n\r\nNot yet.\r\n",
+	})
+	client := NewIMAPClient(server.address, "vox", "secret", WithPlaintext())
+
+	var fetched []string
+	err := client.Fetch(func(raw []byte) bool {
+		reply, err := ParseReply(raw)
+		require.NoError(t, err)
+		fetched = append(fetched, reply.Text)
+		// Only Ada's reply is dealt with, so Grace's is left unread.
+		return reply.From == "ada@example.org"
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"The reports.", "Not yet."}, fetched)
+
+	server.mu.Lock()
+	assert.Equal(t, map[string]bool{"1": true}, server.seen)
+	server.mu.Unlock()
+
+	t.Run("should report a failed login", func(t *testing.T) {
+		err := NewIMAPClient(server.address, "vox", "wrong", WithPlaintext()).Fetch(func([]byte) bool { return true })
+		assert.ErrorIs(t, err, ErrIMAP)
+		assert.ErrorContains(t, err, "LOGIN failed")
+	})
+}
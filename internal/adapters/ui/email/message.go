@@ -0,0 +1,289 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMalformed is returned for an inbound email that can't be read.
+var ErrMalformed = errors.New("malformed email")
+
+// MessageID returns the Message-ID of the nth question of the interview, sent from the domain. Replies refer
+// back to it, which is how they are matched to the interview and the question they answer.
+func MessageID(interviewID string, n int, domain string) string {
+	return fmt.Sprintf("<vox.%s.%d@%s>", interviewID, n, domain)
+}
+
+// noteID returns a unique Message-ID for a message sent while the nth question is waiting for an answer, that
+// isn't a question itself.
+func noteID(interviewID string, n int, domain string) string {
+	return fmt.Sprintf("<vox.%s.%d.%d@%s>", interviewID, n, time.Now().UnixNano(), domain)
+}
+
+// ParseMessageID returns the interview and question number in a Message-ID sent by vox from the domain, or
+// false if it wasn't.
+func ParseMessageID(id, domain string) (interviewID string, n int, ok bool) {
+	id = strings.Trim(strings.TrimSpace(id), "<>")
+	local, host, found := strings.Cut(id, "@")
+	if !found || !strings.EqualFold(host, domain) {
+		return "", 0, false
+	}
+	parts := strings.Split(local, ".")
+	if len(parts) < 3 || parts[0] != "vox" || parts[1] == "" {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(parts[2])
+	if err != nil || n < 0 {
+		return "", 0, false
+	}
+	return parts[1], n, true
+}
+
+// Domain returns the domain of the address, which is used in the Message-IDs of the emails sent from it.
+func Domain(address string) string {
+	_, domain, _ := strings.Cut(address, "@")
+	return strings.ToLower(domain)
+}
+
+// Outgoing is an email sent to the participant.
+type Outgoing struct {
+	From       string
+	To         string
+	Subject    string
+	MessageID  string
+	InReplyTo  string
+	References []string
+	Text       string
+}
+
+// Bytes formats the email as a plain text message, ready to be sent over SMTP.
+func (o Outgoing) Bytes() []byte {
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", o.From)
+	header("To", o.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", o.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", o.MessageID)
+	if o.InReplyTo != "" {
+		header("In-Reply-To", o.InReplyTo)
+	}
+	if len(o.References) > 0 {
+		header("References", strings.Join(o.References, " "))
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	w.Write([]byte(strings.ReplaceAll(o.Text, "\n", "\r\n")))
+	w.Close()
+	return buf.Bytes()
+}
+
+// Reply is an inbound email, with the parts vox uses.
+type Reply struct {
+	// From is the address the email was sent from.
+	From       string
+	Subject    string
+	InReplyTo  string
+	References []string
+	// Text is the text the sender wrote, without the quoted history of the conversation or their signature.
+	Text string
+}
+
+// Thread returns the interview and question the reply was sent in response to, from its In-Reply-To and
+// References headers, or false if it isn't a reply to an email sent by vox from the domain.
+func (r *Reply) Thread(domain string) (interviewID string, n int, ok bool) {
+	if id, n, ok := ParseMessageID(r.InReplyTo, domain); ok {
+		return id, n, true
+	}
+	// The References header lists the thread oldest first, so the latest email from vox is the last one. It is
+	// used when the participant replies to their own email in the thread.
+	for i := len(r.References) - 1; i >= 0; i-- {
+		if id, n, ok := ParseMessageID(r.References[i], domain); ok {
+			return id, n, true
+		}
+	}
+	return "", 0, false
+}
+
+// ParseReply reads an inbound email in the format it was received in.
+func ParseReply(raw []byte) (*Reply, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid From header: %w", ErrMalformed, err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	text, err := bodyText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	return &Reply{
+		From:       strings.ToLower(from.Address),
+		Subject:    subject,
+		InReplyTo:  strings.TrimSpace(msg.Header.Get("In-Reply-To")),
+		References: strings.Fields(msg.Header.Get("References")),
+		Text:       StripQuoted(text),
+	}, nil
+}
+
+// bodyText returns the text of a message body, preferring its plain text part to its HTML one.
+func bodyText(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		var htmlText string
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			partType := part.Header.Get("Content-Type")
+			if partType == "" {
+				partType = "text/plain"
+			}
+			if disposition, _, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition")); disposition == "attachment" {
+				continue
+			}
+			text, err := bodyText(partType, part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+			partMediaType, _, _ := mime.ParseMediaType(partType)
+			switch {
+			case partMediaType == "text/html":
+				if htmlText == "" {
+					htmlText = text
+				}
+			case text != "":
+				return text, nil
+			}
+		}
+		return htmlText, nil
+	}
+	if !strings.HasPrefix(mediaType, "text/") {
+		return "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, &newlineSkipper{r: body})
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if mediaType == "text/html" {
+		text = htmlToText(text)
+	}
+	return text, nil
+}
+
+// newlineSkipper drops the line breaks base64 bodies are wrapped with.
+type newlineSkipper struct {
+	r io.Reader
+}
+
+func (n *newlineSkipper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	kept := p[:0]
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' {
+			kept = append(kept, b)
+		}
+	}
+	return len(kept), err
+}
+
+var (
+	htmlQuote     = regexp.MustCompile(`(?is)<blockquote.*?</blockquote>`)
+	htmlBreak     = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+	htmlTag       = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlHead      = regexp.MustCompile(`(?is)<(head|style|script).*?</(head|style|script)>`)
+	wroteLine     = regexp.MustCompile(`(?i)^on\s.+\swrote:$`)
+	headerLine    = regexp.MustCompile(`(?i)^(from|sent|date|to|subject):\s`)
+	signatureLine = regexp.MustCompile(`(?i)^(sent from my |get outlook for )`)
+)
+
+// htmlToText reduces an HTML body to its text, without any quoted replies.
+func htmlToText(text string) string {
+	text = htmlHead.ReplaceAllString(text, "")
+	text = htmlQuote.ReplaceAllString(text, "")
+	text = htmlBreak.ReplaceAllString(text, "\n")
+	text = htmlTag.ReplaceAllString(text, "")
+	return strings.ReplaceAll(html.UnescapeString(text), "\u00a0", " ")
+}
+
+// StripQuoted returns the text the sender wrote in a reply, without the quoted history of the conversation
+// that email clients add below it, or the sender's signature.
+func StripQuoted(text string) string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(text, "\r\n", "\n")))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t"))
+	}
+
+	var kept []string
+scan:
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		// "On Mon, 1 Jan 2024, Vox <vox@example.com> wrote:", which some clients wrap over two lines.
+		case wroteLine.MatchString(trimmed):
+			break scan
+		case strings.HasPrefix(trimmed, "On ") && i+1 < len(lines) && strings.HasSuffix(strings.TrimSpace(lines[i+1]), "wrote:"):
+			break scan
+		// Outlook starts the quoted email with a separator, or with its headers.
+		case strings.HasPrefix(trimmed, "-----Original Message-----"), strings.HasPrefix(trimmed, "________________________________"):
+			break scan
+		case headerLine.MatchString(trimmed) && i+1 < len(lines) && headerLine.MatchString(strings.TrimSpace(lines[i+1])):
+			break scan
+		// Signatures.
+		case line == "-- " || trimmed == "--", signatureLine.MatchString(trimmed):
+			break scan
+		case strings.HasPrefix(trimmed, ">"):
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
@@ -0,0 +1,125 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessageID(t *testing.T) {
+	id := MessageID("a1b2", 3, "example.com")
+	assert.Equal(t, "<vox.a1b2.3@example.com>", id)
+
+	interviewID, n, ok := ParseMessageID(id, "EXAMPLE.com")
+	require.True(t, ok)
+	assert.Equal(t, "a1b2", interviewID)
+	assert.Equal(t, 3, n)
+
+	_, n, ok = ParseMessageID(noteID("a1b2", 2, "example.com"), "example.com")
+	require.True(t, ok)
+	assert.Equal(t, 2, n)
+
+	for _, other := range []string{id + "x", "<vox.a1b2.3@elsewhere.com>", "<CAF123@mail.gmail.com>", "<vox..3@example.com>"} {
+		_, _, ok := ParseMessageID(other, "example.com")
+		assert.False(t, ok, other)
+	}
+}
+
+func TestStripQuoted(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		want string
+	}{
+		"should keep a reply with nothing quoted": {
+			text: "The reports are great.\n\nThe exports are slow.",
+			want: "The reports are great.\n\nThe exports are slow.",
+		},
+		"should remove the history quoted by Gmail": {
+			text: "The reports.\n\nOn Mon, 6 Jan 2025 at 10:00, Vox <research@example.com> wrote:\n> What do you like?\n",
+			want: "The reports.",
+		},
+		"should remove a wrapped attribution line": {
+			text: "The reports.\n\nOn Mon, 6 Jan 2025 at 10:00, Vox <research@example.com>\nwrote:\n> What do you like?\n",
+			want: "The reports.",
+		},
+		"should remove the history quoted by Outlook": {
+			text: "The reports.\r\n\r\nFrom: Vox <research@example.com>\r\nSent: Monday, January 6, 2025 10:00 AM\r\nSubject: Interview\r\n\r\nWhat do you like?",
+			want: "The reports.",
+		},
+		"should remove signatures": {
+			text: "The reports.\n-- \nAda Lovelace\nAnalytical Engines Ltd",
+			want: "The reports.",
+		},
+		"should remove mobile signatures": {
+			text: "The reports.\n\nSent from my iPhone",
+			want: "The reports.",
+		},
+		"should remove quoted lines between the answer": {
+			text: "> What do you like?\nThe reports.",
+			want: "The reports.",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripQuoted(tt.text))
+		})
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	t.Run("should read the plain text part of a multipart reply", func(t *testing.T) {
+		raw := strings.ReplaceAll(`From: Ada Lovelace <Ada@Example.org>
+To: research@example.com
+Subject: =?utf-8?q?Re:_Interview:_Product_Feedback?=
+In-Reply-To: <vox.a1b2.1@example.com>
+References: <vox.a1b2.1@example.com>
+MIME-Version: 1.0
+Content-Type: multipart/alternative; boundary="b1"
+
+--b1
+Content-Type: text/plain; charset="utf-8"
+Content-Transfer-Encoding: quoted-printable
+
+The reports =E2=80=93 they're great.
+
+On Mon, 6 Jan 2025, Vox <research@example.com> wrote:
+> What do you like?
+--b1
+Content-Type: text/html; charset="utf-8"
+
+<p>The reports</p>
+--b1--
+`, "\n", "\r\n")
+
+		reply, err := ParseReply([]byte(raw))
+		require.NoError(t, err)
+		assert.Equal(t, "ada@example.org", reply.From)
+		assert.Equal(t, "Re: Interview: Product Feedback", reply.Subject)
+		assert.Equal(t, "The reports – they're great.", reply.Text)
+
+		interviewID, n, ok := reply.Thread("example.com")
+		require.True(t, ok)
+		assert.Equal(t, "a1b2", interviewID)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("should read an HTML reply without its quoted history", func(t *testing.T) {
+		raw := "From: ada@example.org\r\nContent-Type: text/html\r\nReferences: <vox.a1b2.1@example.com> <vox.a1b2.2@example.com> <CAF1@mail.example.org>\r\n\r\n" +
+			"<html><head><style>p {}</style></head><body><div>Faster&nbsp;exports</div><blockquote>What would you change?</blockquote></body></html>"
+
+		reply, err := ParseReply([]byte(raw))
+		require.NoError(t, err)
+		assert.Equal(t, "Faster exports", reply.Text)
+
+		_, n, ok := reply.Thread("example.com")
+		require.True(t, ok)
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("should reject an email without a sender", func(t *testing.T) {
+		_, err := ParseReply([]byte("Subject: hello\r\n\r\nhi"))
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+}
@@ -0,0 +1,94 @@
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// ErrSMTP is returned when an email can't be sent.
+var ErrSMTP = errors.New("smtp request failed")
+
+// dialTimeout is how long connecting to a mail server can take.
+const dialTimeout = 30 * time.Second
+
+// Sender sends emails.
+type Sender interface {
+	Send(from string, to []string, message []byte) error
+}
+
+// SMTPSender sends emails through an SMTP server. With port 465 the connection uses TLS from the start;
+// otherwise it is upgraded with STARTTLS if the server offers it.
+type SMTPSender struct {
+	address  string
+	username string
+	password string
+}
+
+// NewSMTPSender creates a sender for the SMTP server at the address, in host:port form. The username and
+// password are only used if the username is set.
+func NewSMTPSender(address, username, password string) *SMTPSender {
+	return &SMTPSender{address: address, username: username, password: password}
+}
+
+// Send sends the message from the address to the recipients.
+func (s *SMTPSender) Send(from string, to []string, message []byte) error {
+	host, port, err := net.SplitHostPort(s.address)
+	if err != nil {
+		return fmt.Errorf("%w: invalid address %q: %w", ErrSMTP, s.address, err)
+	}
+
+	var conn net.Conn
+	if port == "465" {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", s.address, &tls.Config{ServerName: host})
+	} else {
+		conn, err = net.DialTimeout("tcp", s.address, dialTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: could not connect: %w", ErrSMTP, err)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("%w: %w", ErrSMTP, err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("%w: could not start TLS: %w", ErrSMTP, err)
+		}
+	}
+	if s.username != "" {
+		// PlainAuth refuses to send the password without TLS, unless the server is on this machine.
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, host)); err != nil {
+			return fmt.Errorf("%w: could not authenticate: %w", ErrSMTP, err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("%w: %w", ErrSMTP, err)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("%w: %w", ErrSMTP, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSMTP, err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("%w: %w", ErrSMTP, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSMTP, err)
+	}
+	return client.Quit()
+}
+
+// Ensure SMTPSender implements Sender.
+var _ Sender = (*SMTPSender)(nil)
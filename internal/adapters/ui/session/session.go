@@ -0,0 +1,307 @@
+// Package session provides an InterviewUI that is driven remotely, such as from a web browser or an API
+// client. Questions and the summary are recorded as a log of events that clients read, and answers are
+// submitted separately, so a client can disconnect and pick up where it left off.
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// Err* are common errors
+var (
+	ErrClosed     = errors.New("the session is closed")
+	ErrNotWaiting = errors.New("the session is not waiting for an answer")
+)
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+// The kinds of event recorded by a session.
+const (
+	EventQuestion EventType = "question"
+	EventAnswer   EventType = "answer"
+	EventSummary  EventType = "summary"
+	EventError    EventType = "error"
+	EventEnd      EventType = "end"
+)
+
+// Status describes how far a session has got.
+type Status string
+
+// The states of a session.
+const (
+	// StatusPending means the next question is being prepared.
+	StatusPending Status = "pending"
+	// StatusWaiting means a question is waiting for an answer.
+	StatusWaiting   Status = "waiting"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Event is a single entry in the session's log.
+type Event struct {
+	// ID increases with each event, starting at 1, so clients can ask for the events they have not yet seen.
+	ID       int       `json:"id"`
+	Type     EventType `json:"type"`
+	Number   int       `json:"number,omitempty"`
+	Question string    `json:"question,omitempty"`
+	Answer   string    `json:"answer,omitempty"`
+	Summary  string    `json:"summary,omitempty"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// UI is an InterviewUI for a single interview, driven by a remote client.
+type UI struct {
+	ID string
+
+	idleTimeout time.Duration
+	answers     chan string
+	closed      chan struct{}
+	stopped     chan struct{}
+
+	mu      sync.Mutex
+	events  []Event
+	changed chan struct{}
+	waiting bool
+	asked   int
+	ended   bool
+}
+
+// Option configures optional behaviour of a UI.
+type Option func(*UI)
+
+// WithIdleTimeout abandons the interview if a question is not answered within the timeout, so it ends with the
+// answers given so far.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(u *UI) {
+		u.idleTimeout = d
+	}
+}
+
+// New creates a new UI for the session with the given ID.
+func New(id string, opts ...Option) *UI {
+	u := &UI{
+		ID:      id,
+		answers: make(chan string, 1),
+		closed:  make(chan struct{}),
+		stopped: make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// Ask records the question and waits for the client to answer it.
+func (u *UI) Ask(question string) (string, error) {
+	u.mu.Lock()
+	if u.ended {
+		u.mu.Unlock()
+		return "", ErrClosed
+	}
+	if u.isStopped() {
+		u.mu.Unlock()
+		return "", interview.ErrStopped
+	}
+	u.asked++
+	u.waiting = true
+	u.append(Event{Type: EventQuestion, Number: u.asked, Question: question})
+	u.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if u.idleTimeout > 0 {
+		timer := time.NewTimer(u.idleTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case answer := <-u.answers:
+		return answer, nil
+	case <-u.closed:
+		return "", ErrClosed
+	case <-u.stopped:
+		return "", interview.ErrStopped
+	case <-timeout:
+		// The interview is ended with the answers given so far, as it is when a participant in Slack stops
+		// answering.
+		u.mu.Lock()
+		u.waiting = false
+		u.mu.Unlock()
+		return "", interview.ErrAbandoned
+	}
+}
+
+// DisplaySummary records the summary of the interview.
+func (u *UI) DisplaySummary(summary string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.append(Event{Type: EventSummary, Summary: summary})
+}
+
+// Answer submits the answer to the current question.
+func (u *UI) Answer(answer string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.ended {
+		return ErrClosed
+	}
+	if !u.waiting {
+		return ErrNotWaiting
+	}
+	u.waiting = false
+	u.append(Event{Type: EventAnswer, Number: u.asked, Answer: answer})
+	u.answers <- answer
+	return nil
+}
+
+// Current returns the question waiting for an answer, if there is one.
+func (u *UI) Current() (Event, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.waiting {
+		return Event{}, false
+	}
+	for i := len(u.events) - 1; i >= 0; i-- {
+		if u.events[i].Type == EventQuestion {
+			return u.events[i], true
+		}
+	}
+	return Event{}, false
+}
+
+// Stop asks for the interview to finish early. The question waiting for an answer, if any, is abandoned, and
+// the interview is summarised with the answers given so far.
+func (u *UI) Stop() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.ended || u.isStopped() {
+		return
+	}
+	u.waiting = false
+	close(u.stopped)
+}
+
+// Status reports how far the session has got.
+func (u *UI) Status() Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	switch {
+	case u.ended && u.events[len(u.events)-1].Type == EventError:
+		return StatusFailed
+	case u.ended:
+		return StatusCompleted
+	case u.waiting:
+		return StatusWaiting
+	}
+	return StatusPending
+}
+
+// Summary returns the summary of the interview, once it has been recorded.
+func (u *UI) Summary() (string, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, event := range u.events {
+		if event.Type == EventSummary {
+			return event.Summary, true
+		}
+	}
+	return "", false
+}
+
+// Close ends the session. Any question waiting for an answer is abandoned.
+func (u *UI) Close() {
+	u.end(Event{Type: EventEnd})
+}
+
+// Fail ends the session, recording the reason it ended.
+func (u *UI) Fail(err error) {
+	u.end(Event{Type: EventError, Message: err.Error()})
+}
+
+// Ended reports whether the session has ended.
+func (u *UI) Ended() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.ended
+}
+
+// Events returns the events recorded after the event with the given ID.
+func (u *UI) Events(after int) []Event {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.since(after)
+}
+
+// Wait returns the events recorded after the event with the given ID, waiting for one to be recorded if
+// there are none yet. It returns no events once the session has ended and every event has been returned.
+func (u *UI) Wait(ctx context.Context, after int) ([]Event, error) {
+	for {
+		u.mu.Lock()
+		events := u.since(after)
+		ended := u.ended
+		changed := u.changed
+		u.mu.Unlock()
+
+		if len(events) > 0 || ended {
+			return events, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// end records the final event of the session and releases anyone waiting on it.
+func (u *UI) end(event Event) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.ended {
+		return
+	}
+	u.waiting = false
+	u.append(event)
+	u.ended = true
+	close(u.closed)
+}
+
+// isStopped reports whether Stop has been called. The caller must hold the lock.
+func (u *UI) isStopped() bool {
+	select {
+	case <-u.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// append records an event and wakes anyone waiting for one. The caller must hold the lock.
+func (u *UI) append(event Event) {
+	event.ID = len(u.events) + 1
+	u.events = append(u.events, event)
+	close(u.changed)
+	u.changed = make(chan struct{})
+}
+
+// since returns a copy of the events after the given ID. The caller must hold the lock.
+func (u *UI) since(after int) []Event {
+	if after < 0 {
+		after = 0
+	}
+	if after >= len(u.events) {
+		return nil
+	}
+	return append([]Event(nil), u.events[after:]...)
+}
+
+// Ensure UI implements the domain interface.
+var _ interview.InterviewUI = (*UI)(nil)
@@ -0,0 +1,127 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/session"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUI_AskAndAnswer(t *testing.T) {
+	ui := session.New("abc")
+
+	answers := make(chan string)
+	go func() {
+		answer, err := ui.Ask("What do you do?")
+		assert.NoError(t, err)
+		answers <- answer
+	}()
+
+	events, err := ui.Wait(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, session.Event{ID: 1, Type: session.EventQuestion, Number: 1, Question: "What do you do?"}, events[0])
+
+	current, ok := ui.Current()
+	assert.True(t, ok)
+	assert.Equal(t, "What do you do?", current.Question)
+
+	require.NoError(t, ui.Answer("I build things."))
+	assert.Equal(t, "I build things.", <-answers)
+	assert.ErrorIs(t, ui.Answer("Again"), session.ErrNotWaiting)
+
+	ui.DisplaySummary("A builder.")
+	ui.Close()
+
+	// A client that reconnects gets everything it missed, and then nothing more.
+	events, err = ui.Wait(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, session.Event{ID: 2, Type: session.EventAnswer, Number: 1, Answer: "I build things."}, events[0])
+	assert.Equal(t, session.EventSummary, events[1].Type)
+	assert.Equal(t, session.EventEnd, events[2].Type)
+
+	events, err = ui.Wait(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.ErrorIs(t, ui.Answer("Late"), session.ErrClosed)
+}
+
+func TestUI_Close(t *testing.T) {
+	ui := session.New("abc")
+
+	errs := make(chan error)
+	go func() {
+		_, err := ui.Ask("What do you do?")
+		errs <- err
+	}()
+
+	_, err := ui.Wait(context.Background(), 0)
+	require.NoError(t, err)
+	ui.Close()
+	assert.ErrorIs(t, <-errs, session.ErrClosed)
+	assert.True(t, ui.Ended())
+}
+
+func TestUI_IdleTimeout(t *testing.T) {
+	ui := session.New("abc", session.WithIdleTimeout(10*time.Millisecond))
+
+	_, err := ui.Ask("What do you do?")
+	assert.ErrorIs(t, err, interview.ErrAbandoned)
+	assert.Equal(t, session.StatusPending, ui.Status())
+	assert.ErrorIs(t, ui.Answer("Too late."), session.ErrNotWaiting)
+
+	// The interview is still summarised with the answers given so far.
+	ui.DisplaySummary("A short interview.")
+	ui.Close()
+	assert.Equal(t, session.StatusCompleted, ui.Status())
+}
+
+func TestUI_WaitCancelled(t *testing.T) {
+	ui := session.New("abc")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ui.Wait(ctx, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUI_Stop(t *testing.T) {
+	ui := session.New("abc")
+
+	errs := make(chan error)
+	go func() {
+		_, err := ui.Ask("What do you do?")
+		errs <- err
+	}()
+
+	_, err := ui.Wait(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, session.StatusWaiting, ui.Status())
+
+	ui.Stop()
+	assert.ErrorIs(t, <-errs, interview.ErrStopped)
+	assert.Equal(t, session.StatusPending, ui.Status())
+
+	// Any further questions are stopped too, but the summary can still be recorded.
+	_, err = ui.Ask("Anything else?")
+	assert.ErrorIs(t, err, interview.ErrStopped)
+	ui.DisplaySummary("A short interview.")
+	ui.Close()
+
+	summary, ok := ui.Summary()
+	assert.True(t, ok)
+	assert.Equal(t, "A short interview.", summary)
+	assert.Equal(t, session.StatusCompleted, ui.Status())
+}
+
+func TestUI_StatusFailed(t *testing.T) {
+	ui := session.New("abc")
+	ui.Fail(errors.New("the provider is unavailable"))
+	assert.Equal(t, session.StatusFailed, ui.Status())
+}
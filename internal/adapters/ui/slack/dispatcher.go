@@ -0,0 +1,242 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// DefaultQueueSize is the number of messages that can wait to be sent to each channel.
+	DefaultQueueSize = 100
+	// DefaultMaxRetries is the number of times a message is retried after Slack rejects it, before it is
+	// dropped.
+	DefaultMaxRetries = 3
+	// retryBackoff is how long to wait before retrying a message Slack failed to accept, doubled for each
+	// retry. Rate limited messages wait as long as Slack asks instead.
+	retryBackoff = time.Second
+)
+
+var (
+	// ErrQueueFull is returned for a message to a channel that already has too many messages waiting.
+	ErrQueueFull = errors.New("slack outbound queue is full")
+	// ErrRetriesExhausted is returned for a message Slack still hasn't accepted after it has been retried.
+	ErrRetriesExhausted = errors.New("slack message retries exhausted")
+)
+
+// Dispatcher sends messages to Slack through a queue for each channel, so a burst of messages doesn't exceed
+// Slack's rate limits. Messages to a channel are sent in the order they were queued. When Slack rate limits a
+// message, every channel waits for as long as Slack asks before it is retried.
+//
+// Calls other than PostMessage and PostEphemeral go directly to the client.
+type Dispatcher struct {
+	*slack.Client
+
+	queueSize  int
+	maxRetries int
+	meter      metric.MeterProvider
+	// after waits for the duration, and is replaced in tests.
+	after func(time.Duration) <-chan time.Time
+
+	depth   metric.Int64UpDownCounter
+	dropped metric.Int64Counter
+	retries metric.Int64Counter
+
+	mu     sync.Mutex
+	queues map[string]chan delivery
+	// pausedUntil is when Slack will accept messages again, after it rate limited one.
+	pausedUntil time.Time
+}
+
+// delivery is a message waiting to be sent.
+type delivery struct {
+	send func() error
+	done chan error
+}
+
+// DispatcherOption configures optional behaviour of a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithQueueSize sets the number of messages that can wait to be sent to each channel. Messages beyond it are
+// dropped.
+func WithQueueSize(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.queueSize = n
+	}
+}
+
+// WithMaxRetries sets the number of times a message is retried before it is dropped.
+func WithMaxRetries(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.maxRetries = n
+	}
+}
+
+// WithMeterProvider records the dispatcher's metrics with the provider, instead of the global one.
+func WithMeterProvider(provider metric.MeterProvider) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.meter = provider
+	}
+}
+
+// NewDispatcher creates a dispatcher that sends messages with the client.
+func NewDispatcher(client *slack.Client, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		Client:     client,
+		queueSize:  DefaultQueueSize,
+		maxRetries: DefaultMaxRetries,
+		meter:      otel.GetMeterProvider(),
+		after:      time.After,
+		queues:     make(map[string]chan delivery),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	// Instruments can only fail to be created with invalid names, so the errors are ignored.
+	meter := d.meter.Meter("github.com/andrewhowdencom/vox/internal/adapters/ui/slack")
+	d.depth, _ = meter.Int64UpDownCounter("vox.slack.outbound.queue.depth",
+		metric.WithDescription("Messages waiting to be sent to Slack"), metric.WithUnit("{message}"))
+	d.dropped, _ = meter.Int64Counter("vox.slack.outbound.dropped",
+		metric.WithDescription("Messages that were never sent to Slack"), metric.WithUnit("{message}"))
+	d.retries, _ = meter.Int64Counter("vox.slack.outbound.retries",
+		metric.WithDescription("Messages retried after Slack rejected them"), metric.WithUnit("{message}"))
+	return d
+}
+
+// PostMessage queues the message for the channel, and waits for it to be sent.
+func (d *Dispatcher) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	var channel, timestamp string
+	err := d.dispatch(channelID, func() error {
+		var err error
+		channel, timestamp, err = d.Client.PostMessage(channelID, options...)
+		return err
+	})
+	return channel, timestamp, err
+}
+
+// PostEphemeral queues the ephemeral message for the channel, and waits for it to be sent.
+func (d *Dispatcher) PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error) {
+	var timestamp string
+	err := d.dispatch(channelID, func() error {
+		var err error
+		timestamp, err = d.Client.PostEphemeral(channelID, userID, options...)
+		return err
+	})
+	return timestamp, err
+}
+
+// dispatch adds the message to the channel's queue, starting a worker for the channel if it has none, and
+// waits for it to be sent.
+func (d *Dispatcher) dispatch(channelID string, send func() error) error {
+	message := delivery{send: send, done: make(chan error, 1)}
+
+	d.mu.Lock()
+	queue, ok := d.queues[channelID]
+	if !ok {
+		queue = make(chan delivery, d.queueSize)
+		d.queues[channelID] = queue
+		go d.work(channelID, queue)
+	}
+	select {
+	case queue <- message:
+		d.depth.Add(context.Background(), 1)
+	default:
+		d.mu.Unlock()
+		d.drop("queue_full")
+		slog.Warn("Dropping Slack message, the queue is full", "channel_id", channelID)
+		return fmt.Errorf("%w: %s", ErrQueueFull, channelID)
+	}
+	d.mu.Unlock()
+
+	return <-message.done
+}
+
+// work sends the channel's messages in order, until its queue is empty.
+func (d *Dispatcher) work(channelID string, queue chan delivery) {
+	for {
+		// Messages are only queued with the lock held, so none can be added once the queue is removed.
+		d.mu.Lock()
+		if len(queue) == 0 {
+			delete(d.queues, channelID)
+			d.mu.Unlock()
+			return
+		}
+		d.mu.Unlock()
+
+		message := <-queue
+		d.depth.Add(context.Background(), -1)
+		message.done <- d.deliver(channelID, message.send)
+	}
+}
+
+// deliver sends the message, retrying it if Slack rate limits it or fails to accept it.
+func (d *Dispatcher) deliver(channelID string, send func() error) error {
+	for attempt := 0; ; attempt++ {
+		d.waitForLimit()
+		err := send()
+		wait, ok := retryAfter(err, attempt)
+		if !ok {
+			return err
+		}
+		if attempt == d.maxRetries {
+			d.drop("retries_exhausted")
+			slog.Warn("Dropping Slack message, retries exhausted", "channel_id", channelID, "error", err)
+			return fmt.Errorf("%w: %w", ErrRetriesExhausted, err)
+		}
+
+		d.retries.Add(context.Background(), 1)
+		slog.Debug("Retrying Slack message", "channel_id", channelID, "error", err, "wait", wait)
+		var limited *slack.RateLimitedError
+		if errors.As(err, &limited) {
+			d.pause(wait)
+			continue
+		}
+		<-d.after(wait)
+	}
+}
+
+// retryAfter reports whether the error from sending a message is worth retrying, and how long to wait first.
+func retryAfter(err error, attempt int) (time.Duration, bool) {
+	var limited *slack.RateLimitedError
+	if errors.As(err, &limited) {
+		return limited.RetryAfter, true
+	}
+	var status slack.StatusCodeError
+	if errors.As(err, &status) && status.Retryable() {
+		return retryBackoff << attempt, true
+	}
+	return 0, false
+}
+
+// pause stops every channel from sending messages for the duration.
+func (d *Dispatcher) pause(wait time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if until := time.Now().Add(wait); until.After(d.pausedUntil) {
+		d.pausedUntil = until
+	}
+}
+
+// waitForLimit waits until Slack will accept messages again, if it has rate limited one.
+func (d *Dispatcher) waitForLimit() {
+	d.mu.Lock()
+	wait := time.Until(d.pausedUntil)
+	d.mu.Unlock()
+	if wait > 0 {
+		<-d.after(wait)
+	}
+}
+
+// drop records a message that will never be sent.
+func (d *Dispatcher) drop(reason string) {
+	d.dropped.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
@@ -0,0 +1,247 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// fakePoster is a Slack API that records the messages posted to it, and answers each with the response for
+// its text.
+type fakePoster struct {
+	mu       sync.Mutex
+	received []string
+	// respond writes the response to the message, and is called without the lock held.
+	respond func(w http.ResponseWriter, text string)
+}
+
+func newFakePoster(t *testing.T, respond func(w http.ResponseWriter, text string)) (*fakePoster, *slack.Client) {
+	t.Helper()
+	f := &fakePoster{respond: respond}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		text := r.Form.Get("channel") + ":" + r.Form.Get("text")
+		f.mu.Lock()
+		f.received = append(f.received, text)
+		f.mu.Unlock()
+		f.respond(w, text)
+	}))
+	t.Cleanup(server.Close)
+	return f, slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))
+}
+
+func (f *fakePoster) messages() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.received...)
+}
+
+func ok(w http.ResponseWriter, _ string) {
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "channel": "C1", "ts": "1700000000.000100"})
+}
+
+func rateLimited(w http.ResponseWriter, _ string) {
+	w.Header().Set("Retry-After", "2")
+	w.WriteHeader(http.StatusTooManyRequests)
+}
+
+// newTestDispatcher creates a dispatcher that records its metrics with the reader, and records how long it
+// waits instead of waiting.
+func newTestDispatcher(client *slack.Client, reader sdkmetric.Reader, opts ...DispatcherOption) (*Dispatcher, func() []time.Duration) {
+	opts = append(opts, WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	d := NewDispatcher(client, opts...)
+	var mu sync.Mutex
+	var waits []time.Duration
+	d.after = func(wait time.Duration) <-chan time.Time {
+		mu.Lock()
+		waits = append(waits, wait)
+		mu.Unlock()
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+	return d, func() []time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Duration(nil), waits...)
+	}
+}
+
+// sum returns the total recorded by the counter, across all its attributes.
+func sum(t *testing.T, reader sdkmetric.Reader, name string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var total int64
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != name {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				total += point.Value
+			}
+		}
+	}
+	return total
+}
+
+func TestDispatcher_Order(t *testing.T) {
+	release := make(chan struct{})
+	api, client := newFakePoster(t, func(w http.ResponseWriter, text string) {
+		if text == "C1:first" {
+			<-release
+		}
+		ok(w, text)
+	})
+	reader := sdkmetric.NewManualReader()
+	d, _ := newTestDispatcher(client, reader)
+
+	queued := func(n int) func() bool {
+		return func() bool {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			return len(d.queues["C1"]) == n
+		}
+	}
+	var wg sync.WaitGroup
+	post := func(text string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := d.PostMessage("C1", slack.MsgOptionText(text, false))
+			assert.NoError(t, err)
+		}()
+	}
+	post("first")
+	require.Eventually(t, func() bool { return len(api.messages()) == 1 }, time.Second, time.Millisecond)
+	post("second")
+	require.Eventually(t, queued(1), time.Second, time.Millisecond)
+	post("third")
+	require.Eventually(t, queued(2), time.Second, time.Millisecond)
+	assert.Equal(t, int64(2), sum(t, reader, "vox.slack.outbound.queue.depth"))
+
+	t.Run("should not hold up other channels", func(t *testing.T) {
+		_, err := d.PostEphemeral("C2", "U1", slack.MsgOptionText("elsewhere", false))
+		assert.NoError(t, err)
+	})
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []string{"C1:first", "C2:elsewhere", "C1:second", "C1:third"}, api.messages())
+	assert.Zero(t, sum(t, reader, "vox.slack.outbound.queue.depth"))
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	assert.Empty(t, d.queues)
+}
+
+func TestDispatcher_RateLimit(t *testing.T) {
+	t.Run("should retry once Slack is ready", func(t *testing.T) {
+		var limited sync.Once
+		api, client := newFakePoster(t, func(w http.ResponseWriter, text string) {
+			retry := false
+			limited.Do(func() { retry = true })
+			if retry {
+				rateLimited(w, text)
+				return
+			}
+			ok(w, text)
+		})
+		reader := sdkmetric.NewManualReader()
+		d, waits := newTestDispatcher(client, reader)
+
+		_, timestamp, err := d.PostMessage("C1", slack.MsgOptionText("hello", false))
+		require.NoError(t, err)
+		assert.Equal(t, "1700000000.000100", timestamp)
+		assert.Len(t, api.messages(), 2)
+		require.Len(t, waits(), 1)
+		assert.InDelta(t, 2*time.Second, waits()[0], float64(100*time.Millisecond))
+		assert.Equal(t, int64(1), sum(t, reader, "vox.slack.outbound.retries"))
+		assert.Zero(t, sum(t, reader, "vox.slack.outbound.dropped"))
+	})
+
+	t.Run("should drop messages once the retries are exhausted", func(t *testing.T) {
+		api, client := newFakePoster(t, rateLimited)
+		reader := sdkmetric.NewManualReader()
+		d, _ := newTestDispatcher(client, reader, WithMaxRetries(2))
+
+		_, _, err := d.PostMessage("C1", slack.MsgOptionText("hello", false))
+		assert.ErrorIs(t, err, ErrRetriesExhausted)
+		assert.Len(t, api.messages(), 3)
+		assert.Equal(t, int64(2), sum(t, reader, "vox.slack.outbound.retries"))
+		assert.Equal(t, int64(1), sum(t, reader, "vox.slack.outbound.dropped"))
+	})
+
+	t.Run("should back off from server errors", func(t *testing.T) {
+		var failed sync.Once
+		_, client := newFakePoster(t, func(w http.ResponseWriter, text string) {
+			fail := false
+			failed.Do(func() { fail = true })
+			if fail {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			ok(w, text)
+		})
+		d, waits := newTestDispatcher(client, sdkmetric.NewManualReader())
+
+		_, _, err := d.PostMessage("C1", slack.MsgOptionText("hello", false))
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{retryBackoff}, waits())
+	})
+
+	t.Run("should not retry other errors", func(t *testing.T) {
+		api, client := newFakePoster(t, func(w http.ResponseWriter, _ string) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "channel_not_found"})
+		})
+		d, _ := newTestDispatcher(client, sdkmetric.NewManualReader())
+
+		_, _, err := d.PostMessage("C1", slack.MsgOptionText("hello", false))
+		assert.EqualError(t, err, "channel_not_found")
+		assert.Len(t, api.messages(), 1)
+	})
+}
+
+func TestDispatcher_QueueFull(t *testing.T) {
+	release := make(chan struct{})
+	api, client := newFakePoster(t, func(w http.ResponseWriter, text string) {
+		<-release
+		ok(w, text)
+	})
+	reader := sdkmetric.NewManualReader()
+	d, _ := newTestDispatcher(client, reader, WithQueueSize(1))
+
+	var wg sync.WaitGroup
+	for waiting := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := d.PostMessage("C1", slack.MsgOptionText("waiting", false))
+			assert.NoError(t, err)
+		}()
+		// The first message is taken off the queue to be sent, and the second waits in it.
+		require.Eventually(t, func() bool {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			return len(api.messages()) == 1 && len(d.queues["C1"]) == waiting
+		}, time.Second, time.Millisecond)
+	}
+
+	_, _, err := d.PostMessage("C1", slack.MsgOptionText("dropped", false))
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, int64(1), sum(t, reader, "vox.slack.outbound.dropped"))
+
+	close(release)
+	wg.Wait()
+	assert.NotContains(t, api.messages(), "C1:dropped")
+}
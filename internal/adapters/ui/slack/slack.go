@@ -1,9 +1,14 @@
 package slack
 
 import (
+	"bytes"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/andrewhowdencom/vox/internal/domain"
 	"github.com/andrewhowdencom/vox/internal/domain/interview"
 	"github.com/slack-go/slack"
 )
@@ -14,37 +19,355 @@ type ChannelID string
 // UserID is a type for Slack user IDs.
 type UserID string
 
+// Action IDs of the Block Kit buttons posted with each question. Each choice's action ID starts with
+// ActionChoice, followed by its position.
+const (
+	ActionChoice = "vox_choice"
+	ActionSkip   = "vox_skip"
+	ActionWrapUp = "vox_wrap_up"
+)
+
+// MaxAttachmentSize is the largest file a participant can send with an answer. Larger files are left out.
+const MaxAttachmentSize = 20 << 20
+
+// Limits on the length of Block Kit text. Slack rejects a message with longer text as invalid_blocks.
+const (
+	maxSectionText = 3000
+	maxButtonText  = 75
+	maxButtonValue = 2000
+)
+
+// controlsSuffix is appended to a question's block ID for the block holding the interview controls.
+const controlsSuffix = "_controls"
+
+// filesAnswer is an answer the participant sent files with.
+type filesAnswer struct {
+	text        string
+	attachments []domain.Attachment
+}
+
+// action is the outcome of a button pressed by the participant.
+type action struct {
+	// blockID is the block ID of the question the button was pressed on.
+	blockID string
+	answer  string
+	err     error
+}
+
 // UI handles the user interface for the interview in Slack.
 type UI struct {
 	Client     SlackClient
 	ChannelID  ChannelID
 	UserID     UserID
 	AnswerChan chan string
+	// ThreadTS is the timestamp of the thread the interview is held in, or empty for a direct message.
+	ThreadTS string
+
+	// observers are sent the summary when the interview finishes.
+	observers []ChannelID
+	actions   chan action
+	withFiles chan filesAnswer
+	// remindAfter and abandonAfter are how long a question can go unanswered before the participant is
+	// reminded, and before the interview is abandoned. Zero means never.
+	remindAfter  time.Duration
+	abandonAfter time.Duration
+	// cancelled is closed once the interview is cancelled.
+	cancelled  chan struct{}
+	cancelOnce sync.Once
+	// closed is closed once the interview has finished.
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu sync.Mutex
+	// asked counts the questions posted, so each can be given its own block ID.
+	asked int
+	// waiting is the block ID of the question waiting for an answer, or empty if there is none.
+	waiting string
+	// attachments are the files sent with the last answer.
+	attachments []domain.Attachment
+}
+
+// Option configures optional behaviour of a UI.
+type Option func(*UI)
+
+// WithThread holds the interview in the thread with the given timestamp, instead of at the top level of the
+// channel.
+func WithThread(ts string) Option {
+	return func(s *UI) {
+		s.ThreadTS = ts
+	}
+}
+
+// WithObserver also sends the summary to the channel, such as a direct message with a PM who is observing the
+// interview.
+func WithObserver(channelID ChannelID) Option {
+	return func(s *UI) {
+		s.observers = append(s.observers, channelID)
+	}
+}
+
+// WithQuestionsAsked numbers the questions on from those already asked, such as when an interview is resumed,
+// so buttons on the earlier questions are still ignored.
+func WithQuestionsAsked(n int) Option {
+	return func(s *UI) {
+		s.asked = n
+	}
+}
+
+// WithIdle reminds the participant once a question has gone unanswered for remindAfter, and abandons the
+// interview once it has gone unanswered for abandonAfter. Either can be zero to turn it off.
+func WithIdle(remindAfter, abandonAfter time.Duration) Option {
+	return func(s *UI) {
+		s.remindAfter = remindAfter
+		s.abandonAfter = abandonAfter
+	}
 }
 
 // New creates a new SlackUI.
-func New(client SlackClient, channelID ChannelID, userID UserID) *UI {
-	return &UI{
+func New(client SlackClient, channelID ChannelID, userID UserID, opts ...Option) *UI {
+	s := &UI{
 		Client:     client,
 		ChannelID:  channelID,
 		UserID:     userID,
 		AnswerChan: make(chan string),
+		actions:    make(chan action, 1),
+		withFiles:  make(chan filesAnswer),
+		cancelled:  make(chan struct{}),
+		closed:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// post posts a message to the interview's channel, in its thread if it has one.
+func (s *UI) post(options ...slack.MsgOption) error {
+	if s.ThreadTS != "" {
+		options = append(options, slack.MsgOptionTS(s.ThreadTS))
 	}
+	_, _, err := s.Client.PostMessage(string(s.ChannelID), options...)
+	return err
+}
+
+// Observers returns the channels that are sent the summary when the interview finishes.
+func (s *UI) Observers() []ChannelID {
+	return append([]ChannelID(nil), s.observers...)
+}
+
+// Say posts a message to the participant that doesn't need an answer.
+func (s *UI) Say(text string) error {
+	if err := s.post(slack.MsgOptionText(text, false)); err != nil {
+		return fmt.Errorf("failed to post message to slack: %w", err)
+	}
+	return nil
 }
 
 // Ask sends a question to the user on Slack and waits for their answer.
 func (s *UI) Ask(question string) (string, error) {
-	slog.Debug("Asking question on slack", "channel_id", s.ChannelID, "user_id", s.UserID, "question", question)
-	_, _, err := s.Client.PostMessage(string(s.ChannelID), slack.MsgOptionText(question, false))
+	return s.AskQuestion(interview.Question{Text: question})
+}
+
+// AskQuestion sends a question to the user on Slack, with buttons for its choices and to skip it or wrap up the
+// interview, and waits for them to answer by message or by pressing a button.
+func (s *UI) AskQuestion(question interview.Question) (string, error) {
+	select {
+	case <-s.cancelled:
+		return "", s.endCancelled()
+	default:
+	}
+
+	s.mu.Lock()
+	s.asked++
+	blockID := fmt.Sprintf("vox_question_%d", s.asked)
+	s.attachments = nil
+	s.mu.Unlock()
+
+	slog.Debug("Asking question on slack", "channel_id", s.ChannelID, "user_id", s.UserID, "question", question.Text)
+	err := s.post(
+		slack.MsgOptionText(question.String(), false),
+		slack.MsgOptionBlocks(questionBlocks(blockID, question)...),
+	)
 	if err != nil {
 		slog.Error("Failed to post message to slack", "error", err, "channel_id", s.ChannelID, "user_id", s.UserID)
 		return "", fmt.Errorf("failed to post message to slack: %w", err)
 	}
-	// Wait for the answer from the event handler via the channel
+
+	s.mu.Lock()
+	s.waiting = blockID
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.waiting = ""
+		// A button pressed while the answer was being taken some other way is dropped, so it isn't taken as
+		// the answer to the next question.
+		select {
+		case <-s.actions:
+		default:
+		}
+	}()
+
+	var remind, abandon <-chan time.Time
+	if s.remindAfter > 0 {
+		timer := time.NewTimer(s.remindAfter)
+		defer timer.Stop()
+		remind = timer.C
+	}
+	if s.abandonAfter > 0 {
+		timer := time.NewTimer(s.abandonAfter)
+		defer timer.Stop()
+		abandon = timer.C
+	}
+
+	// Wait for the answer from the event handler via the channel, or for a button to be pressed
 	slog.Debug("Waiting for answer from user", "channel_id", s.ChannelID, "user_id", s.UserID)
-	answer := <-s.AnswerChan
-	slog.Debug("Received answer from user", "channel_id", s.ChannelID, "user_id", s.UserID, "answer", answer)
-	return answer, nil
+	for {
+		select {
+		case answer := <-s.AnswerChan:
+			slog.Debug("Received answer from user", "channel_id", s.ChannelID, "user_id", s.UserID, "answer", answer)
+			return answer, nil
+		case answer := <-s.withFiles:
+			slog.Debug("Received answer with files from user", "channel_id", s.ChannelID, "user_id", s.UserID, "answer", answer.text, "files", len(answer.attachments))
+			s.mu.Lock()
+			s.attachments = answer.attachments
+			s.mu.Unlock()
+			return answer.text, nil
+		case a := <-s.actions:
+			if a.blockID != blockID {
+				continue
+			}
+			slog.Debug("Received action from user", "channel_id", s.ChannelID, "user_id", s.UserID, "answer", a.answer, "error", a.err)
+			return a.answer, a.err
+		case <-remind:
+			remind = nil
+			slog.Debug("Reminding idle user", "channel_id", s.ChannelID, "user_id", s.UserID)
+			text := fmt.Sprintf("<@%s>, are you still there? Reply to the question above to carry on, or press *Wrap up* to finish with the answers you've given so far.", s.UserID)
+			if err := s.post(slack.MsgOptionText(text, false)); err != nil {
+				slog.Error("Error reminding idle user", "error", err, "channel_id", s.ChannelID, "user_id", s.UserID)
+			}
+		case <-abandon:
+			slog.Info("Abandoning interview with idle user", "channel_id", s.ChannelID, "user_id", s.UserID)
+			text := "This interview has been closed, as we haven't heard from you in a while. Thanks for the answers you gave, they've been saved."
+			if err := s.post(slack.MsgOptionText(text, false)); err != nil {
+				slog.Error("Error closing idle interview", "error", err, "channel_id", s.ChannelID, "user_id", s.UserID)
+			}
+			return "", interview.ErrAbandoned
+		case <-s.cancelled:
+			return "", s.endCancelled()
+		}
+	}
+}
+
+// Cancel ends the interview without saving it. A question waiting for an answer returns straight away, and
+// any later question isn't asked.
+func (s *UI) Cancel() {
+	s.cancelOnce.Do(func() { close(s.cancelled) })
+}
+
+// Answer passes a message from the participant to the question waiting for an answer, or to the next question
+// to be asked. It reports false if the interview is cancelled or closed before the message is taken.
+func (s *UI) Answer(text string) bool {
+	select {
+	case s.AnswerChan <- text:
+		return true
+	case <-s.cancelled:
+		return false
+	case <-s.closed:
+		return false
+	}
+}
+
+// AnswerWithFiles is like Answer, but also downloads the files the participant sent with the message, such as
+// screenshots, so they are kept with the answer. Files that can't be downloaded are left out.
+func (s *UI) AnswerWithFiles(text string, files []slack.File) bool {
+	attachments := s.download(files)
+	if len(attachments) == 0 {
+		return s.Answer(text)
+	}
+
+	select {
+	case s.withFiles <- filesAnswer{text: text, attachments: attachments}:
+		return true
+	case <-s.cancelled:
+		return false
+	case <-s.closed:
+		return false
+	}
+}
+
+// download downloads the files with the client, if it is able to.
+func (s *UI) download(files []slack.File) []domain.Attachment {
+	downloader, ok := s.Client.(FileDownloader)
+	if !ok {
+		return nil
+	}
+
+	var attachments []domain.Attachment
+	for _, file := range files {
+		if file.Size > MaxAttachmentSize {
+			slog.Warn("Leaving out file that is too large", "channel_id", s.ChannelID, "user_id", s.UserID, "name", file.Name, "size", file.Size)
+			continue
+		}
+		url := file.URLPrivateDownload
+		if url == "" {
+			url = file.URLPrivate
+		}
+		var buf bytes.Buffer
+		if err := downloader.GetFile(url, &buf); err != nil {
+			slog.Error("Error downloading file", "error", err, "channel_id", s.ChannelID, "user_id", s.UserID, "name", file.Name)
+			continue
+		}
+		attachments = append(attachments, domain.Attachment{Name: file.Name, MimeType: file.Mimetype, Data: buf.Bytes()})
+	}
+	return attachments
+}
+
+// Attachments returns the files sent with the answer last returned by Ask.
+func (s *UI) Attachments() []domain.Attachment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attachments
+}
+
+// Close marks the interview as finished, so messages still waiting to be passed to it are dropped.
+func (s *UI) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// endCancelled tells the participant the interview was cancelled.
+func (s *UI) endCancelled() error {
+	if err := s.post(slack.MsgOptionText("This interview was cancelled, and your answers have been discarded.", false)); err != nil {
+		slog.Error("Error ending cancelled interview", "error", err, "channel_id", s.ChannelID, "user_id", s.UserID)
+	}
+	return interview.ErrCancelled
+}
+
+// HandleAction handles a button pressed on a question. It reports whether the action was for the question
+// waiting for an answer; buttons on earlier questions are ignored.
+func (s *UI) HandleAction(actionID, blockID, value string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiting == "" || strings.TrimSuffix(blockID, controlsSuffix) != s.waiting {
+		return false
+	}
+
+	a := action{blockID: s.waiting}
+	switch {
+	case strings.HasPrefix(actionID, ActionChoice):
+		a.answer = value
+	case actionID == ActionSkip:
+		a.err = interview.ErrSkipped
+	case actionID == ActionWrapUp:
+		a.err = interview.ErrStopped
+	default:
+		return false
+	}
+
+	// Only the first button pressed counts.
+	s.waiting = ""
+	s.actions <- a
+	return true
 }
 
 // DisplaySummary sends the interview summary to the user on Slack.
@@ -53,12 +376,60 @@ func (s *UI) DisplaySummary(summary string) {
 		formattedSummary := fmt.Sprintf("*--- Interview Summary ---*\n%s\n*-----------------------*", summary)
 		slog.Debug("Displaying summary on slack", "channel_id", s.ChannelID, "user_id", s.UserID, "summary", formattedSummary)
 
-		_, _, err := s.Client.PostMessage(string(s.ChannelID), slack.MsgOptionText(formattedSummary, false))
-		if err != nil {
+		if err := s.post(slack.MsgOptionText(formattedSummary, false)); err != nil {
 			slog.Error("Error displaying summary", "error", err, "channel_id", s.ChannelID, "user_id", s.UserID)
 		}
+		for _, observer := range s.observers {
+			text := fmt.Sprintf("Interview with <@%s> finished.\n%s", s.UserID, formattedSummary)
+			if _, _, err := s.Client.PostMessage(string(observer), slack.MsgOptionText(text, false)); err != nil {
+				slog.Error("Error sending summary to observer", "error", err, "channel_id", observer, "user_id", s.UserID)
+			}
+		}
+	}
+}
+
+// questionBlocks builds the Block Kit message for a question: the question itself, a button for each of its
+// choices, its progress through the interview, and buttons to skip it or wrap up.
+func questionBlocks(blockID string, question interview.Question) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, truncate(question.Text, maxSectionText), false, false), nil, nil),
+	}
+
+	if len(question.Choices) > 0 {
+		buttons := make([]slack.BlockElement, 0, len(question.Choices))
+		for i, choice := range question.Choices {
+			button := slack.NewButtonBlockElement(fmt.Sprintf("%s_%d", ActionChoice, i), truncate(choice, maxButtonValue),
+				slack.NewTextBlockObject(slack.PlainTextType, truncate(choice, maxButtonText), false, false))
+			buttons = append(buttons, button)
+		}
+		blocks = append(blocks, slack.NewActionBlock(blockID, buttons...))
+	}
+
+	if question.Number > 0 {
+		progress := fmt.Sprintf("Question %d", question.Number)
+		if question.Total > 0 {
+			progress += fmt.Sprintf(" of %d", question.Total)
+		}
+		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, progress, false, false)))
+	}
+
+	blocks = append(blocks, slack.NewActionBlock(blockID+controlsSuffix,
+		slack.NewButtonBlockElement(ActionSkip, "skip", slack.NewTextBlockObject(slack.PlainTextType, "Skip", false, false)),
+		slack.NewButtonBlockElement(ActionWrapUp, "wrap_up", slack.NewTextBlockObject(slack.PlainTextType, "Wrap up", false, false)),
+	))
+	return blocks
+}
+
+// truncate shortens the text to at most n characters, marking where it was cut.
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
 	}
+	return string(runes[:n-1]) + "…"
 }
 
 // Ensure UI implements the domain interface.
 var _ interview.InterviewUI = (*UI)(nil)
+var _ interview.QuestionUI = (*UI)(nil)
+var _ interview.AttachmentUI = (*UI)(nil)
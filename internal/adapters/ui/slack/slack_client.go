@@ -1,6 +1,10 @@
 package slack
 
-import "github.com/slack-go/slack"
+import (
+	"io"
+
+	"github.com/slack-go/slack"
+)
 
 // SlackClient is an interface that wraps the slack.Client.
 // This is useful for testing and abstracting away the concrete implementation.
@@ -8,5 +12,33 @@ type SlackClient interface {
 	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
 }
 
-// Ensure the real client implements the interface
-var _ SlackClient = (*slack.Client)(nil)
+// FileDownloader is implemented by clients that can download the files participants send, such as slack.Client,
+// which uses the bot token.
+type FileDownloader interface {
+	GetFile(downloadURL string, writer io.Writer) error
+}
+
+// AppClient is the part of the Slack API used by the app around the interviews it holds, such as to answer slash
+// commands and show the App Home. It is implemented by the Dispatcher, which queues only PostMessage and
+// PostEphemeral, including the summaries sent to observers. The other calls, such as UploadFileV2, OpenView and
+// PublishView, go directly to Slack, as they are made once in reply to a user rather than in bursts.
+type AppClient interface {
+	SlackClient
+	FileDownloader
+	PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error)
+	OpenConversation(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	GetPermalink(params *slack.PermalinkParameters) (string, error)
+	GetUserGroupMembers(userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error)
+	UploadFileV2(params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+	OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
+	PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error)
+}
+
+// Ensure the real client, and the dispatcher that queues its messages, implement the interface
+var (
+	_ SlackClient = (*slack.Client)(nil)
+	_ SlackClient = (*Dispatcher)(nil)
+)
+
+var _ FileDownloader = (*slack.Client)(nil)
+var _ AppClient = (*Dispatcher)(nil)
@@ -1,13 +1,20 @@
 package slack_test
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
 	goslack "github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSlackClient is a mock implementation of the SlackClient interface.
@@ -62,3 +69,298 @@ func TestSlackUI_DisplaySummary(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 }
+
+func TestSlackUI_HandleAction(t *testing.T) {
+	// ask asks the question in the background, returning the block ID it was posted with, and the result once
+	// it has been answered.
+	ask := func(t *testing.T, ui *slack.UI, mockClient *MockSlackClient, question interview.Question) (string, <-chan error, *string) {
+		t.Helper()
+		posted := make(chan string, 1)
+		mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil).Run(func(args mock.Arguments) {
+			_, values, err := goslack.UnsafeApplyMsgOptions("", "C12345", "", args.Get(1).([]goslack.MsgOption)...)
+			if err == nil {
+				posted <- values.Get("blocks")
+			}
+		}).Once()
+
+		var answer string
+		done := make(chan error, 1)
+		go func() {
+			var err error
+			answer, err = ui.AskQuestion(question)
+			done <- err
+		}()
+
+		var blocks []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(<-posted), &blocks))
+		blockID := blocks[len(blocks)-1]["block_id"].(string)
+		return strings.TrimSuffix(blockID, "_controls"), done, &answer
+	}
+
+	t.Run("should answer with the chosen option", func(t *testing.T) {
+		mockClient := new(MockSlackClient)
+		ui := slack.New(mockClient, "C12345", "U12345")
+		blockID, done, answer := ask(t, ui, mockClient, interview.ParseQuestion("Which plan? [Free | Pro]"))
+
+		assert.Eventually(t, func() bool { return ui.HandleAction(slack.ActionChoice+"_1", blockID, "Pro") }, time.Second, time.Millisecond)
+		require.NoError(t, <-done)
+		assert.Equal(t, "Pro", *answer)
+
+		// Buttons on a question that has been answered are ignored.
+		assert.False(t, ui.HandleAction(slack.ActionChoice+"_0", blockID, "Free"))
+	})
+
+	t.Run("should skip the question or wrap up", func(t *testing.T) {
+		mockClient := new(MockSlackClient)
+		ui := slack.New(mockClient, "C12345", "U12345")
+
+		blockID, done, _ := ask(t, ui, mockClient, interview.Question{Text: "What do you like?", Number: 1, Total: 2})
+		assert.Eventually(t, func() bool { return ui.HandleAction(slack.ActionSkip, blockID+"_controls", "skip") }, time.Second, time.Millisecond)
+		assert.ErrorIs(t, <-done, interview.ErrSkipped)
+
+		blockID, done, _ = ask(t, ui, mockClient, interview.Question{Text: "What would you change?", Number: 2, Total: 2})
+		assert.Eventually(t, func() bool { return ui.HandleAction(slack.ActionWrapUp, blockID+"_controls", "wrap_up") }, time.Second, time.Millisecond)
+		assert.ErrorIs(t, <-done, interview.ErrStopped)
+	})
+
+	t.Run("should shorten text too long for Block Kit", func(t *testing.T) {
+		mockClient := new(MockSlackClient)
+		ui := slack.New(mockClient, "C12345", "U12345")
+		posted := make(chan string, 1)
+		mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil).Run(func(args mock.Arguments) {
+			_, values, err := goslack.UnsafeApplyMsgOptions("", "C12345", "", args.Get(1).([]goslack.MsgOption)...)
+			if err == nil {
+				posted <- values.Get("blocks")
+			}
+		}).Once()
+
+		long := strings.Repeat("a", 100)
+		done := make(chan string, 1)
+		go func() {
+			answer, _ := ui.AskQuestion(interview.Question{Text: strings.Repeat("q", 4000), Choices: []string{long, "Pro"}})
+			done <- answer
+		}()
+
+		var blocks []struct {
+			BlockID string `json:"block_id"`
+			Text    *struct {
+				Text string `json:"text"`
+			} `json:"text"`
+			Elements []struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+				Value string `json:"value"`
+			} `json:"elements"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(<-posted), &blocks))
+		assert.Equal(t, strings.Repeat("q", 2999)+"…", blocks[0].Text.Text)
+		assert.Equal(t, strings.Repeat("a", 74)+"…", blocks[1].Elements[0].Text.Text)
+		assert.Equal(t, long, blocks[1].Elements[0].Value)
+		assert.Equal(t, "Pro", blocks[1].Elements[1].Text.Text)
+
+		// The whole choice is still the answer.
+		assert.Eventually(t, func() bool { return ui.HandleAction(slack.ActionChoice+"_0", blocks[1].BlockID, long) }, time.Second, time.Millisecond)
+		assert.Equal(t, long, <-done)
+	})
+}
+
+func TestSlackUI_ButtonPressedWithMessage(t *testing.T) {
+	// Both the message and the button are waiting when the question takes its answer, so either can be taken.
+	// Whichever isn't must not be taken as the answer to the next question.
+	for range 20 {
+		mockClient := new(MockSlackClient)
+		reminding, release := make(chan struct{}), make(chan struct{})
+		mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil).Once()
+		mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil).Run(func(mock.Arguments) {
+			close(reminding)
+			<-release
+		}).Once()
+		mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil)
+		ui := slack.New(mockClient, "C12345", "U12345", slack.WithIdle(time.Millisecond, 0))
+
+		first := make(chan error, 1)
+		go func() {
+			_, err := ui.AskQuestion(interview.ParseQuestion("Which plan? [Free | Pro]"))
+			first <- err
+		}()
+		<-reminding
+		go ui.Answer("Free, I think")
+		time.Sleep(time.Millisecond)
+		require.True(t, ui.HandleAction(slack.ActionChoice+"_1", "vox_question_1", "Pro"))
+		close(release)
+		require.NoError(t, <-first)
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			ui.Answer("It's cheaper.")
+		}()
+		answer, err := ui.AskQuestion(interview.Question{Text: "Why?"})
+		require.NoError(t, err)
+		assert.NotEqual(t, "Pro", answer)
+		ui.Close()
+	}
+}
+
+func TestSlackUI_Thread(t *testing.T) {
+	t.Run("should reply in the thread and send the summary to observers", func(t *testing.T) {
+		mockClient := new(MockSlackClient)
+		ui := slack.New(mockClient, "C12345", "U12345", slack.WithThread("1700000000.000100"), slack.WithObserver("D999"))
+
+		inspect := func(channelID string) func(mock.Arguments) {
+			return func(args mock.Arguments) {
+				_, values, err := goslack.UnsafeApplyMsgOptions("", channelID, "", args.Get(1).([]goslack.MsgOption)...)
+				require.NoError(t, err)
+				if channelID == "C12345" {
+					assert.Equal(t, "1700000000.000100", values.Get("thread_ts"))
+				} else {
+					assert.Empty(t, values.Get("thread_ts"))
+					assert.Contains(t, values.Get("text"), "Interview with <@U12345> finished.")
+				}
+			}
+		}
+		mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil).Run(inspect("C12345"))
+		mockClient.On("PostMessage", "D999", mock.Anything).Return("", "", nil).Run(inspect("D999")).Once()
+
+		ui.DisplaySummary("This is a summary.")
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestSlackUI_WithQuestionsAsked(t *testing.T) {
+	mockClient := new(MockSlackClient)
+	ui := slack.New(mockClient, "C12345", "U12345", slack.WithQuestionsAsked(2))
+
+	blocks := make(chan string, 1)
+	mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil).Run(func(args mock.Arguments) {
+		_, values, err := goslack.UnsafeApplyMsgOptions("", "C12345", "", args.Get(1).([]goslack.MsgOption)...)
+		require.NoError(t, err)
+		blocks <- values.Get("blocks")
+	}).Once()
+
+	go ui.AskQuestion(interview.ParseQuestion("Which plan? [Free | Pro]"))
+	assert.Contains(t, <-blocks, `"block_id":"vox_question_3"`)
+
+	// Buttons on questions asked before the interview was resumed are ignored.
+	assert.False(t, ui.HandleAction(slack.ActionChoice+"_0", "vox_question_1", "Free"))
+	assert.Eventually(t, func() bool { return ui.HandleAction(slack.ActionChoice+"_1", "vox_question_3", "Pro") }, time.Second, time.Millisecond)
+}
+
+func TestSlackUI_Idle(t *testing.T) {
+	// texts records the text of each message posted.
+	texts := func(mockClient *MockSlackClient) chan string {
+		posted := make(chan string, 10)
+		mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil).Run(func(args mock.Arguments) {
+			_, values, err := goslack.UnsafeApplyMsgOptions("", "C12345", "", args.Get(1).([]goslack.MsgOption)...)
+			require.NoError(t, err)
+			posted <- values.Get("text")
+		})
+		return posted
+	}
+
+	t.Run("should remind the participant, then abandon the interview", func(t *testing.T) {
+		mockClient := new(MockSlackClient)
+		posted := texts(mockClient)
+		ui := slack.New(mockClient, "C12345", "U12345", slack.WithIdle(10*time.Millisecond, 50*time.Millisecond))
+
+		_, err := ui.Ask("What do you like?")
+		assert.ErrorIs(t, err, interview.ErrAbandoned)
+
+		assert.Equal(t, "What do you like?", <-posted)
+		assert.Contains(t, <-posted, "<@U12345>, are you still there?")
+		assert.Contains(t, <-posted, "This interview has been closed")
+		assert.Empty(t, posted)
+	})
+
+	t.Run("should wait for an answer without an idle policy", func(t *testing.T) {
+		mockClient := new(MockSlackClient)
+		posted := texts(mockClient)
+		ui := slack.New(mockClient, "C12345", "U12345")
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			ui.AnswerChan <- "The speed."
+		}()
+		answer, err := ui.Ask("What do you like?")
+		require.NoError(t, err)
+		assert.Equal(t, "The speed.", answer)
+		assert.Len(t, posted, 1)
+	})
+}
+
+func TestSlackUI_Cancel(t *testing.T) {
+	mockClient := new(MockSlackClient)
+	mockClient.On("PostMessage", "C12345", mock.Anything).Return("", "", nil)
+	ui := slack.New(mockClient, "C12345", "U12345")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := ui.Ask("What do you like?")
+		done <- err
+	}()
+	ui.Cancel()
+	ui.Cancel()
+	assert.ErrorIs(t, <-done, interview.ErrCancelled)
+
+	// Later questions aren't asked.
+	_, err := ui.Ask("What would you change?")
+	assert.ErrorIs(t, err, interview.ErrCancelled)
+	mockClient.AssertNotCalled(t, "PostMessage", "C12345", mock.MatchedBy(func(options []goslack.MsgOption) bool {
+		_, values, _ := goslack.UnsafeApplyMsgOptions("", "C12345", "", options...)
+		return values.Get("text") == "What would you change?"
+	}))
+}
+
+func TestSlackUI_Answer(t *testing.T) {
+	t.Run("should pass the message to the question waiting for an answer", func(t *testing.T) {
+		ui := slack.New(nil, "C12345", "U12345")
+		go func() { assert.True(t, ui.Answer("The speed.")) }()
+		assert.Equal(t, "The speed.", <-ui.AnswerChan)
+	})
+
+	t.Run("should drop the message once the interview has finished", func(t *testing.T) {
+		ui := slack.New(nil, "C12345", "U12345")
+		ui.Close()
+		assert.False(t, ui.Answer("Too late."))
+	})
+}
+
+// downloadingClient is a MockSlackClient that can also download files, which hold their URL.
+type downloadingClient struct {
+	MockSlackClient
+}
+
+func (c *downloadingClient) GetFile(downloadURL string, writer io.Writer) error {
+	if strings.HasSuffix(downloadURL, "missing") {
+		return errors.New("file_not_found")
+	}
+	_, err := io.WriteString(writer, downloadURL)
+	return err
+}
+
+func TestSlackUI_AnswerWithFiles(t *testing.T) {
+	client := &downloadingClient{}
+	client.On("PostMessage", "C12345", mock.Anything).Return("", "", nil)
+	ui := slack.New(client, "C12345", "U12345")
+
+	go func() {
+		assert.True(t, ui.AnswerWithFiles("Here's my dashboard.", []goslack.File{
+			{Name: "dashboard.png", Mimetype: "image/png", URLPrivateDownload: "https://files.slack.com/dashboard.png"},
+			{Name: "gone.png", Mimetype: "image/png", URLPrivateDownload: "https://files.slack.com/missing"},
+			{Name: "huge.mov", Mimetype: "video/quicktime", Size: slack.MaxAttachmentSize + 1},
+		}))
+	}()
+	answer, err := ui.Ask("What does your dashboard look like?")
+	require.NoError(t, err)
+	assert.Equal(t, "Here's my dashboard.", answer)
+	assert.Equal(t, []domain.Attachment{
+		{Name: "dashboard.png", MimeType: "image/png", Data: []byte("https://files.slack.com/dashboard.png")},
+	}, ui.Attachments())
+
+	t.Run("should forget the files once the next question is asked", func(t *testing.T) {
+		go func() { assert.True(t, ui.AnswerWithFiles("Nothing else.", nil)) }()
+		_, err := ui.Ask("Anything else?")
+		require.NoError(t, err)
+		assert.Empty(t, ui.Attachments())
+	})
+}
@@ -0,0 +1,95 @@
+package sms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultAPIURL is the address of the Twilio REST API.
+const DefaultAPIURL = "https://api.twilio.com"
+
+// errorUnsubscribed is the code of the error returned for a message to someone who has opted out.
+const errorUnsubscribed = 21610
+
+var (
+	// ErrAPI is returned when the messaging API rejects a request.
+	ErrAPI = errors.New("messaging api request failed")
+	// ErrOptedOut is returned for a message to someone who has opted out of messages from the sender.
+	ErrOptedOut = errors.New("recipient has opted out of messages")
+)
+
+// Messenger sends text and WhatsApp messages.
+type Messenger interface {
+	SendMessage(from, to, body string) error
+}
+
+// Client sends messages with the Twilio REST API, or an API compatible with it.
+type Client struct {
+	accountSID string
+	authToken  string
+	apiURL     string
+	httpClient *http.Client
+}
+
+// ClientOption configures optional behaviour of a Client.
+type ClientOption func(*Client)
+
+// WithAPIURL calls the API at the URL instead of DefaultAPIURL, such as a local stand-in.
+func WithAPIURL(apiURL string) ClientOption {
+	return func(c *Client) {
+		c.apiURL = strings.TrimSuffix(apiURL, "/")
+	}
+}
+
+// NewClient creates a client that authenticates as the account.
+func NewClient(accountSID, authToken string, opts ...ClientOption) *Client {
+	c := &Client{
+		accountSID: accountSID,
+		authToken:  authToken,
+		apiURL:     DefaultAPIURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SendMessage sends the message body from the sender to the recipient. WhatsApp addresses are prefixed with
+// "whatsapp:".
+func (c *Client) SendMessage(from, to, body string) error {
+	form := url.Values{"From": {from}, "To": {to}, "Body": {body}}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", c.apiURL, url.PathEscape(c.accountSID))
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.SetBasicAuth(c.accountSID, c.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not call messaging api: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	var apiErr struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	json.NewDecoder(resp.Body).Decode(&apiErr)
+	if apiErr.Code == errorUnsubscribed {
+		return fmt.Errorf("%w: %s", ErrOptedOut, to)
+	}
+	return fmt.Errorf("%w: %s: %s (code %d)", ErrAPI, resp.Status, apiErr.Message, apiErr.Code)
+}
+
+// Ensure Client implements Messenger.
+var _ Messenger = (*Client)(nil)
@@ -0,0 +1,111 @@
+package sms
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits on the length of a single message. A text message holds 160 characters of the GSM 7-bit alphabet, or
+// 70 of any other, before the carrier splits it; WhatsApp messages sent through Twilio hold 1600.
+const (
+	gsmLimit      = 160
+	unicodeLimit  = 70
+	whatsAppLimit = 1600
+)
+
+// gsmBasic holds the characters of the GSM 7-bit default alphabet, and gsmExtended those that take two.
+const (
+	gsmBasic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsmExtended = "^{}\\[~]|€\f"
+)
+
+// Segments splits the text into messages that each fit in a single text message, or a single WhatsApp message.
+// The text is split between words where it can, and each message is numbered, such as "(1/3)".
+func Segments(text string, whatsApp bool) []string {
+	text = strings.TrimSpace(text)
+	length, limit := gsmLength, gsmLimit
+	switch {
+	case whatsApp:
+		length, limit = utf8.RuneCountInString, whatsAppLimit
+	case !isGSM(text):
+		length, limit = utf16Length, unicodeLimit
+	}
+	if length(text) <= limit {
+		return []string{text}
+	}
+
+	// Leave room for the numbering, allowing for up to 99 messages.
+	limit -= len(" (99/99)")
+	var segments []string
+	for text != "" {
+		cut := split(text, limit, length)
+		segments = append(segments, strings.TrimSpace(text[:cut]))
+		text = strings.TrimSpace(text[cut:])
+	}
+	for i := range segments {
+		segments[i] += fmt.Sprintf(" (%d/%d)", i+1, len(segments))
+	}
+	return segments
+}
+
+// split returns where to cut the text so the part before it fits the limit: after the last space that fits,
+// or at the limit itself in a word too long to fit.
+func split(text string, limit int, length func(string) int) int {
+	if length(text) <= limit {
+		return len(text)
+	}
+	cut, lastSpace := 0, 0
+	for i, r := range text {
+		end := i + utf8.RuneLen(r)
+		if length(text[:end]) > limit {
+			break
+		}
+		cut = end
+		if r == ' ' || r == '\n' {
+			lastSpace = end
+		}
+	}
+	if lastSpace > 0 {
+		return lastSpace
+	}
+	if cut == 0 {
+		_, size := utf8.DecodeRuneInString(text)
+		return size
+	}
+	return cut
+}
+
+// isGSM reports whether the text can be sent in the GSM 7-bit alphabet.
+func isGSM(text string) bool {
+	for _, r := range text {
+		if !strings.ContainsRune(gsmBasic, r) && !strings.ContainsRune(gsmExtended, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// gsmLength returns the length of the text in the GSM 7-bit alphabet.
+func gsmLength(text string) int {
+	n := 0
+	for _, r := range text {
+		n++
+		if strings.ContainsRune(gsmExtended, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// utf16Length returns the length of the text in UCS-2, where characters outside the basic plane take two.
+func utf16Length(text string) int {
+	n := 0
+	for _, r := range text {
+		n++
+		if r > 0xFFFF {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,155 @@
+// Package sms runs interviews by text message and WhatsApp, through the Twilio REST API or an API compatible
+// with it. Questions are sent as messages split to fit the channel, and answers arrive on a signed webhook.
+package sms
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/chat"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// Replies that skip a question or wrap up the interview.
+const (
+	ReplySkip   = "skip"
+	ReplyWrapUp = "wrap up"
+)
+
+// WhatsAppPrefix marks an address as a WhatsApp number rather than a phone number for text messages.
+const WhatsAppPrefix = "whatsapp:"
+
+// HelpText is sent to participants who reply "HELP".
+const HelpText = `Reply to each question to answer it. Reply "skip" to skip a question, "wrap up" to finish the interview, or STOP to stop receiving messages.`
+
+// UI handles the user interface for an interview by text message or WhatsApp. Each question is sent as one or
+// more messages, and the participant's replies are passed to it with Answer.
+type UI struct {
+	Messenger Messenger
+	// From is the sender the messages are sent from, and To the participant they're sent to. WhatsApp
+	// addresses are prefixed with WhatsAppPrefix.
+	From string
+	To   string
+
+	*chat.Inbox
+}
+
+// New creates a UI for an interview with the participant, sending messages from the sender.
+func New(messenger Messenger, from, to string) *UI {
+	return &UI{
+		Messenger: messenger,
+		From:      from,
+		To:        to,
+		Inbox:     chat.NewInbox(),
+	}
+}
+
+// Say sends a message to the participant that doesn't need an answer, split into as many messages as it takes.
+func (u *UI) Say(text string) error {
+	for _, segment := range Segments(text, strings.HasPrefix(u.To, WhatsAppPrefix)) {
+		if err := u.Messenger.SendMessage(u.From, u.To, segment); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+	}
+	return nil
+}
+
+// Ask sends a question to the participant and waits for their answer.
+func (u *UI) Ask(question string) (string, error) {
+	return u.AskQuestion(interview.Question{Text: question})
+}
+
+// AskQuestion sends a question to the participant, with its choices numbered, and waits for them to answer.
+// A reply with a choice's number is taken as that choice.
+func (u *UI) AskQuestion(question interview.Question) (string, error) {
+	select {
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	default:
+	}
+
+	slog.Debug("Asking question by message", "to", u.To, "question", question.Text)
+	if err := u.Say(questionText(question)); err != nil {
+		slog.Error("Failed to send question", "error", err, "to", u.To)
+		if errors.Is(err, ErrOptedOut) {
+			return "", interview.ErrCancelled
+		}
+		return "", err
+	}
+
+	select {
+	case answer := <-u.Answers():
+		slog.Debug("Received answer from participant", "to", u.To, "answer", answer)
+		switch {
+		case strings.EqualFold(answer, ReplySkip):
+			return "", interview.ErrSkipped
+		case strings.EqualFold(answer, ReplyWrapUp):
+			return "", interview.ErrStopped
+		}
+		return choice(answer, question.Choices), nil
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	}
+}
+
+// questionText builds the message for a question: the question itself, its numbered choices, and its progress
+// through the interview.
+func questionText(question interview.Question) string {
+	var text strings.Builder
+	text.WriteString(question.Text)
+	if len(question.Choices) > 0 {
+		text.WriteString("\n")
+		for i, c := range question.Choices {
+			fmt.Fprintf(&text, "\n%d. %s", i+1, c)
+		}
+		text.WriteString("\nReply with a number.")
+	}
+	if question.Number > 0 {
+		fmt.Fprintf(&text, "\n\n(Question %d", question.Number)
+		if question.Total > 0 {
+			fmt.Fprintf(&text, " of %d", question.Total)
+		}
+		text.WriteString(`. Reply "skip" or "wrap up".)`)
+	}
+	return text.String()
+}
+
+// choice returns the choice a reply picks by its number or text, or the reply itself if it picks none.
+func choice(reply string, choices []string) string {
+	if n, err := strconv.Atoi(reply); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1]
+	}
+	for _, c := range choices {
+		if strings.EqualFold(reply, c) {
+			return c
+		}
+	}
+	return reply
+}
+
+// OptOut cancels the interview because the participant opted out of messages, so it ends without telling them.
+func (u *UI) OptOut() {
+	u.CancelSilently()
+}
+
+// endCancelled tells the participant the interview was cancelled, unless they opted out.
+func (u *UI) endCancelled() error {
+	return u.EndCancelled(u.Say, "to", u.To)
+}
+
+// DisplaySummary sends the interview summary to the participant.
+func (u *UI) DisplaySummary(summary string) {
+	if summary == "" {
+		return
+	}
+	if err := u.Say("Interview summary:\n\n" + summary); err != nil {
+		slog.Error("Error displaying summary", "error", err, "to", u.To)
+	}
+}
+
+// Ensure UI implements the domain interface.
+var _ interview.InterviewUI = (*UI)(nil)
+var _ interview.QuestionUI = (*UI)(nil)
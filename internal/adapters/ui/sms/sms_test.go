@@ -0,0 +1,181 @@
+package sms
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMessenger records the messages sent through it.
+type fakeMessenger struct {
+	mu   sync.Mutex
+	sent []string
+	err  error
+}
+
+func (f *fakeMessenger) SendMessage(from, to, body string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, body)
+	return nil
+}
+
+// messages returns the messages sent so far.
+func (f *fakeMessenger) messages() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sent...)
+}
+
+func TestClient_SendMessage(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" || user != "AC123" || pass != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		form = r.PostForm
+		if form.Get("To") == "+15550199" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":21610,"message":"Attempt to send to unsubscribed recipient"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("AC123", "token", WithAPIURL(server.URL+"/"))
+	require.NoError(t, client.SendMessage("+15550100", "whatsapp:+15550123", "Hello"))
+	assert.Equal(t, url.Values{"From": {"+15550100"}, "To": {"whatsapp:+15550123"}, "Body": {"Hello"}}, form)
+
+	t.Run("should report recipients who have opted out", func(t *testing.T) {
+		assert.ErrorIs(t, client.SendMessage("+15550100", "+15550199", "Hello"), ErrOptedOut)
+	})
+
+	t.Run("should report other errors", func(t *testing.T) {
+		err := NewClient("AC123", "wrong", WithAPIURL(server.URL)).SendMessage("+15550100", "+15550123", "Hello")
+		assert.ErrorIs(t, err, ErrAPI)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	// The example from Twilio's documentation on webhook security.
+	params := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+	webhookURL := "https://mycompany.com/myapp.php?foo=1&bar=2"
+	assert.NoError(t, Verify("12345", webhookURL, params, "0/KCTR6DLpKmkAf8muzZqo1nDgQ="))
+	assert.ErrorIs(t, Verify("12345", webhookURL, params, "bm90IHRoZSBzaWduYXR1cmU="), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("54321", webhookURL, params, "0/KCTR6DLpKmkAf8muzZqo1nDgQ="), ErrInvalidSignature)
+}
+
+func TestKeywords(t *testing.T) {
+	assert.True(t, IsOptOut("stop"))
+	assert.True(t, IsOptOut(" Unsubscribe. "))
+	assert.False(t, IsOptOut("stop sending so many questions"))
+	assert.True(t, IsOptIn("START"))
+	assert.True(t, IsHelp("help"))
+}
+
+func TestSegments(t *testing.T) {
+	t.Run("should send short text as one message", func(t *testing.T) {
+		assert.Equal(t, []string{"What do you like?"}, Segments(" What do you like? ", false))
+	})
+
+	t.Run("should split long text between words", func(t *testing.T) {
+		text := strings.Repeat("word ", 60)
+		segments := Segments(text, false)
+		require.Len(t, segments, 2)
+		for _, segment := range segments {
+			assert.LessOrEqual(t, gsmLength(segment), gsmLimit)
+			assert.NotContains(t, segment, "wor ")
+		}
+		assert.True(t, strings.HasSuffix(segments[0], "word (1/2)"))
+		assert.True(t, strings.HasSuffix(segments[1], "word (2/2)"))
+	})
+
+	t.Run("should fit fewer characters outside the GSM alphabet", func(t *testing.T) {
+		text := strings.Repeat("ü", 60) + " " + strings.Repeat("日本", 20)
+		segments := Segments(text, false)
+		require.Len(t, segments, 2)
+		for _, segment := range segments {
+			assert.LessOrEqual(t, utf16Length(segment), unicodeLimit)
+		}
+	})
+
+	t.Run("should fit more in a WhatsApp message", func(t *testing.T) {
+		text := strings.Repeat("日本 ", 300)
+		assert.Len(t, Segments(text, true), 1)
+		for _, segment := range Segments(text+text, true) {
+			assert.LessOrEqual(t, utf8.RuneCountInString(segment), whatsAppLimit)
+		}
+	})
+}
+
+func TestUI_AskQuestion(t *testing.T) {
+	// ask asks the question in the background.
+	ask := func(ui *UI, question interview.Question) (<-chan string, <-chan error) {
+		answers, result := make(chan string, 1), make(chan error, 1)
+		go func() {
+			answer, err := ui.AskQuestion(question)
+			answers <- answer
+			result <- err
+		}()
+		return answers, result
+	}
+
+	t.Run("should take a choice by its number", func(t *testing.T) {
+		messenger := &fakeMessenger{}
+		ui := New(messenger, "+15550100", "+15550123")
+		answers, result := ask(ui, interview.Question{Text: "Which plan?", Choices: []string{"Free", "Pro"}, Number: 1, Total: 2})
+		require.True(t, ui.Answer(" 2 "))
+		require.NoError(t, <-result)
+		assert.Equal(t, "Pro", <-answers)
+		assert.Equal(t, []string{"Which plan?\n\n1. Free\n2. Pro\nReply with a number.\n\n(Question 1 of 2. Reply \"skip\" or \"wrap up\".)"}, messenger.messages())
+	})
+
+	t.Run("should skip and wrap up", func(t *testing.T) {
+		ui := New(&fakeMessenger{}, "+15550100", "+15550123")
+		_, result := ask(ui, interview.Question{Text: "Why?"})
+		require.True(t, ui.Answer("Skip"))
+		assert.ErrorIs(t, <-result, interview.ErrSkipped)
+		_, result = ask(ui, interview.Question{Text: "Why?"})
+		require.True(t, ui.Answer("WRAP UP"))
+		assert.ErrorIs(t, <-result, interview.ErrStopped)
+	})
+
+	t.Run("should end without a message when the participant opts out", func(t *testing.T) {
+		messenger := &fakeMessenger{}
+		ui := New(messenger, "+15550100", "+15550123")
+		_, result := ask(ui, interview.Question{Text: "Why?"})
+		require.Eventually(t, func() bool { return len(messenger.messages()) == 1 }, time.Second, time.Millisecond)
+		ui.OptOut()
+		assert.ErrorIs(t, <-result, interview.ErrCancelled)
+		assert.Len(t, messenger.messages(), 1)
+		assert.False(t, ui.Answer("Because."))
+	})
+
+	t.Run("should be cancelled when the recipient has opted out", func(t *testing.T) {
+		ui := New(&fakeMessenger{err: ErrOptedOut}, "+15550100", "+15550123")
+		_, err := ui.AskQuestion(interview.Question{Text: "Why?"})
+		assert.ErrorIs(t, err, interview.ErrCancelled)
+	})
+}
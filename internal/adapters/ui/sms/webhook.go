@@ -0,0 +1,74 @@
+package sms
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidSignature is returned for a webhook request that wasn't signed with the account's auth token.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Keywords people reply with to opt out of messages, opt back in, or ask for help. Twilio acts on the same
+// keywords for text messages, so vox must respect them too.
+var (
+	optOutKeywords = []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT", "REVOKE", "OPTOUT"}
+	optInKeywords  = []string{"START", "UNSTOP", "YES"}
+	helpKeywords   = []string{"HELP", "INFO"}
+)
+
+// Sign returns the signature of a webhook request to the URL with the form parameters, as sent in the
+// X-Twilio-Signature header: the URL followed by each parameter's name and value in order of name, signed
+// with HMAC-SHA1.
+func Sign(authToken, webhookURL string, params url.Values) string {
+	var data strings.Builder
+	data.WriteString(webhookURL)
+	for _, name := range slices.Sorted(func(yield func(string) bool) {
+		for name := range params {
+			if !yield(name) {
+				return
+			}
+		}
+	}) {
+		for _, value := range params[name] {
+			data.WriteString(name + value)
+		}
+	}
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(data.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a webhook request to the URL with the form parameters. The URL must be the
+// public address the request was sent to, as configured in Twilio.
+func Verify(authToken, webhookURL string, params url.Values, signature string) error {
+	if !hmac.Equal([]byte(Sign(authToken, webhookURL, params)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// isKeyword reports whether the message is one of the keywords, ignoring case and surrounding punctuation.
+func isKeyword(body string, keywords []string) bool {
+	return slices.Contains(keywords, strings.ToUpper(strings.Trim(body, " \t\r\n.!")))
+}
+
+// IsOptOut reports whether the message opts out of messages, such as "STOP".
+func IsOptOut(body string) bool {
+	return isKeyword(body, optOutKeywords)
+}
+
+// IsOptIn reports whether the message opts back in to messages, such as "START". "YES" is one of them, so it
+// should only be taken as opting in from someone who has opted out.
+func IsOptIn(body string) bool {
+	return isKeyword(body, optInKeywords)
+}
+
+// IsHelp reports whether the message asks for help.
+func IsHelp(body string) bool {
+	return isKeyword(body, helpKeywords)
+}
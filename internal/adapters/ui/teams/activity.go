@@ -0,0 +1,88 @@
+// Package teams provides an InterviewUI for Microsoft Teams, through the Bot Framework. Teams sends the bot
+// activities, such as messages, over HTTP, and the bot sends its own messages to the conversation through the
+// Bot Connector service that the activity came from.
+package teams
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Types of activity handled by the bot.
+const (
+	ActivityMessage = "message"
+	// ActivityConversationUpdate is sent when the bot is added to a conversation.
+	ActivityConversationUpdate = "conversationUpdate"
+)
+
+// ConversationPersonal is the type of a one-to-one conversation between a user and the bot.
+const ConversationPersonal = "personal"
+
+// mention matches a mention of the bot in a message, which Teams includes in the text in channels.
+var mention = regexp.MustCompile(`<at>[^<]*</at>`)
+
+// Activity is a Bot Framework activity, such as a message, with the fields vox uses.
+type Activity struct {
+	Type             string              `json:"type"`
+	ID               string              `json:"id,omitempty"`
+	ServiceURL       string              `json:"serviceUrl,omitempty"`
+	ChannelID        string              `json:"channelId,omitempty"`
+	From             ChannelAccount      `json:"from"`
+	Recipient        ChannelAccount      `json:"recipient"`
+	Conversation     ConversationAccount `json:"conversation"`
+	Text             string              `json:"text,omitempty"`
+	TextFormat       string              `json:"textFormat,omitempty"`
+	SuggestedActions *SuggestedActions   `json:"suggestedActions,omitempty"`
+}
+
+// ChannelAccount is a user or bot in a conversation.
+type ChannelAccount struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// AADObjectID is the user's ID in Microsoft Entra ID.
+	AADObjectID string `json:"aadObjectId,omitempty"`
+}
+
+// ConversationAccount is the conversation an activity belongs to.
+type ConversationAccount struct {
+	ID string `json:"id,omitempty"`
+	// ConversationType is "personal", "groupChat" or "channel".
+	ConversationType string `json:"conversationType,omitempty"`
+	TenantID         string `json:"tenantId,omitempty"`
+}
+
+// SuggestedActions are buttons shown below a message, which disappear once one is pressed.
+type SuggestedActions struct {
+	// To lists the IDs of the users the actions are shown to.
+	To      []string     `json:"to,omitempty"`
+	Actions []CardAction `json:"actions"`
+}
+
+// CardAction is a button. An "imBack" action sends its value back to the bot as a message from the user.
+type CardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// ConversationReference is what's needed to send messages to a conversation outside of a reply to an
+// activity, known as proactive messaging.
+type ConversationReference struct {
+	ServiceURL     string
+	ConversationID string
+}
+
+// Reference returns the reference to the conversation the activity belongs to.
+func (a Activity) Reference() ConversationReference {
+	return ConversationReference{ServiceURL: a.ServiceURL, ConversationID: a.Conversation.ID}
+}
+
+// Personal reports whether the activity is from a one-to-one conversation with the bot.
+func (a Activity) Personal() bool {
+	return a.Conversation.ConversationType == ConversationPersonal
+}
+
+// PlainText returns the text of a message without any mentions of the bot.
+func (a Activity) PlainText() string {
+	return strings.TrimSpace(mention.ReplaceAllString(a.Text, ""))
+}
@@ -0,0 +1,234 @@
+package teams
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultOpenIDURL is where the Bot Framework publishes the keys it signs its tokens with.
+	DefaultOpenIDURL = "https://login.botframework.com/v1/.well-known/openidconfiguration"
+	// Issuer is the issuer of the tokens the Bot Framework sends with activities.
+	Issuer = "https://api.botframework.com"
+	// keysTTL is how long the signing keys are kept before they are fetched again.
+	keysTTL = 24 * time.Hour
+	// keysRefresh is how often the keys can be fetched early, when a token is signed with a key that isn't
+	// known, so forged tokens can't make the verifier fetch them over and over.
+	keysRefresh = 5 * time.Minute
+	// clockSkew is how far the clocks of the Bot Framework and the server can differ.
+	clockSkew = 5 * time.Minute
+)
+
+// ErrUnauthorized is returned for an activity that wasn't sent by the Bot Framework for this bot.
+var ErrUnauthorized = errors.New("activity is not authorized")
+
+// Verifier checks the token that the Bot Framework sends with each activity.
+type Verifier struct {
+	appID      string
+	openIDURL  string
+	httpClient *http.Client
+	// now returns the current time, and is replaced in tests.
+	now func() time.Time
+
+	mu sync.Mutex
+	// keys are the signing keys by their ID, with the channels each is endorsed for.
+	keys    map[string]signingKey
+	fetched time.Time
+}
+
+// signingKey is a key the Bot Framework signs tokens with.
+type signingKey struct {
+	key *rsa.PublicKey
+	// endorsements lists the channels, such as "msteams", the key can sign tokens for.
+	endorsements []string
+}
+
+// VerifierOption configures optional behaviour of a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithOpenIDURL fetches the signing keys from the OpenID metadata at the URL instead of DefaultOpenIDURL,
+// such as a local stand-in.
+func WithOpenIDURL(openIDURL string) VerifierOption {
+	return func(v *Verifier) {
+		v.openIDURL = openIDURL
+	}
+}
+
+// NewVerifier creates a verifier for activities sent to the bot with the given Microsoft App ID.
+func NewVerifier(appID string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		appID:      appID,
+		openIDURL:  DefaultOpenIDURL,
+		httpClient: http.DefaultClient,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify checks the Authorization header sent with the activity. The token must be signed by the Bot
+// Framework for the bot, be current, and have been issued for the service the activity came from.
+func (v *Verifier) Verify(authorization string, activity Activity) error {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return fmt.Errorf("%w: missing bearer token", ErrUnauthorized)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: malformed token", ErrUnauthorized)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return err
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrUnauthorized, header.Alg)
+	}
+	key, err := v.key(header.Kid)
+	if err != nil {
+		return err
+	}
+	if activity.ChannelID != "" && !slices.Contains(key.endorsements, activity.ChannelID) {
+		return fmt.Errorf("%w: key is not endorsed for %s", ErrUnauthorized, activity.ChannelID)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrUnauthorized)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key.key, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("%w: invalid signature", ErrUnauthorized)
+	}
+
+	var claims struct {
+		Issuer     string `json:"iss"`
+		Audience   string `json:"aud"`
+		Expires    int64  `json:"exp"`
+		NotBefore  int64  `json:"nbf"`
+		ServiceURL string `json:"serviceurl"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return err
+	}
+	now := v.now()
+	switch {
+	case claims.Issuer != Issuer:
+		return fmt.Errorf("%w: unexpected issuer %q", ErrUnauthorized, claims.Issuer)
+	case claims.Audience != v.appID:
+		return fmt.Errorf("%w: token is for another bot", ErrUnauthorized)
+	case now.After(time.Unix(claims.Expires, 0).Add(clockSkew)):
+		return fmt.Errorf("%w: token has expired", ErrUnauthorized)
+	case now.Add(clockSkew).Before(time.Unix(claims.NotBefore, 0)):
+		return fmt.Errorf("%w: token is not valid yet", ErrUnauthorized)
+	case claims.ServiceURL != activity.ServiceURL:
+		return fmt.Errorf("%w: token is for another service", ErrUnauthorized)
+	}
+	return nil
+}
+
+// decodeSegment decodes a segment of a token into v.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed token", ErrUnauthorized)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed token", ErrUnauthorized)
+	}
+	return nil
+}
+
+// key returns the signing key with the ID, fetching the keys if they have expired or the key isn't known.
+func (v *Verifier) key(id string) (signingKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	age := v.now().Sub(v.fetched)
+	key, ok := v.keys[id]
+	if ok && age < keysTTL {
+		return key, nil
+	}
+	if v.keys == nil || age >= keysRefresh {
+		keys, err := v.fetchKeys()
+		if err != nil {
+			return signingKey{}, err
+		}
+		v.keys = keys
+		v.fetched = v.now()
+		key, ok = v.keys[id]
+	}
+	if !ok {
+		return signingKey{}, fmt.Errorf("%w: unknown signing key %q", ErrUnauthorized, id)
+	}
+	return key, nil
+}
+
+// fetchKeys fetches the signing keys listed in the OpenID metadata.
+func (v *Verifier) fetchKeys() (map[string]signingKey, error) {
+	var metadata struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := v.getJSON(v.openIDURL, &metadata); err != nil {
+		return nil, fmt.Errorf("could not get OpenID metadata: %w", err)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty          string   `json:"kty"`
+			Kid          string   `json:"kid"`
+			N            string   `json:"n"`
+			E            string   `json:"e"`
+			Endorsements []string `json:"endorsements"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(metadata.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("could not get signing keys: %w", err)
+	}
+
+	keys := make(map[string]signingKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = signingKey{
+			key:          &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())},
+			endorsements: k.Endorsements,
+		}
+	}
+	return keys, nil
+}
+
+// getJSON decodes the JSON document at the URL into out.
+func (v *Verifier) getJSON(url string, out any) error {
+	resp, err := v.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,139 @@
+package teams_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/teams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAppID      = "00000000-0000-0000-0000-000000000001"
+	testServiceURL = "https://smba.trafficmanager.net/teams/"
+)
+
+// fakeOpenID serves OpenID metadata and the signing key, counting how often the keys are fetched.
+type fakeOpenID struct {
+	key     *rsa.PrivateKey
+	url     string
+	fetches atomic.Int32
+}
+
+func newFakeOpenID(t *testing.T) *fakeOpenID {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f := &fakeOpenID{key: key}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	f.url = server.URL + "/openid"
+	mux.HandleFunc("/openid", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		f.fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]any{{
+			"kty":          "RSA",
+			"kid":          "key-1",
+			"n":            base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			"endorsements": []string{"msteams"},
+		}}})
+	})
+	return f
+}
+
+// sign returns an Authorization header with a token signed by the key, with the claims merged over valid ones.
+func (f *fakeOpenID) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	all := map[string]any{
+		"iss":        teams.Issuer,
+		"aud":        testAppID,
+		"exp":        time.Now().Add(time.Hour).Unix(),
+		"nbf":        time.Now().Add(-time.Minute).Unix(),
+		"serviceurl": testServiceURL,
+	}
+	for k, v := range claims {
+		all[k] = v
+	}
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(map[string]string{"alg": "RS256", "kid": kid}) + "." + segment(all)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return "Bearer " + signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	activity := teams.Activity{Type: teams.ActivityMessage, ServiceURL: testServiceURL, ChannelID: "msteams"}
+
+	t.Run("should accept a token from the Bot Framework", func(t *testing.T) {
+		openID := newFakeOpenID(t)
+		verifier := teams.NewVerifier(testAppID, teams.WithOpenIDURL(openID.url))
+
+		assert.NoError(t, verifier.Verify(openID.sign(t, "key-1", nil), activity))
+		assert.NoError(t, verifier.Verify(openID.sign(t, "key-1", nil), activity))
+		assert.Equal(t, int32(1), openID.fetches.Load(), "the keys should be cached")
+	})
+
+	tests := []struct {
+		name   string
+		header func(*testing.T, *fakeOpenID) string
+	}{
+		{"a missing token", func(*testing.T, *fakeOpenID) string { return "" }},
+		{"a token for another bot", func(t *testing.T, f *fakeOpenID) string {
+			return f.sign(t, "key-1", map[string]any{"aud": "someone-else"})
+		}},
+		{"a token from another issuer", func(t *testing.T, f *fakeOpenID) string {
+			return f.sign(t, "key-1", map[string]any{"iss": "https://example.com"})
+		}},
+		{"an expired token", func(t *testing.T, f *fakeOpenID) string {
+			return f.sign(t, "key-1", map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})
+		}},
+		{"a token for another service", func(t *testing.T, f *fakeOpenID) string {
+			return f.sign(t, "key-1", map[string]any{"serviceurl": "https://attacker.example.com/"})
+		}},
+		{"a token signed with an unknown key", func(t *testing.T, f *fakeOpenID) string {
+			return f.sign(t, "key-2", nil)
+		}},
+		{"a tampered token", func(t *testing.T, f *fakeOpenID) string {
+			parts := strings.Split(f.sign(t, "key-1", nil), ".")
+			claims, _ := json.Marshal(map[string]any{"iss": teams.Issuer, "aud": testAppID, "exp": time.Now().Add(time.Hour).Unix(), "serviceurl": testServiceURL, "admin": true})
+			return parts[0] + "." + base64.RawURLEncoding.EncodeToString(claims) + "." + parts[2]
+		}},
+	}
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			openID := newFakeOpenID(t)
+			verifier := teams.NewVerifier(testAppID, teams.WithOpenIDURL(openID.url))
+			assert.ErrorIs(t, verifier.Verify(tt.header(t, openID), activity), teams.ErrUnauthorized)
+		})
+	}
+
+	t.Run("should reject a key that isn't endorsed for the channel", func(t *testing.T) {
+		openID := newFakeOpenID(t)
+		verifier := teams.NewVerifier(testAppID, teams.WithOpenIDURL(openID.url))
+		activity := activity
+		activity.ChannelID = "webchat"
+		assert.ErrorIs(t, verifier.Verify(openID.sign(t, "key-1", nil), activity), teams.ErrUnauthorized)
+	})
+}
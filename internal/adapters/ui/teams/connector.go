@@ -0,0 +1,150 @@
+package teams
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTokenURL is where multi-tenant bots get the token they send to the Bot Connector.
+	DefaultTokenURL = "https://login.microsoftonline.com/botframework.com/oauth2/v2.0/token"
+	// tokenScope is the scope of the token the Bot Connector accepts.
+	tokenScope = "https://api.botframework.com/.default"
+	// tokenLeeway is how long before a token expires that a new one is fetched.
+	tokenLeeway = time.Minute
+)
+
+// ErrConnector is returned when the Bot Connector, or the service that issues its tokens, rejects a request.
+var ErrConnector = errors.New("bot connector request failed")
+
+// Sender sends activities to conversations in Teams.
+type Sender interface {
+	SendToConversation(conversation ConversationReference, activity Activity) error
+}
+
+// Client sends activities through the Bot Connector, authenticating as the bot.
+type Client struct {
+	appID       string
+	appPassword string
+	tokenURL    string
+	httpClient  *http.Client
+
+	mu sync.Mutex
+	// token is the access token for the Bot Connector, until expires.
+	token   string
+	expires time.Time
+}
+
+// ClientOption configures optional behaviour of a Client.
+type ClientOption func(*Client)
+
+// WithTokenURL gets tokens from the URL instead of DefaultTokenURL, such as the token endpoint of the tenant
+// of a single-tenant bot, or a local stand-in.
+func WithTokenURL(tokenURL string) ClientOption {
+	return func(c *Client) {
+		c.tokenURL = tokenURL
+	}
+}
+
+// WithHTTPClient sends requests with the client instead of http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient creates a client for the bot with the given Microsoft App ID and password.
+func NewClient(appID, appPassword string, opts ...ClientOption) *Client {
+	c := &Client{
+		appID:       appID,
+		appPassword: appPassword,
+		tokenURL:    DefaultTokenURL,
+		httpClient:  http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SendToConversation sends the activity to the conversation, whether or not the bot was sent a message there
+// recently.
+func (c *Client) SendToConversation(conversation ConversationReference, activity Activity) error {
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("could not encode activity: %w", err)
+	}
+	token, err := c.accessToken()
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/v3/conversations/%s/activities", strings.TrimSuffix(conversation.ServiceURL, "/"), url.PathEscape(conversation.ConversationID))
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send activity: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The token may have been revoked, so the next activity gets a new one.
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: sending activity: %s", ErrConnector, resp.Status)
+	}
+	return nil
+}
+
+// accessToken returns a token for the Bot Connector, fetching a new one if the last has expired.
+func (c *Client) accessToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.expires) {
+		return c.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.appID},
+		"client_secret": {c.appPassword},
+		"scope":         {tokenScope},
+	}
+	resp, err := c.httpClient.PostForm(c.tokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("could not get token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: getting token: %s", ErrConnector, resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("could not decode token: %w", err)
+	}
+	c.token = token.AccessToken
+	c.expires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenLeeway)
+	return c.token, nil
+}
+
+// Ensure Client implements Sender.
+var _ Sender = (*Client)(nil)
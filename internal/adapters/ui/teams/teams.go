@@ -0,0 +1,127 @@
+package teams
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/chat"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// Replies that skip a question or wrap up the interview, offered as buttons with each question.
+const (
+	ReplySkip   = "Skip"
+	ReplyWrapUp = "Wrap up"
+)
+
+// UI handles the user interface for an interview in Microsoft Teams. Each question is sent to the conversation
+// as a proactive message, so the interview can carry on however long the participant takes to answer.
+type UI struct {
+	Sender       Sender
+	Conversation ConversationReference
+	// UserID is the Teams ID of the participant. In conversations with more than one person, only their
+	// messages are taken as answers.
+	UserID string
+
+	*chat.Inbox
+}
+
+// New creates a UI for an interview with the user, in the conversation.
+func New(sender Sender, conversation ConversationReference, userID string) *UI {
+	return &UI{
+		Sender:       sender,
+		Conversation: conversation,
+		UserID:       userID,
+		Inbox:        chat.NewInbox(),
+	}
+}
+
+// send sends a message to the interview's conversation.
+func (u *UI) send(activity Activity) error {
+	activity.Type = ActivityMessage
+	activity.TextFormat = "markdown"
+	return u.Sender.SendToConversation(u.Conversation, activity)
+}
+
+// Say sends a message to the participant that doesn't need an answer.
+func (u *UI) Say(text string) error {
+	if err := u.send(Activity{Text: text}); err != nil {
+		return fmt.Errorf("failed to send message to teams: %w", err)
+	}
+	return nil
+}
+
+// Ask sends a question to the participant in Teams and waits for their answer.
+func (u *UI) Ask(question string) (string, error) {
+	return u.AskQuestion(interview.Question{Text: question})
+}
+
+// AskQuestion sends a question to the participant in Teams, with buttons for its choices and to skip it or wrap
+// up the interview, and waits for them to answer.
+func (u *UI) AskQuestion(question interview.Question) (string, error) {
+	select {
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	default:
+	}
+
+	slog.Debug("Asking question on teams", "conversation_id", u.Conversation.ConversationID, "user_id", u.UserID, "question", question.Text)
+	if err := u.send(questionActivity(u.UserID, question)); err != nil {
+		slog.Error("Failed to send message to teams", "error", err, "conversation_id", u.Conversation.ConversationID, "user_id", u.UserID)
+		return "", fmt.Errorf("failed to send message to teams: %w", err)
+	}
+
+	select {
+	case answer := <-u.Answers():
+		slog.Debug("Received answer from user", "conversation_id", u.Conversation.ConversationID, "user_id", u.UserID, "answer", answer)
+		switch {
+		case strings.EqualFold(answer, ReplySkip):
+			return "", interview.ErrSkipped
+		case strings.EqualFold(answer, ReplyWrapUp):
+			return "", interview.ErrStopped
+		}
+		return answer, nil
+	case <-u.Cancelled():
+		return "", u.endCancelled()
+	}
+}
+
+// questionActivity builds the message for a question: the question itself and its progress through the
+// interview, with a button for each of its choices and buttons to skip it or wrap up.
+func questionActivity(userID string, question interview.Question) Activity {
+	text := question.Text
+	if question.Number > 0 {
+		progress := fmt.Sprintf("Question %d", question.Number)
+		if question.Total > 0 {
+			progress += fmt.Sprintf(" of %d", question.Total)
+		}
+		text += "\n\n_" + progress + "_"
+	}
+
+	actions := make([]CardAction, 0, len(question.Choices)+2)
+	for _, choice := range append(append([]string(nil), question.Choices...), ReplySkip, ReplyWrapUp) {
+		actions = append(actions, CardAction{Type: "imBack", Title: choice, Value: choice})
+	}
+	return Activity{Text: text, SuggestedActions: &SuggestedActions{To: []string{userID}, Actions: actions}}
+}
+
+// endCancelled tells the participant the interview was cancelled.
+func (u *UI) endCancelled() error {
+	return u.EndCancelled(u.Say, "conversation_id", u.Conversation.ConversationID, "user_id", u.UserID)
+}
+
+// DisplaySummary sends the interview summary to the participant in Teams.
+func (u *UI) DisplaySummary(summary string) {
+	if summary == "" {
+		return
+	}
+	text := fmt.Sprintf("**Interview Summary**\n\n%s", summary)
+	if err := u.Say(text); err != nil {
+		slog.Error("Error displaying summary", "error", err, "conversation_id", u.Conversation.ConversationID, "user_id", u.UserID)
+	}
+}
+
+// Ensure UI implements the domain interface.
+var _ interview.InterviewUI = (*UI)(nil)
+var _ interview.QuestionUI = (*UI)(nil)
@@ -0,0 +1,150 @@
+package teams_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/teams"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConnector is a Bot Connector that records the activities sent to each conversation, and issues the
+// tokens it accepts.
+type fakeConnector struct {
+	url string
+
+	mu       sync.Mutex
+	tokens   int
+	received map[string][]teams.Activity
+}
+
+func newFakeConnector(t *testing.T) *fakeConnector {
+	t.Helper()
+	f := &fakeConnector{received: make(map[string][]teams.Activity)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.Form.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.mu.Lock()
+		f.tokens++
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "connector-token", "expires_in": 3600})
+	})
+	mux.HandleFunc("POST /v3/conversations/{id}/activities", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer connector-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var activity teams.Activity
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&activity))
+		f.mu.Lock()
+		f.received[r.PathValue("id")] = append(f.received[r.PathValue("id")], activity)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": "1"})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	f.url = server.URL
+	return f
+}
+
+func (f *fakeConnector) activities(conversationID string) []teams.Activity {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]teams.Activity(nil), f.received[conversationID]...)
+}
+
+func TestClient_SendToConversation(t *testing.T) {
+	connector := newFakeConnector(t)
+	conversation := teams.ConversationReference{ServiceURL: connector.url + "/", ConversationID: "a:1"}
+
+	t.Run("should send activities with a cached token", func(t *testing.T) {
+		client := teams.NewClient(testAppID, "secret", teams.WithTokenURL(connector.url+"/token"))
+		require.NoError(t, client.SendToConversation(conversation, teams.Activity{Type: teams.ActivityMessage, Text: "one"}))
+		require.NoError(t, client.SendToConversation(conversation, teams.Activity{Type: teams.ActivityMessage, Text: "two"}))
+
+		received := connector.activities("a:1")
+		require.Len(t, received, 2)
+		assert.Equal(t, "two", received[1].Text)
+		connector.mu.Lock()
+		defer connector.mu.Unlock()
+		assert.Equal(t, 1, connector.tokens)
+	})
+
+	t.Run("should return an error if the token is refused", func(t *testing.T) {
+		client := teams.NewClient(testAppID, "wrong", teams.WithTokenURL(connector.url+"/token"))
+		err := client.SendToConversation(conversation, teams.Activity{Type: teams.ActivityMessage, Text: "three"})
+		assert.ErrorIs(t, err, teams.ErrConnector)
+	})
+}
+
+func TestUI_AskQuestion(t *testing.T) {
+	connector := newFakeConnector(t)
+	client := teams.NewClient(testAppID, "secret", teams.WithTokenURL(connector.url+"/token"))
+	conversation := teams.ConversationReference{ServiceURL: connector.url, ConversationID: "a:1"}
+
+	// ask asks the question in the background, answering it with the reply once it has been sent.
+	ask := func(t *testing.T, ui *teams.UI, question interview.Question, reply string) (string, error) {
+		t.Helper()
+		sent := len(connector.activities("a:1"))
+		go func() {
+			assert.Eventually(t, func() bool { return len(connector.activities("a:1")) > sent }, time.Second, time.Millisecond)
+			ui.Answer(reply)
+		}()
+		return ui.AskQuestion(question)
+	}
+
+	t.Run("should send the question with its choices and return the answer", func(t *testing.T) {
+		ui := teams.New(client, conversation, "29:ada")
+		answer, err := ask(t, ui, interview.Question{Text: "Which plan are you on?", Choices: []string{"Free", "Pro"}, Number: 1, Total: 3}, " Pro ")
+		require.NoError(t, err)
+		assert.Equal(t, "Pro", answer)
+
+		received := connector.activities("a:1")
+		question := received[len(received)-1]
+		assert.Equal(t, "Which plan are you on?\n\n_Question 1 of 3_", question.Text)
+		require.NotNil(t, question.SuggestedActions)
+		assert.Equal(t, []string{"29:ada"}, question.SuggestedActions.To)
+		var titles []string
+		for _, action := range question.SuggestedActions.Actions {
+			assert.Equal(t, "imBack", action.Type)
+			titles = append(titles, action.Title)
+		}
+		assert.Equal(t, []string{"Free", "Pro", teams.ReplySkip, teams.ReplyWrapUp}, titles)
+	})
+
+	t.Run("should skip the question", func(t *testing.T) {
+		ui := teams.New(client, conversation, "29:ada")
+		_, err := ask(t, ui, interview.Question{Text: "Why?"}, "skip")
+		assert.ErrorIs(t, err, interview.ErrSkipped)
+	})
+
+	t.Run("should wrap up the interview", func(t *testing.T) {
+		ui := teams.New(client, conversation, "29:ada")
+		_, err := ask(t, ui, interview.Question{Text: "Why?"}, teams.ReplyWrapUp)
+		assert.ErrorIs(t, err, interview.ErrStopped)
+	})
+
+	t.Run("should end a cancelled interview", func(t *testing.T) {
+		ui := teams.New(client, conversation, "29:ada")
+		ui.Cancel()
+		_, err := ui.AskQuestion(interview.Question{Text: "Why?"})
+		assert.ErrorIs(t, err, interview.ErrCancelled)
+		assert.False(t, ui.Answer("too late"))
+	})
+}
+
+func TestActivity_PlainText(t *testing.T) {
+	activity := teams.Activity{Text: "<at>vox</at> interview start --topic discovery "}
+	assert.Equal(t, "interview start --topic discovery", activity.PlainText())
+}
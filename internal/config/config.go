@@ -1,5 +1,25 @@
 package config
 
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain/redaction"
+)
+
+// ProviderNames lists the question providers that can be used by a topic.
+var ProviderNames = []string{"static", "gemini", "openai"}
+
+// ErrInvalidConfig is returned when the configuration fails validation.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
 // Config defines the structure of the application's configuration file.
 type Config struct {
 	// DNSServer specifies a custom DNS server to use for all outbound connections.
@@ -13,15 +33,164 @@ type Config struct {
 		}
 	}
 	Interviews []Topic
-	Providers  struct {
-		Gemini struct {
-			APIKey      string `mapstructure:"api_key"`
-			Model       string
-			Interviewer struct {
-				Prompt string
-			}
+	// Redaction removes sensitive information from answers before they are sent to a provider or stored.
+	Redaction struct {
+		Enabled bool
+		// Detectors lists the built-in detectors to use (email, phone, card, secret). All are used if empty.
+		Detectors []string
+		// Dictionaries maps a name to a list of terms to redact, such as customer names. Names are used in the
+		// tokens that replace the terms, so may only contain letters, digits, "_" and "-".
+		Dictionaries map[string][]string
+		// Key is a base64-encoded 32 byte key. If set, the original values are kept encrypted so that an
+		// export with the same key can restore them.
+		Key string
+		// Attachments keeps the files sent with answers, and sends them to the provider, even though they can't
+		// be redacted. Otherwise, only their names, types and sizes are kept.
+		Attachments bool
+	}
+	// Invites configures the signed links used to invite participants from outside the organisation.
+	Invites struct {
+		// Key is a base64-encoded key of at least 32 bytes, used to sign invite links.
+		Key string
+		// BaseURL is the public address of `vox serve`, used to build invite links.
+		BaseURL string `mapstructure:"base_url"`
+	}
+	// Slack configures how vox behaves in Slack.
+	Slack struct {
+		// PMGroups lists the IDs of the Slack user groups whose members are product managers. They are shown
+		// recent interviews for each topic on the App Home tab.
+		PMGroups []string `mapstructure:"pm_groups"`
+		// Triage maps the name of a reaction, such as "white_check_mark", to the label it gives an interview
+		// when added to the interview's summary card. If empty, ✅ marks an interview as reviewed.
+		Triage map[string]string
+		// SummaryTeam is the ID of the workspace the topics' summary channels are in, when vox is installed
+		// with OAuth. If empty, summary cards are posted with the bot token.
+		SummaryTeam string `mapstructure:"summary_team"`
+		// OAuth lets workspaces install vox with "Add to Slack", each with its own bot token, instead of
+		// using a single bot token.
+		OAuth struct {
+			ClientID     string `mapstructure:"client_id"`
+			ClientSecret string `mapstructure:"client_secret"`
+			// RedirectURL is the address of /slack/oauth/callback. It can be left out if the Slack app has
+			// only one redirect URL.
+			RedirectURL string `mapstructure:"redirect_url"`
+			// TokenKey is a base64-encoded 32 byte key, used to encrypt the bot tokens stored in the
+			// repository.
+			TokenKey string `mapstructure:"token_key"`
 		}
 	}
+	// Teams configures interviews in Microsoft Teams, through an Azure Bot.
+	Teams struct {
+		// AppID and AppPassword are the bot's Microsoft App ID and client secret.
+		AppID       string `mapstructure:"app_id"`
+		AppPassword string `mapstructure:"app_password"`
+		// TokenURL is where the bot gets tokens for the Bot Connector. It only needs to be set for a
+		// single-tenant bot, to the token endpoint of its tenant.
+		TokenURL string `mapstructure:"token_url"`
+		// OpenIDURL is where the keys that sign the Bot Framework's requests are published. It only needs to
+		// be set to test against a stand-in for the Bot Framework.
+		OpenIDURL string `mapstructure:"openid_url"`
+	}
+	// Discord configures interviews in Discord, started with the /vox slash command.
+	Discord struct {
+		ApplicationID string `mapstructure:"application_id"`
+		// PublicKey is the application's hex-encoded public key, used to check that requests come from Discord.
+		PublicKey string `mapstructure:"public_key"`
+		BotToken  string `mapstructure:"bot_token"`
+		// APIURL is the address of the Discord REST API. It only needs to be set to test against a stand-in.
+		APIURL string `mapstructure:"api_url"`
+	}
+	// Email configures interviews held over email, with each question sent as an email and answered by replying
+	// to it.
+	Email struct {
+		// From is the address the emails are sent from, such as "Vox <research@example.com>". Replies are
+		// matched to their interview by Message-IDs in its domain.
+		From string
+		SMTP struct {
+			// Address is the host and port of the SMTP server. Port 465 uses TLS from the start; other ports
+			// use STARTTLS if the server offers it.
+			Address  string
+			Username string
+			Password string
+		}
+		// IMAP is polled for replies. It can be left out if replies are delivered to /email/inbound instead.
+		IMAP struct {
+			// Address is the host and port of the IMAP server, which is connected to over TLS.
+			Address  string
+			Username string
+			Password string
+			// Mailbox is where replies are delivered. It defaults to INBOX, and should only be read by vox.
+			Mailbox string
+			// PollInterval is how often the mailbox is checked for replies. It defaults to one minute.
+			PollInterval time.Duration `mapstructure:"poll_interval"`
+			// Plaintext connects without TLS. It should only be used with a local stand-in for the server.
+			Plaintext bool
+		}
+		// InboundToken is the bearer token a mail provider must send with the replies it delivers to
+		// /email/inbound, as raw MIME messages. The endpoint is disabled if it is empty.
+		InboundToken string `mapstructure:"inbound_token"`
+	}
+	// SMS configures interviews by text message and WhatsApp, sent through Twilio or an API compatible with it.
+	SMS struct {
+		AccountSID string `mapstructure:"account_sid"`
+		// AuthToken authenticates requests to the API, and signs the requests it sends to /sms/inbound.
+		AuthToken string `mapstructure:"auth_token"`
+		// From is the phone number text messages are sent from, in E.164 format such as "+15550100".
+		From string
+		// WhatsAppFrom is the number WhatsApp messages are sent from. WhatsApp interviews can't be started
+		// without it.
+		WhatsAppFrom string `mapstructure:"whatsapp_from"`
+		// WebhookURL is the public address of /sms/inbound, exactly as configured for incoming messages, since
+		// it is part of their signature.
+		WebhookURL string `mapstructure:"webhook_url"`
+		// APIURL is the address of the Twilio REST API. It only needs to be set to use a compatible API, or to
+		// test against a stand-in.
+		APIURL string `mapstructure:"api_url"`
+	}
+	// API configures access to the HTTP API served by `vox serve`.
+	API struct {
+		Tokens []APIToken
+	}
+	Providers Providers
+}
+
+// APIScopes lists the scopes that can be granted to an API token.
+var APIScopes = []string{"sessions:write", "interviews:read"}
+
+// APIToken grants a client access to the parts of the HTTP API named by its scopes.
+type APIToken struct {
+	// Name identifies the client in logs and on the interviews it starts.
+	Name   string
+	Token  string
+	Scopes []string
+}
+
+// Providers defines the configuration for each question provider.
+type Providers struct {
+	Gemini Gemini
+	OpenAI OpenAI
+}
+
+// Gemini defines the configuration for the Gemini provider.
+type Gemini struct {
+	APIKey      string `mapstructure:"api_key"`
+	Model       string
+	Interviewer Interviewer
+	// Generation tunes how the model generates questions. Topics may override individual settings.
+	Generation Generation
+}
+
+// OpenAI defines the configuration for the OpenAI-compatible provider, which can also be used with locally
+// hosted models.
+type OpenAI struct {
+	BaseURL string `mapstructure:"base_url"`
+	APIKey  string `mapstructure:"api_key"`
+	Model   string
+}
+
+// Interviewer defines the interviewer's instructions, shared by every topic.
+type Interviewer struct {
+	Prompt string
 }
 
 // Topic defines the structure of an interview topic.
@@ -31,4 +200,120 @@ type Topic struct {
 	Provider  string
 	Prompt    string
 	Questions []string
+	// Browser allows anyone with the link to take the interview in a web browser, at /interview/<id>.
+	Browser bool
+	// SummaryChannels lists the Slack channels where a card with the summary of each interview is posted
+	// when it finishes.
+	SummaryChannels []string `mapstructure:"summary_channels"`
+	// Fallback lists the providers to fail over to, in order, if the provider stops responding.
+	Fallback []string
+	// Generation overrides the provider's generation settings for this topic.
+	Generation Generation
+	// History limits the conversation history sent to the provider with each turn.
+	History struct {
+		// Strategy is one of "full" (the default), "sliding-window" or "summarizing-window".
+		Strategy string
+		// Turns is the number of recent question and answer exchanges to keep.
+		Turns int
+	}
+	// Lint configures the interviewer quality checks for this topic.
+	Lint struct {
+		// Guard asks the provider to rephrase questions that fail the checks before the participant sees them.
+		Guard bool
+		// Jargon lists additional terms to flag as jargon.
+		Jargon []string
+	}
+	// Idle nudges, then ends, interviews in Slack that the participant stops answering.
+	Idle struct {
+		// RemindAfter sends the participant a reminder once a question has gone unanswered this long.
+		RemindAfter time.Duration `mapstructure:"remind_after"`
+		// AbandonAfter ends the interview once a question has gone unanswered this long, saving the answers
+		// given so far.
+		AbandonAfter time.Duration `mapstructure:"abandon_after"`
+	}
+}
+
+// Validate checks the configuration for settings that would fail at runtime.
+func (c *Config) Validate() error {
+	var errs []error
+	for name := range c.Redaction.Dictionaries {
+		if err := redaction.ValidateDictionaryName(name); err != nil {
+			errs = append(errs, fmt.Errorf("redaction.dictionaries: %w", err))
+		}
+	}
+	if err := c.Providers.Gemini.Generation.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("providers.gemini.generation: %w", err))
+	}
+	for _, t := range c.Interviews {
+		if err := c.Providers.Gemini.Generation.Merge(t.Generation).Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("interviews.%s.generation: %w", t.ID, err))
+		}
+		for _, name := range t.Fallback {
+			if !slices.Contains(ProviderNames, strings.ToLower(name)) {
+				errs = append(errs, fmt.Errorf("interviews.%s.fallback: unknown provider %q", t.ID, name))
+			}
+		}
+		if t.Idle.RemindAfter < 0 || t.Idle.AbandonAfter < 0 {
+			errs = append(errs, fmt.Errorf("interviews.%s.idle: durations can't be negative", t.ID))
+		}
+		if t.Idle.RemindAfter > 0 && t.Idle.AbandonAfter > 0 && t.Idle.RemindAfter >= t.Idle.AbandonAfter {
+			errs = append(errs, fmt.Errorf("interviews.%s.idle: remind_after must be shorter than abandon_after", t.ID))
+		}
+	}
+	for i, t := range c.API.Tokens {
+		if t.Token == "" {
+			errs = append(errs, fmt.Errorf("api.tokens[%d]: token is required", i))
+		}
+		for _, scope := range t.Scopes {
+			if !slices.Contains(APIScopes, scope) {
+				errs = append(errs, fmt.Errorf("api.tokens[%d]: unknown scope %q", i, scope))
+			}
+		}
+	}
+	if oauth := c.Slack.OAuth; oauth.ClientID != "" && (oauth.ClientSecret == "" || oauth.TokenKey == "") {
+		errs = append(errs, errors.New("slack.oauth: client_secret and token_key are required with client_id"))
+	}
+	if c.Teams.AppID != "" && c.Teams.AppPassword == "" {
+		errs = append(errs, errors.New("teams: app_password is required with app_id"))
+	}
+	if discord := c.Discord; discord.ApplicationID != "" {
+		if key, err := hex.DecodeString(discord.PublicKey); err != nil || len(key) != ed25519.PublicKeySize {
+			errs = append(errs, errors.New("discord: public_key must be the application's hex-encoded public key"))
+		}
+		if discord.BotToken == "" {
+			errs = append(errs, errors.New("discord: bot_token is required with application_id"))
+		}
+	}
+	if email := c.Email; email.From != "" {
+		if address, err := mail.ParseAddress(email.From); err != nil || !strings.Contains(address.Address, "@") {
+			errs = append(errs, errors.New("email: from must be an email address"))
+		}
+		if email.SMTP.Address == "" {
+			errs = append(errs, errors.New("email: smtp.address is required with from"))
+		}
+		if email.IMAP.Address == "" && email.InboundToken == "" {
+			errs = append(errs, errors.New("email: imap.address or inbound_token is required to receive replies"))
+		}
+		if email.IMAP.Address != "" && email.IMAP.Username == "" {
+			errs = append(errs, errors.New("email: imap.username is required with imap.address"))
+		}
+		if email.IMAP.PollInterval < 0 {
+			errs = append(errs, errors.New("email: imap.poll_interval can't be negative"))
+		}
+	}
+	if sms := c.SMS; sms.AccountSID != "" {
+		if sms.AuthToken == "" {
+			errs = append(errs, errors.New("sms: auth_token is required with account_sid"))
+		}
+		if sms.From == "" && sms.WhatsAppFrom == "" {
+			errs = append(errs, errors.New("sms: from or whatsapp_from is required with account_sid"))
+		}
+		if u, err := url.Parse(sms.WebhookURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, errors.New("sms: webhook_url must be the public address of /sms/inbound"))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	return nil
 }
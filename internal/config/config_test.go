@@ -0,0 +1,165 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain/redaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestGeneration_Merge(t *testing.T) {
+	base := Generation{
+		Temperature:   ptr[float32](0.7),
+		StopSequences: []string{"END"},
+		Safety:        map[string]string{"harassment": "block_none"},
+	}
+	merged := base.Merge(Generation{
+		Temperature: ptr[float32](0.2),
+		Safety:      map[string]string{"hate_speech": "block_only_high"},
+	})
+
+	assert.Equal(t, float32(0.2), *merged.Temperature)
+	assert.Equal(t, []string{"END"}, merged.StopSequences)
+	assert.Equal(t, map[string]string{"harassment": "block_none", "hate_speech": "block_only_high"}, merged.Safety)
+	// The base settings must not be modified.
+	assert.Equal(t, float32(0.7), *base.Temperature)
+	assert.Len(t, base.Safety, 1)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("should accept valid settings", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Providers.Gemini.Generation = Generation{
+			Temperature:     ptr[float32](1),
+			TopP:            ptr[float32](0.9),
+			MaxOutputTokens: ptr[int32](512),
+			Safety:          map[string]string{"dangerous_content": "BLOCK_LOW_AND_ABOVE"},
+		}
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("should reject out of range provider settings", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Providers.Gemini.Generation = Generation{
+			Temperature:     ptr[float32](3),
+			MaxOutputTokens: ptr[int32](0),
+		}
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "temperature")
+		assert.ErrorContains(t, err, "max_output_tokens")
+	})
+
+	t.Run("should reject invalid topic overrides", func(t *testing.T) {
+		topic := Topic{ID: "discovery"}
+		topic.Generation.TopP = ptr[float32](1.5)
+		topic.Generation.Safety = map[string]string{"gossip": "block_none"}
+		cfg := &Config{Interviews: []Topic{topic}}
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "interviews.discovery.generation")
+		assert.ErrorContains(t, err, "top_p")
+		assert.ErrorContains(t, err, "gossip")
+	})
+
+	t.Run("should reject unknown fallback providers", func(t *testing.T) {
+		cfg := &Config{Interviews: []Topic{{ID: "discovery", Provider: "gemini", Fallback: []string{"openai", "claude"}}}}
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, `interviews.discovery.fallback: unknown provider "claude"`)
+	})
+
+	t.Run("should reject idle policies that abandon before reminding", func(t *testing.T) {
+		topic := Topic{ID: "discovery", Provider: "static"}
+		topic.Idle.RemindAfter = 48 * time.Hour
+		topic.Idle.AbandonAfter = 24 * time.Hour
+		cfg := &Config{Interviews: []Topic{topic}}
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "interviews.discovery.idle: remind_after must be shorter than abandon_after")
+	})
+
+	t.Run("should reject incomplete Slack OAuth settings", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Slack.OAuth.ClientID = "123.456"
+		cfg.Slack.OAuth.ClientSecret = "secret"
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "slack.oauth: client_secret and token_key are required with client_id")
+	})
+
+	t.Run("should reject incomplete Teams settings", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Teams.AppID = "00000000-0000-0000-0000-000000000001"
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "teams: app_password is required with app_id")
+	})
+
+	t.Run("should reject incomplete Discord settings", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Discord.ApplicationID = "1234567890"
+		cfg.Discord.PublicKey = "not-hex"
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "discord: public_key must be the application's hex-encoded public key")
+		assert.ErrorContains(t, err, "discord: bot_token is required with application_id")
+	})
+
+	t.Run("should reject incomplete email settings", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Email.From = "Vox <research>"
+		cfg.Email.IMAP.Address = "imap.example.com:993"
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "email: from must be an email address")
+		assert.ErrorContains(t, err, "email: smtp.address is required with from")
+		assert.ErrorContains(t, err, "email: imap.username is required with imap.address")
+	})
+
+	t.Run("should reject dictionary names that can't be used in tokens", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Redaction.Dictionaries = map[string][]string{"customers": {"Acme"}, "internal hosts": {"db1"}}
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorIs(t, err, redaction.ErrInvalidDictionary)
+		assert.ErrorContains(t, err, `"internal hosts"`)
+		assert.NotContains(t, err.Error(), `"customers"`)
+	})
+
+	t.Run("should reject incomplete sms settings", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.SMS.AccountSID = "AC123"
+		cfg.SMS.WebhookURL = "/sms/inbound"
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "sms: auth_token is required with account_sid")
+		assert.ErrorContains(t, err, "sms: from or whatsapp_from is required with account_sid")
+		assert.ErrorContains(t, err, "sms: webhook_url must be the public address of /sms/inbound")
+	})
+
+	t.Run("should reject incomplete API tokens", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.API.Tokens = []APIToken{{Name: "widget", Scopes: []string{"sessions:write", "admin"}}}
+
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "api.tokens[0]: token is required")
+		assert.ErrorContains(t, err, `api.tokens[0]: unknown scope "admin"`)
+	})
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Safety categories that may be configured in Generation.Safety.
+var SafetyCategories = []string{"harassment", "hate_speech", "sexually_explicit", "dangerous_content"}
+
+// Safety thresholds that may be configured in Generation.Safety.
+var SafetyThresholds = []string{"block_none", "block_only_high", "block_medium_and_above", "block_low_and_above"}
+
+// Generation configures how a model generates responses. Unset fields use the model's defaults.
+type Generation struct {
+	Temperature     *float32
+	TopP            *float32 `mapstructure:"top_p"`
+	MaxOutputTokens *int32   `mapstructure:"max_output_tokens"`
+	StopSequences   []string `mapstructure:"stop_sequences"`
+	// Safety maps a harm category to the threshold at which responses are blocked.
+	Safety map[string]string
+}
+
+// Merge returns the settings with any fields that are set in the override replaced.
+func (g Generation) Merge(override Generation) Generation {
+	merged := g
+	if override.Temperature != nil {
+		merged.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		merged.TopP = override.TopP
+	}
+	if override.MaxOutputTokens != nil {
+		merged.MaxOutputTokens = override.MaxOutputTokens
+	}
+	if override.StopSequences != nil {
+		merged.StopSequences = override.StopSequences
+	}
+	if len(override.Safety) > 0 {
+		merged.Safety = make(map[string]string, len(g.Safety)+len(override.Safety))
+		for k, v := range g.Safety {
+			merged.Safety[k] = v
+		}
+		for k, v := range override.Safety {
+			merged.Safety[k] = v
+		}
+	}
+	return merged
+}
+
+// Validate checks that each setting is within the range the model accepts.
+func (g Generation) Validate() error {
+	var errs []error
+	if g.Temperature != nil && (*g.Temperature < 0 || *g.Temperature > 2) {
+		errs = append(errs, fmt.Errorf("temperature must be between 0 and 2, got %v", *g.Temperature))
+	}
+	if g.TopP != nil && (*g.TopP < 0 || *g.TopP > 1) {
+		errs = append(errs, fmt.Errorf("top_p must be between 0 and 1, got %v", *g.TopP))
+	}
+	if g.MaxOutputTokens != nil && *g.MaxOutputTokens <= 0 {
+		errs = append(errs, fmt.Errorf("max_output_tokens must be positive, got %d", *g.MaxOutputTokens))
+	}
+	if len(g.StopSequences) > 5 {
+		errs = append(errs, fmt.Errorf("at most 5 stop_sequences are allowed, got %d", len(g.StopSequences)))
+	}
+
+	categories := make([]string, 0, len(g.Safety))
+	for c := range g.Safety {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+	for _, c := range categories {
+		if !slices.Contains(SafetyCategories, c) {
+			errs = append(errs, fmt.Errorf("unknown safety category %q, expected one of %s", c, strings.Join(SafetyCategories, ", ")))
+		}
+		if t := g.Safety[c]; !slices.Contains(SafetyThresholds, strings.ToLower(t)) {
+			errs = append(errs, fmt.Errorf("unknown safety threshold %q for %s, expected one of %s", t, c, strings.Join(SafetyThresholds, ", ")))
+		}
+	}
+
+	return errors.Join(errs...)
+}
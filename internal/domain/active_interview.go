@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// ViaEmail marks an ActiveInterview held over email.
+const ViaEmail = "email"
+
+// ActiveInterview records an interview that is still in progress, so it can be picked up where it left off
+// if the server restarts.
+type ActiveInterview struct {
+	ID      string `json:"id"`
+	TopicID string `json:"topic_id"`
+	UserID  string `json:"user_id"`
+	// Via is how the interview is held: ViaEmail, or empty for Slack.
+	Via string `json:"via,omitempty"`
+	// TeamID is the Slack workspace the interview is held in. It is empty unless vox was installed with
+	// OAuth.
+	TeamID string `json:"team_id,omitempty"`
+	// ChannelID and ThreadTS identify the conversation the interview is held in. ThreadTS is empty for a
+	// direct message. For an interview over email, ChannelID is the participant's address.
+	ChannelID string `json:"channel_id"`
+	ThreadTS  string `json:"thread_ts,omitempty"`
+	// Asked counts the questions sent over email, and Pending is the last of them if it is still waiting for
+	// an answer, so it isn't sent again when the interview is picked up.
+	Asked   int    `json:"asked,omitempty"`
+	Pending string `json:"pending,omitempty"`
+	// Observers are the channels that are sent the summary when the interview finishes.
+	Observers []string `json:"observers,omitempty"`
+	// Replaces is the abandoned interview this one picks up. It is deleted once this one has been saved.
+	Replaces  string    `json:"replaces,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Transcript holds the answers given so far. The provider's state is rebuilt from it when the interview
+	// is resumed.
+	Transcript Transcript `json:"transcript"`
+}
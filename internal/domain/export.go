@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownFormat is returned by ExportInterview for a format it can't export in.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// ExportInterview formats an interview, its transcript and its summary as "json" or "text", in any case.
+func ExportInterview(interview *Interview, transcript *Transcript, summary *Summary, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		b, err := json.MarshalIndent(struct {
+			*Interview
+			*Transcript
+			*Summary
+		}{interview, transcript, summary}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("could not marshal interview: %w", err)
+		}
+		return string(b) + "\n", nil
+	case "text":
+		var b strings.Builder
+		fmt.Fprintf(&b, "Interview ID: %s\n", interview.ID)
+		fmt.Fprintf(&b, "User: %s\n", interview.UserID)
+		fmt.Fprintf(&b, "Project: %s\n", interview.ProjectID)
+		fmt.Fprintf(&b, "Created At: %s\n\n", interview.CreatedAt.String())
+		fmt.Fprintln(&b, "--- Transcript ---")
+		for i, entry := range transcript.Entries {
+			fmt.Fprintf(&b, "Q: %s\nA: %s\n", entry.Question, entry.Answer)
+			for _, attachment := range transcript.Attachments {
+				if attachment.Entry == i {
+					fmt.Fprintf(&b, "Attached: %s (%s, %d bytes)\n", attachment.Name, attachment.MimeType, attachment.Size)
+				}
+			}
+			fmt.Fprintln(&b)
+		}
+		fmt.Fprintln(&b, "--- Summary ---")
+		fmt.Fprintln(&b, summary.Text)
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportInterview(t *testing.T) {
+	interview := &Interview{ID: "1", UserID: "U123", ProjectID: "feedback", CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	transcript := &Transcript{
+		Entries: []struct {
+			Question string `json:"question"`
+			Answer   string `json:"answer"`
+		}{{Question: "What do you like?", Answer: "The speed."}},
+		Attachments: []Attachment{{Entry: 0, Name: "speed.png", MimeType: "image/png", Size: 42}},
+	}
+	summary := &Summary{Text: "Likes the speed."}
+
+	t.Run("should export as text in any case", func(t *testing.T) {
+		text, err := ExportInterview(interview, transcript, summary, "TEXT")
+		require.NoError(t, err)
+		assert.Contains(t, text, "Interview ID: 1\n")
+		assert.Contains(t, text, "Q: What do you like?\nA: The speed.\nAttached: speed.png (image/png, 42 bytes)\n")
+		assert.Contains(t, text, "--- Summary ---\nLikes the speed.\n")
+	})
+
+	t.Run("should export as JSON", func(t *testing.T) {
+		text, err := ExportInterview(interview, transcript, summary, "json")
+		require.NoError(t, err)
+		assert.Contains(t, text, `"user_id": "U123"`)
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		_, err := ExportInterview(interview, transcript, summary, "xml")
+		assert.ErrorIs(t, err, ErrUnknownFormat)
+	})
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Installation records a Slack workspace that installed vox with OAuth.
+type Installation struct {
+	TeamID    string `json:"team_id"`
+	TeamName  string `json:"team_name"`
+	BotUserID string `json:"bot_user_id"`
+	// BotToken is the workspace's bot token, encrypted with the token key.
+	BotToken    string    `json:"bot_token"`
+	Scope       string    `json:"scope"`
+	InstalledBy string    `json:"installed_by"`
+	InstalledAt time.Time `json:"installed_at"`
+}
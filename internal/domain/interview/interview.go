@@ -1,7 +1,10 @@
 package interview
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/andrewhowdencom/vox/internal/domain"
@@ -16,6 +19,48 @@ type QuestionProvider interface {
 	NextQuestion(previousAnswer string) (question string, hasMore bool)
 }
 
+// FallibleQuestionProvider is implemented by providers that can tell a failure apart from the end of the
+// interview, so that another provider can take over.
+type FallibleQuestionProvider interface {
+	QuestionProvider
+	// TryNextQuestion behaves like NextQuestion, but returns an error if the question could not be generated.
+	TryNextQuestion(previousAnswer string) (question string, hasMore bool, err error)
+}
+
+// Resumer is implemented by providers that can pick up an interview part way through.
+type Resumer interface {
+	// Resume restores the provider's state as if it had asked every question in the transcript. The answer to
+	// the last question is passed to the next call to NextQuestion, as usual.
+	Resume(transcript *domain.Transcript) error
+}
+
+// FailoverReporter is implemented by providers that can switch to another provider part way through an
+// interview, so the switches can be recorded on the interview.
+type FailoverReporter interface {
+	Failovers() []domain.Failover
+}
+
+// Describer is implemented by providers that can report the settings they used, so they can be recorded
+// on the interview.
+type Describer interface {
+	Describe() *domain.ProviderInfo
+}
+
+// ErrStopped is returned by InterviewUI.Ask when the participant ends the interview early. The interview is
+// summarised and saved with the answers given so far.
+var ErrStopped = errors.New("the participant ended the interview")
+
+// ErrAbandoned is returned by InterviewUI.Ask when the participant stops answering. Like ErrStopped, the
+// interview is summarised and saved with the answers given so far, and is marked as abandoned.
+var ErrAbandoned = errors.New("the participant stopped answering")
+
+// ErrCancelled is returned by InterviewUI.Ask when the interview is cancelled. Nothing is saved.
+var ErrCancelled = errors.New("the interview was cancelled")
+
+// ErrNotResumable is returned by Run when an interview is resumed with a provider that can't pick up part way
+// through.
+var ErrNotResumable = errors.New("the provider can't resume an interview")
+
 // InterviewUI is an interface for the user interface of the interview.
 type InterviewUI interface {
 	// Ask asks a question to the user and returns the answer.
@@ -24,6 +69,47 @@ type InterviewUI interface {
 	DisplaySummary(summary string)
 }
 
+// AskedQuestionUI is implemented by UIs that may ask a question other than the one they were given, such as one
+// sent before the interview was interrupted that is still waiting for an answer when it is picked up.
+type AskedQuestionUI interface {
+	// AskedQuestion returns the question last asked, as the participant saw it.
+	AskedQuestion() string
+}
+
+// AttachmentUI is implemented by UIs that let participants send files with their answers, such as screenshots.
+type AttachmentUI interface {
+	// Attachments returns the files sent with the answer last returned by Ask, with their content.
+	Attachments() []domain.Attachment
+}
+
+// AttachmentReceiver is implemented by providers that can take files as well as text, such as multimodal
+// models.
+type AttachmentReceiver interface {
+	// ReceiveAttachments passes the files sent with the answer given to the next call to NextQuestion.
+	ReceiveAttachments(attachments []domain.Attachment)
+}
+
+// Checkpointer saves the progress of an interview after each answer, so it can be resumed if it is
+// interrupted.
+type Checkpointer interface {
+	// Checkpoint saves the transcript of the answers given so far.
+	Checkpoint(transcript *domain.Transcript) error
+}
+
+// Publisher is told about each interview once it has been saved, such as to share its summary with a team.
+type Publisher interface {
+	// Publish is called with the saved interview, before the participant is shown the summary.
+	Publish(event domain.InterviewCompleted)
+}
+
+// Redactor removes sensitive information from answers before they reach the provider or the repository.
+type Redactor interface {
+	// Redact returns the text with any sensitive information replaced by tokens.
+	Redact(text string) (string, error)
+	// Redactions returns a record of every token issued so far.
+	Redactions() []domain.Redaction
+}
+
 // QuestionAndAnswer holds a question and its corresponding answer.
 type QuestionAndAnswer struct {
 	Question string
@@ -35,15 +121,187 @@ type Interview struct {
 	Provider QuestionProvider
 	UI       InterviewUI
 	Repo     storage.Repository
+	Redactor Redactor
+	// Attributes are recorded on the interview to describe the participant.
+	Attributes map[string]string
+	// Checkpointer is given the transcript after each answer, if set.
+	Checkpointer Checkpointer
+	// Transcript holds the answers given before the interview was resumed, if it was.
+	Transcript *domain.Transcript
+	// Publisher is told about the interview once it has been saved, if set.
+	Publisher Publisher
+	// Blobs stores the content of the files sent with answers. Without it, only their names are kept.
+	Blobs storage.BlobRepository
+	// UnredactedAttachments keeps the files sent with answers, and passes them to the provider, even though the
+	// Redactor can't redact them. Otherwise, only their names are kept while there is a Redactor.
+	UnredactedAttachments bool
+}
+
+// Option configures optional behaviour of an Interview.
+type Option func(*Interview)
+
+// WithRedactor redacts each answer before it is passed to the provider or saved.
+func WithRedactor(r Redactor) Option {
+	return func(i *Interview) {
+		i.Redactor = r
+	}
+}
+
+// WithAttributes records attributes describing the participant on the interview.
+func WithAttributes(attributes map[string]string) Option {
+	return func(i *Interview) {
+		i.Attributes = attributes
+	}
+}
+
+// WithCheckpointer saves the transcript after each answer, so the interview can be resumed with
+// WithTranscript if it is interrupted.
+func WithCheckpointer(c Checkpointer) Option {
+	return func(i *Interview) {
+		i.Checkpointer = c
+	}
+}
+
+// WithTranscript resumes an interview from the answers given so far, such as after a restart. The provider
+// must implement Resumer, and so should the redactor if there is one, so tokens aren't issued twice.
+func WithTranscript(transcript *domain.Transcript) Option {
+	return func(i *Interview) {
+		i.Transcript = transcript
+	}
+}
+
+// WithPublisher publishes an InterviewCompleted event once the interview has been saved.
+func WithPublisher(p Publisher) Option {
+	return func(i *Interview) {
+		i.Publisher = p
+	}
+}
+
+// WithBlobs stores the files participants send with their answers, if the UI lets them.
+func WithBlobs(blobs storage.BlobRepository) Option {
+	return func(i *Interview) {
+		i.Blobs = blobs
+	}
+}
+
+// WithUnredactedAttachments keeps the files sent with answers, and passes them to the provider, even though they
+// can't be redacted.
+func WithUnredactedAttachments() Option {
+	return func(i *Interview) {
+		i.UnredactedAttachments = true
+	}
 }
 
 // NewInterview creates a new Interview.
-func NewInterview(provider QuestionProvider, ui InterviewUI, repo storage.Repository) *Interview {
-	return &Interview{
+func NewInterview(provider QuestionProvider, ui InterviewUI, repo storage.Repository, opts ...Option) *Interview {
+	i := &Interview{
 		Provider: provider,
 		UI:       ui,
 		Repo:     repo,
 	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// nextQuestion asks the provider for its next question, returning an error if the provider can report one.
+func (i *Interview) nextQuestion(answer string) (string, bool, error) {
+	if f, ok := i.Provider.(FallibleQuestionProvider); ok {
+		return f.TryNextQuestion(answer)
+	}
+	question, hasMore := i.Provider.NextQuestion(answer)
+	return question, hasMore, nil
+}
+
+// ask asks the question through the UI. UIs that can present choices and progress are given the parsed
+// question, and other UIs are given it as plain text.
+func (i *Interview) ask(question string, number int) (string, error) {
+	parsed := ParseQuestion(question)
+	q, ok := i.UI.(QuestionUI)
+	if !ok {
+		return i.UI.Ask(parsed.String())
+	}
+
+	parsed.Number = number
+	if c, ok := i.Provider.(QuestionCounter); ok {
+		parsed.Total = c.QuestionCount()
+	}
+	return q.AskQuestion(parsed)
+}
+
+// resume restores the provider and redactor from the transcript of an interrupted interview, returning the
+// answer to its last question.
+func (i *Interview) resume() (string, error) {
+	if len(i.Transcript.Entries) == 0 {
+		return "", nil
+	}
+
+	r, ok := i.Provider.(Resumer)
+	if !ok {
+		return "", ErrNotResumable
+	}
+	if err := r.Resume(i.Transcript); err != nil {
+		return "", fmt.Errorf("could not resume provider: %w", err)
+	}
+	if r, ok := i.Redactor.(Resumer); ok {
+		if err := r.Resume(i.Transcript); err != nil {
+			return "", fmt.Errorf("could not resume redactor: %w", err)
+		}
+	}
+	return i.Transcript.Entries[len(i.Transcript.Entries)-1].Answer, nil
+}
+
+// attachments returns the files sent with the answer to the question at the position in the transcript, if the
+// UI lets participants send them. Their content is stored as blobs.
+func (i *Interview) attachments(entry int) ([]domain.Attachment, error) {
+	a, ok := i.UI.(AttachmentUI)
+	if !ok {
+		return nil, nil
+	}
+	attachments := a.Attachments()
+	for n := range attachments {
+		attachments[n].Entry = entry
+		attachments[n].Size = len(attachments[n].Data)
+		if i.Blobs == nil || !i.keepAttachments() {
+			continue
+		}
+		blobID, err := i.Blobs.SaveBlob(attachments[n].Data)
+		if err != nil {
+			return nil, fmt.Errorf("could not save attachment: %w", err)
+		}
+		attachments[n].BlobID = blobID
+	}
+	return attachments, nil
+}
+
+// keepAttachments reports whether the content of the files sent with answers can be kept and passed to the
+// provider. Files can't be redacted, so they are only kept if redaction is off, unless that is overridden.
+func (i *Interview) keepAttachments() bool {
+	return i.Redactor == nil || i.UnredactedAttachments
+}
+
+// attachedAnswer describes the files sent in place of an answer, so the transcript still reads as one.
+func attachedAnswer(attachments []domain.Attachment) string {
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		names = append(names, attachment.Name)
+	}
+	return fmt.Sprintf("(attached %s)", strings.Join(names, ", "))
+}
+
+// checkpoint passes the answers given so far to the checkpointer, if there is one.
+func (i *Interview) checkpoint(transcript *domain.Transcript) error {
+	if i.Checkpointer == nil {
+		return nil
+	}
+	if i.Redactor != nil {
+		transcript.Redactions = i.Redactor.Redactions()
+	}
+	if err := i.Checkpointer.Checkpoint(transcript); err != nil {
+		return fmt.Errorf("could not save progress: %w", err)
+	}
+	return nil
 }
 
 // Run executes the interview loop.
@@ -52,19 +310,74 @@ func (i *Interview) Run(userID, projectID string) error {
 		Question string `json:"question"`
 		Answer   string `json:"answer"`
 	}
+	var attachments []domain.Attachment
 	var answer string
+	var abandoned bool
 	var err error
 
+	if i.Transcript != nil {
+		transcriptEntries = append(transcriptEntries, i.Transcript.Entries...)
+		attachments = append(attachments, i.Transcript.Attachments...)
+		if answer, err = i.resume(); err != nil {
+			return err
+		}
+	}
+
 	for {
-		question, hasMore := i.Provider.NextQuestion(answer)
+		question, hasMore, err := i.nextQuestion(answer)
+		if err != nil {
+			// The answers given so far are saved, as they are when the participant stops answering.
+			slog.Error("Could not get next question, ending the interview", "error", err, "answers", len(transcriptEntries))
+			abandoned = true
+			break
+		}
 		if !hasMore {
 			break
 		}
 
-		answer, err = i.UI.Ask(question)
+		answer, err = i.ask(question, len(transcriptEntries)+1)
+		if errors.Is(err, ErrStopped) {
+			break
+		}
+		if errors.Is(err, ErrAbandoned) {
+			abandoned = true
+			break
+		}
+		if errors.Is(err, ErrSkipped) {
+			answer, err = SkippedAnswer, nil
+		}
 		if err != nil {
 			return fmt.Errorf("error asking question: %w", err)
 		}
+		if a, ok := i.UI.(AskedQuestionUI); ok && a.AskedQuestion() != ParseQuestion(question).Text {
+			// The answer is to the question the participant saw, so that is the one the provider carries on from.
+			question = a.AskedQuestion()
+			if r, ok := i.Provider.(interface{ ReplaceQuestion(question string) }); ok {
+				r.ReplaceQuestion(question)
+			}
+		}
+
+		attached, err := i.attachments(len(transcriptEntries))
+		if err != nil {
+			return err
+		}
+		if answer == "" && len(attached) > 0 {
+			answer = attachedAnswer(attached)
+		}
+		if i.Redactor != nil && answer != SkippedAnswer {
+			answer, err = i.Redactor.Redact(answer)
+			if err != nil {
+				return fmt.Errorf("could not redact answer: %w", err)
+			}
+		}
+		if r, ok := i.Provider.(AttachmentReceiver); ok && len(attached) > 0 && i.keepAttachments() {
+			r.ReceiveAttachments(attached)
+		}
+		for _, attachment := range attached {
+			// The content is in the blob, so it isn't held on to for the rest of the interview.
+			attachment.Data = nil
+			attachments = append(attachments, attachment)
+		}
 
 		transcriptEntries = append(transcriptEntries, struct {
 			Question string `json:"question"`
@@ -73,27 +386,46 @@ func (i *Interview) Run(userID, projectID string) error {
 			Question: question,
 			Answer:   answer,
 		})
+		if err := i.checkpoint(&domain.Transcript{Entries: transcriptEntries, Attachments: attachments}); err != nil {
+			return err
+		}
 	}
 
 	// Create the transcript
 	transcript := &domain.Transcript{
-		Entries: transcriptEntries,
+		Entries:     transcriptEntries,
+		Attachments: attachments,
+	}
+	if i.Redactor != nil {
+		transcript.Redactions = i.Redactor.Redactions()
 	}
 
 	// Generate the summary
 	summaryText, err := i.Provider.Summarize(transcript)
-	if err != nil {
+	if err != nil && !abandoned {
 		return fmt.Errorf("could not generate summary: %w", err)
 	}
+	if err != nil {
+		// The provider may be the reason the interview ended, so it is saved without a summary.
+		slog.Warn("Could not generate summary, saving the interview without one", "error", err)
+	}
 	summary := &domain.Summary{
 		Text: summaryText,
 	}
 
 	// Create the interview metadata
 	interview := &domain.Interview{
-		UserID:    userID,
-		ProjectID: projectID,
-		CreatedAt: time.Now(),
+		UserID:     userID,
+		ProjectID:  projectID,
+		CreatedAt:  time.Now(),
+		Attributes: i.Attributes,
+		Abandoned:  abandoned,
+	}
+	if d, ok := i.Provider.(Describer); ok {
+		interview.Provider = d.Describe()
+	}
+	if f, ok := i.Provider.(FailoverReporter); ok {
+		interview.Failovers = f.Failovers()
 	}
 
 	// Save the interview
@@ -101,8 +433,12 @@ func (i *Interview) Run(userID, projectID string) error {
 	if err != nil {
 		return fmt.Errorf("could not save interview: %w", err)
 	}
+	interview.ID = interviewID
 	transcript.InterviewID = interviewID
 	summary.InterviewID = interviewID
+	if i.Publisher != nil {
+		i.Publisher.Publish(domain.InterviewCompleted{Interview: interview, Transcript: transcript, Summary: summary})
+	}
 
 	// Display the summary to the user
 	i.UI.DisplaySummary(summary.Text)
@@ -0,0 +1,358 @@
+package interview_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listProvider asks a fixed list of questions, recording the answers it is given.
+type listProvider struct {
+	questions []string
+	next      int
+	answers   []string
+}
+
+func (p *listProvider) NextQuestion(previousAnswer string) (string, bool) {
+	if previousAnswer != "" {
+		p.answers = append(p.answers, previousAnswer)
+	}
+	if p.next >= len(p.questions) {
+		return "", false
+	}
+	p.next++
+	return p.questions[p.next-1], true
+}
+
+func (p *listProvider) Summarize(transcript *domain.Transcript) (string, error) {
+	return "summary", nil
+}
+
+// resumableProvider is a listProvider that can pick up part way through.
+type resumableProvider struct {
+	listProvider
+}
+
+func (p *resumableProvider) Resume(transcript *domain.Transcript) error {
+	p.next = len(transcript.Entries)
+	return nil
+}
+
+// scriptedUI answers each question with the next of its answers.
+type scriptedUI struct {
+	answers []string
+	asked   []string
+}
+
+func (u *scriptedUI) Ask(question string) (string, error) {
+	u.asked = append(u.asked, question)
+	answer := u.answers[0]
+	u.answers = u.answers[1:]
+	return answer, nil
+}
+
+func (u *scriptedUI) DisplaySummary(summary string) {}
+
+// recordingCheckpointer records each transcript it is given.
+type recordingCheckpointer struct {
+	transcripts []domain.Transcript
+}
+
+func (c *recordingCheckpointer) Checkpoint(transcript *domain.Transcript) error {
+	c.transcripts = append(c.transcripts, *transcript)
+	return nil
+}
+
+// recordingPublisher records each event it is given.
+type recordingPublisher struct {
+	events []domain.InterviewCompleted
+}
+
+func (p *recordingPublisher) Publish(event domain.InterviewCompleted) {
+	p.events = append(p.events, event)
+}
+
+// memoryRepository keeps the last interview saved.
+type memoryRepository struct {
+	interview  *domain.Interview
+	transcript *domain.Transcript
+	summary    *domain.Summary
+}
+
+func (m *memoryRepository) SaveInterview(interview *domain.Interview, transcript *domain.Transcript, summary *domain.Summary) (string, error) {
+	m.interview = interview
+	m.transcript = transcript
+	m.summary = summary
+	return "interview-1", nil
+}
+
+func (m *memoryRepository) GetInterview(id string) (*domain.Interview, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (m *memoryRepository) GetTranscript(interviewID string) (*domain.Transcript, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (m *memoryRepository) GetSummary(interviewID string) (*domain.Summary, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (m *memoryRepository) ListInterviews() ([]*domain.Interview, error) {
+	return nil, nil
+}
+
+func (m *memoryRepository) UpdateInterview(id string, update func(interview *domain.Interview) error) (*domain.Interview, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (m *memoryRepository) DeleteInterview(id string) error {
+	return storage.ErrNotFound
+}
+
+func (m *memoryRepository) Close() error {
+	return nil
+}
+
+// transcriptOf builds a transcript from pairs of questions and answers.
+func transcriptOf(pairs ...string) *domain.Transcript {
+	transcript := &domain.Transcript{}
+	for i := 0; i < len(pairs); i += 2 {
+		transcript.Entries = append(transcript.Entries, struct {
+			Question string `json:"question"`
+			Answer   string `json:"answer"`
+		}{Question: pairs[i], Answer: pairs[i+1]})
+	}
+	return transcript
+}
+
+func TestInterview_Checkpoint(t *testing.T) {
+	provider := &listProvider{questions: []string{"What do you like?", "What would you change?"}}
+	checkpointer := &recordingCheckpointer{}
+	repo := &memoryRepository{}
+	ui := &scriptedUI{answers: []string{"The speed.", "Nothing."}}
+
+	err := interview.NewInterview(provider, ui, repo, interview.WithCheckpointer(checkpointer)).Run("U123", "feedback")
+	require.NoError(t, err)
+
+	require.Len(t, checkpointer.transcripts, 2)
+	assert.Equal(t, *transcriptOf("What do you like?", "The speed."), checkpointer.transcripts[0])
+	assert.Equal(t, *transcriptOf("What do you like?", "The speed.", "What would you change?", "Nothing."), checkpointer.transcripts[1])
+}
+
+// failingProvider is a listProvider whose API fails once its questions run out, and that can't summarise.
+type failingProvider struct {
+	listProvider
+}
+
+func (p *failingProvider) TryNextQuestion(previousAnswer string) (string, bool, error) {
+	if question, hasMore := p.NextQuestion(previousAnswer); hasMore {
+		return question, true, nil
+	}
+	return "", false, errors.New("upstream unavailable")
+}
+
+func (p *failingProvider) Summarize(transcript *domain.Transcript) (string, error) {
+	return "", errors.New("upstream unavailable")
+}
+
+func TestInterview_ProviderFailure(t *testing.T) {
+	provider := &failingProvider{listProvider{questions: []string{"What do you like?", "What would you change?"}}}
+	repo := &memoryRepository{}
+	ui := &scriptedUI{answers: []string{"The speed.", "Nothing."}}
+
+	err := interview.NewInterview(provider, ui, repo).Run("U123", "feedback")
+	require.NoError(t, err)
+
+	require.NotNil(t, repo.interview, "the answers given before the provider failed should be saved")
+	assert.True(t, repo.interview.Abandoned)
+	assert.Equal(t, transcriptOf("What do you like?", "The speed.", "What would you change?", "Nothing.").Entries, repo.transcript.Entries)
+	assert.Empty(t, repo.summary.Text)
+}
+
+// rephrasingProvider is a resumableProvider that records the questions it is told were asked in its place.
+type rephrasingProvider struct {
+	resumableProvider
+	replaced []string
+}
+
+func (p *rephrasingProvider) ReplaceQuestion(question string) {
+	p.replaced = append(p.replaced, question)
+}
+
+// resumedUI is a scriptedUI that asks the question sent before the interview was interrupted in place of the
+// first one it is given.
+type resumedUI struct {
+	scriptedUI
+	pending string
+	last    string
+}
+
+func (u *resumedUI) Ask(question string) (string, error) {
+	u.last = question
+	if u.pending != "" {
+		u.last, u.pending = u.pending, ""
+	}
+	return u.scriptedUI.Ask(u.last)
+}
+
+func (u *resumedUI) AskedQuestion() string { return u.last }
+
+func TestInterview_AskedQuestion(t *testing.T) {
+	provider := &rephrasingProvider{resumableProvider: resumableProvider{listProvider{questions: []string{"What do you like?", "What would you change about it?", "Anything else?"}}}}
+	repo := &memoryRepository{}
+	ui := &resumedUI{scriptedUI: scriptedUI{answers: []string{"Nothing.", "No."}}, pending: "What would you change?"}
+
+	err := interview.NewInterview(provider, ui, repo, interview.WithTranscript(transcriptOf("What do you like?", "The speed."))).Run("U123", "feedback")
+	require.NoError(t, err)
+
+	assert.Equal(t, transcriptOf("What do you like?", "The speed.", "What would you change?", "Nothing.", "Anything else?", "No.").Entries, repo.transcript.Entries)
+	assert.Equal(t, []string{"What would you change?"}, provider.replaced)
+}
+
+func TestInterview_Publish(t *testing.T) {
+	provider := &listProvider{questions: []string{"What do you like?"}}
+	publisher := &recordingPublisher{}
+	ui := &scriptedUI{answers: []string{"The speed."}}
+
+	err := interview.NewInterview(provider, ui, &memoryRepository{}, interview.WithPublisher(publisher)).Run("U123", "feedback")
+	require.NoError(t, err)
+
+	require.Len(t, publisher.events, 1)
+	event := publisher.events[0]
+	assert.Equal(t, "interview-1", event.Interview.ID)
+	assert.Equal(t, "U123", event.Interview.UserID)
+	assert.Equal(t, "feedback", event.Interview.ProjectID)
+	assert.Equal(t, transcriptOf("What do you like?", "The speed.").Entries, event.Transcript.Entries)
+	assert.Equal(t, "interview-1", event.Summary.InterviewID)
+}
+
+func TestInterview_Resume(t *testing.T) {
+	t.Run("should carry on from the last answer", func(t *testing.T) {
+		provider := &resumableProvider{listProvider{questions: []string{"What do you like?", "What would you change?"}}}
+		repo := &memoryRepository{}
+		ui := &scriptedUI{answers: []string{"Nothing."}}
+
+		err := interview.NewInterview(provider, ui, repo, interview.WithTranscript(transcriptOf("What do you like?", "The speed."))).Run("U123", "feedback")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"What would you change?"}, ui.asked)
+		assert.Equal(t, []string{"The speed.", "Nothing."}, provider.answers)
+		assert.Equal(t, transcriptOf("What do you like?", "The speed.", "What would you change?", "Nothing.").Entries, repo.transcript.Entries)
+	})
+
+	t.Run("should fail if the provider can't resume", func(t *testing.T) {
+		provider := &listProvider{questions: []string{"What do you like?"}}
+		err := interview.NewInterview(provider, &scriptedUI{}, &memoryRepository{}, interview.WithTranscript(transcriptOf("What do you like?", "The speed."))).Run("U123", "feedback")
+		assert.ErrorIs(t, err, interview.ErrNotResumable)
+	})
+}
+
+// attachingUI is a scriptedUI that sends files with its answers.
+type attachingUI struct {
+	scriptedUI
+	files [][]domain.Attachment
+	sent  []domain.Attachment
+}
+
+func (u *attachingUI) Ask(question string) (string, error) {
+	u.sent, u.files = u.files[0], u.files[1:]
+	return u.scriptedUI.Ask(question)
+}
+
+func (u *attachingUI) Attachments() []domain.Attachment {
+	return u.sent
+}
+
+// multimodalProvider is a listProvider that records the files it is given.
+type multimodalProvider struct {
+	listProvider
+	received []domain.Attachment
+}
+
+func (p *multimodalProvider) ReceiveAttachments(attachments []domain.Attachment) {
+	p.received = append(p.received, attachments...)
+}
+
+// memoryBlobs keeps blobs in memory.
+type memoryBlobs map[string][]byte
+
+func (m memoryBlobs) SaveBlob(data []byte) (string, error) {
+	id := fmt.Sprintf("blob-%d", len(m)+1)
+	m[id] = data
+	return id, nil
+}
+
+func (m memoryBlobs) GetBlob(id string) ([]byte, error) {
+	data, ok := m[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return data, nil
+}
+
+// upperRedactor "redacts" answers by upper-casing them.
+type upperRedactor struct{}
+
+func (upperRedactor) Redact(text string) (string, error) { return strings.ToUpper(text), nil }
+func (upperRedactor) Redactions() []domain.Redaction     { return nil }
+
+func TestInterview_Attachments(t *testing.T) {
+	screenshot := domain.Attachment{Name: "dashboard.png", MimeType: "image/png", Data: []byte("\x89PNG")}
+	newUI := func() *attachingUI {
+		return &attachingUI{
+			scriptedUI: scriptedUI{answers: []string{"The speed.", ""}},
+			files:      [][]domain.Attachment{nil, {screenshot}},
+		}
+	}
+
+	t.Run("should store the files and pass them to the provider", func(t *testing.T) {
+		provider := &multimodalProvider{listProvider: listProvider{questions: []string{"What do you like?", "What does your dashboard look like?"}}}
+		repo := &memoryRepository{}
+		blobs := memoryBlobs{}
+
+		err := interview.NewInterview(provider, newUI(), repo, interview.WithBlobs(blobs)).Run("U123", "feedback")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"The speed.", "(attached dashboard.png)"}, provider.answers)
+		require.Len(t, provider.received, 1)
+		assert.Equal(t, screenshot.Data, provider.received[0].Data)
+		assert.Equal(t, []domain.Attachment{{Entry: 1, Name: "dashboard.png", MimeType: "image/png", Size: 4, BlobID: "blob-1"}}, repo.transcript.Attachments)
+		assert.Equal(t, screenshot.Data, blobs["blob-1"])
+	})
+
+	t.Run("should neither store the files nor pass them to the provider if answers are redacted", func(t *testing.T) {
+		provider := &multimodalProvider{listProvider: listProvider{questions: []string{"What do you like?", "What does your dashboard look like?"}}}
+		repo := &memoryRepository{}
+		blobs := memoryBlobs{}
+
+		err := interview.NewInterview(provider, newUI(), repo, interview.WithRedactor(upperRedactor{}), interview.WithBlobs(blobs)).Run("U123", "feedback")
+		require.NoError(t, err)
+
+		assert.Empty(t, provider.received)
+		assert.Empty(t, blobs)
+		assert.Equal(t, []domain.Attachment{{Entry: 1, Name: "dashboard.png", MimeType: "image/png", Size: 4}}, repo.transcript.Attachments)
+	})
+
+	t.Run("should store the files and pass them to the provider if unredacted attachments are allowed", func(t *testing.T) {
+		provider := &multimodalProvider{listProvider: listProvider{questions: []string{"What do you like?", "What does your dashboard look like?"}}}
+		repo := &memoryRepository{}
+		blobs := memoryBlobs{}
+
+		err := interview.NewInterview(provider, newUI(), repo, interview.WithRedactor(upperRedactor{}), interview.WithBlobs(blobs),
+			interview.WithUnredactedAttachments()).Run("U123", "feedback")
+		require.NoError(t, err)
+
+		require.Len(t, provider.received, 1)
+		assert.Equal(t, screenshot.Data, provider.received[0].Data)
+		assert.Equal(t, screenshot.Data, blobs["blob-1"])
+		assert.Equal(t, []domain.Attachment{{Entry: 1, Name: "dashboard.png", MimeType: "image/png", Size: 4, BlobID: "blob-1"}}, repo.transcript.Attachments)
+	})
+}
@@ -0,0 +1,76 @@
+package interview
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrSkipped is returned by QuestionUI.AskQuestion when the participant chooses not to answer a question. The
+// interview carries on with the next question.
+var ErrSkipped = errors.New("the participant skipped the question")
+
+// SkippedAnswer is recorded in place of the answer to a question that was skipped.
+const SkippedAnswer = "(skipped)"
+
+// LikertScale is offered for questions marked with [likert].
+var LikertScale = []string{"Strongly disagree", "Disagree", "Neutral", "Agree", "Strongly agree"}
+
+// Question is a question as it is presented to the participant.
+type Question struct {
+	// Text is the question, without its choices.
+	Text string
+	// Choices are the answers the participant can pick from. It is empty for free-text questions.
+	Choices []string
+	// Number is the position of the question in the interview, starting at 1.
+	Number int
+	// Total is the number of questions in the interview, or 0 if it isn't known in advance.
+	Total int
+}
+
+// QuestionUI is implemented by UIs that can present choices, progress, and controls to skip a question or
+// wrap up the interview early.
+type QuestionUI interface {
+	// AskQuestion asks the question and returns the answer. It returns ErrSkipped if the participant skips the
+	// question, and ErrStopped if they wrap up the interview.
+	AskQuestion(question Question) (answer string, err error)
+}
+
+// QuestionCounter is implemented by providers that know how many questions they will ask.
+type QuestionCounter interface {
+	QuestionCount() int
+}
+
+// ParseQuestion reads the choices from a question. A question that ends with its choices in brackets, such as
+// "Which plan are you on? [Free | Pro | Enterprise]", offers those choices, and one that ends with [likert]
+// offers the LikertScale.
+func ParseQuestion(question string) Question {
+	text := strings.TrimSpace(question)
+	open := strings.LastIndex(text, "[")
+	if open < 0 || !strings.HasSuffix(text, "]") {
+		return Question{Text: text}
+	}
+
+	inner := strings.TrimSpace(text[open+1 : len(text)-1])
+	if strings.EqualFold(inner, "likert") {
+		return Question{Text: strings.TrimSpace(text[:open]), Choices: LikertScale}
+	}
+	if !strings.Contains(inner, "|") {
+		return Question{Text: text}
+	}
+
+	var choices []string
+	for _, choice := range strings.Split(inner, "|") {
+		if choice = strings.TrimSpace(choice); choice != "" {
+			choices = append(choices, choice)
+		}
+	}
+	return Question{Text: strings.TrimSpace(text[:open]), Choices: choices}
+}
+
+// String returns the question as plain text, with its choices listed after it.
+func (q Question) String() string {
+	if len(q.Choices) == 0 {
+		return q.Text
+	}
+	return q.Text + " (" + strings.Join(q.Choices, " / ") + ")"
+}
@@ -0,0 +1,48 @@
+package interview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuestion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		question string
+		want     Question
+	}{
+		{name: "free text", question: "What do you like?", want: Question{Text: "What do you like?"}},
+		{
+			name:     "choices",
+			question: "Which plan are you on? [Free | Pro | Enterprise]",
+			want:     Question{Text: "Which plan are you on?", Choices: []string{"Free", "Pro", "Enterprise"}},
+		},
+		{
+			name:     "likert",
+			question: "The product is easy to use. [Likert]",
+			want:     Question{Text: "The product is easy to use.", Choices: LikertScale},
+		},
+		{
+			name:     "brackets without choices",
+			question: "What do you think of [product]?",
+			want:     Question{Text: "What do you think of [product]?"},
+		},
+		{
+			name:     "single bracketed word",
+			question: "Tell me about the [beta]",
+			want:     Question{Text: "Tell me about the [beta]"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ParseQuestion(tc.question))
+		})
+	}
+}
+
+func TestQuestion_String(t *testing.T) {
+	assert.Equal(t, "What do you like?", Question{Text: "What do you like?"}.String())
+	assert.Equal(t, "Which plan are you on? (Free / Pro)", ParseQuestion("Which plan are you on? [Free | Pro]").String())
+}
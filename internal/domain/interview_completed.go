@@ -0,0 +1,17 @@
+package domain
+
+// InterviewCompleted is published once an interview has finished and been saved, whether the participant
+// answered every question or it was abandoned.
+type InterviewCompleted struct {
+	Interview  *Interview
+	Transcript *Transcript
+	Summary    *Summary
+}
+
+// SummaryCard records a message in Slack with the summary of an interview, so reactions to it can be traced
+// back to the interview.
+type SummaryCard struct {
+	InterviewID string `json:"interview_id"`
+	ChannelID   string `json:"channel_id"`
+	Timestamp   string `json:"timestamp"`
+}
@@ -8,6 +8,49 @@ type Interview struct {
 	UserID    string    `json:"user_id"`
 	ProjectID string    `json:"project_id"`
 	CreatedAt time.Time `json:"created_at"`
+	// Attributes describe the participant, such as those bound to the invite they used.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Provider records the provider and settings that were used to conduct the interview.
+	Provider *ProviderInfo `json:"provider,omitempty"`
+	// Failovers records each time the interview switched to another provider.
+	Failovers []Failover `json:"failovers,omitempty"`
+	// Abandoned is set if the participant stopped answering or the provider failed, and the interview was
+	// ended with the answers given so far.
+	Abandoned bool `json:"abandoned,omitempty"`
+	// Triage records the labels given to the interview by reacting to its summary in Slack.
+	Triage []Triage `json:"triage,omitempty"`
+}
+
+// Triage records a label given to an interview, such as "reviewed", and who gave it.
+type Triage struct {
+	Label  string    `json:"label"`
+	UserID string    `json:"user_id"`
+	At     time.Time `json:"at"`
+}
+
+// Failover records a switch from one provider to another part way through an interview.
+type Failover struct {
+	At       time.Time `json:"at"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Reason   string    `json:"reason"`
+	Question int       `json:"question"`
+}
+
+// ProviderInfo describes a question provider and the settings it used.
+type ProviderInfo struct {
+	Name       string      `json:"name"`
+	Model      string      `json:"model,omitempty"`
+	Generation *Generation `json:"generation,omitempty"`
+}
+
+// Generation holds the generation settings sent to a model. Unset fields used the model's defaults.
+type Generation struct {
+	Temperature     *float32          `json:"temperature,omitempty"`
+	TopP            *float32          `json:"top_p,omitempty"`
+	MaxOutputTokens *int32            `json:"max_output_tokens,omitempty"`
+	StopSequences   []string          `json:"stop_sequences,omitempty"`
+	Safety          map[string]string `json:"safety,omitempty"`
 }
 
 // Transcript holds the full question-and-answer record of an interview.
@@ -17,6 +60,9 @@ type Transcript struct {
 		Question string `json:"question"`
 		Answer   string `json:"answer"`
 	} `json:"entries"`
+	Redactions []Redaction `json:"redactions,omitempty"`
+	// Attachments are the files the participant sent with their answers.
+	Attachments []Attachment `json:"attachments,omitempty"`
 }
 
 // Summary holds the generated summary of an interview.
@@ -24,3 +70,24 @@ type Summary struct {
 	InterviewID string `json:"interview_id"`
 	Text        string `json:"text"`
 }
+
+// Redaction records a piece of sensitive information that was replaced by a token before it left the UI.
+type Redaction struct {
+	Token string `json:"token"`
+	Kind  string `json:"kind"`
+	// Sealed is the encrypted original value. It is empty when redaction is not reversible.
+	Sealed string `json:"sealed,omitempty"`
+}
+
+// Attachment is a file the participant sent with an answer, such as a screenshot.
+type Attachment struct {
+	// Entry is the position in the transcript of the answer the file was sent with, starting at 0.
+	Entry    int    `json:"entry"`
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type"`
+	Size     int    `json:"size"`
+	// BlobID identifies the file's content in the repository. It is empty if the content wasn't kept.
+	BlobID string `json:"blob_id,omitempty"`
+	// Data is the file's content. It is stored as a blob, rather than with the transcript.
+	Data []byte `json:"-"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// InviteStatus is a stage in the life of an invite.
+type InviteStatus string
+
+// The stages an invite passes through.
+const (
+	InviteSent      InviteStatus = "sent"
+	InviteOpened    InviteStatus = "opened"
+	InviteStarted   InviteStatus = "started"
+	InviteCompleted InviteStatus = "completed"
+	InviteRevoked   InviteStatus = "revoked"
+)
+
+// Invite allows a participant from outside the organisation to take an interview through a signed link.
+type Invite struct {
+	ID      string `json:"id"`
+	TopicID string `json:"topic_id"`
+	// Participant identifies who the invite was sent to, such as their email address.
+	Participant string `json:"participant,omitempty"`
+	// Attributes are recorded on every interview taken with the invite, such as the participant's company.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	// MaxUses is the number of interviews that can be started with the invite. Zero means no limit.
+	MaxUses int `json:"max_uses,omitempty"`
+	Uses    int `json:"uses"`
+	// RevokedAt is set once the invite has been revoked, after which it can't be opened or used.
+	RevokedAt *time.Time    `json:"revoked_at,omitempty"`
+	Events    []InviteEvent `json:"events"`
+}
+
+// InviteEvent records when an invite reached a stage.
+type InviteEvent struct {
+	Status InviteStatus `json:"status"`
+	At     time.Time    `json:"at"`
+}
+
+// Status returns the latest stage the invite has reached.
+func (i *Invite) Status() InviteStatus {
+	if i.RevokedAt != nil {
+		return InviteRevoked
+	}
+	if len(i.Events) == 0 {
+		return InviteSent
+	}
+	return i.Events[len(i.Events)-1].Status
+}
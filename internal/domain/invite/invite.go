@@ -0,0 +1,182 @@
+// Package invite manages signed links that let participants from outside the organisation take an interview.
+package invite
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/google/uuid"
+)
+
+// minKeySize is the minimum size of the signing key, in bytes.
+const minKeySize = 32
+
+// Err* are common errors
+var (
+	ErrInvalidKey   = errors.New("the invite key must be a base64-encoded key of at least 32 bytes")
+	ErrInvalidToken = errors.New("the invite link is not valid")
+	ErrExpired      = errors.New("the invite has expired")
+	ErrRevoked      = errors.New("the invite has been revoked")
+	ErrUsedUp       = errors.New("the invite has already been used")
+)
+
+// Signer creates and verifies the tokens in invite links.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a Signer from a base64-encoded key.
+func NewSigner(key string) (*Signer, error) {
+	k, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(k) < minKeySize {
+		return nil, ErrInvalidKey
+	}
+	return &Signer{key: k}, nil
+}
+
+// Token returns the signed token for an invite ID.
+func (s *Signer) Token(id string) string {
+	return id + "." + base64.RawURLEncoding.EncodeToString(s.sign(id))
+}
+
+// Verify checks the signature of a token, and returns the invite ID it was issued for.
+func (s *Signer) Verify(token string) (string, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.sign(id)) {
+		return "", ErrInvalidToken
+	}
+	return id, nil
+}
+
+// sign returns the signature of an invite ID.
+func (s *Signer) sign(id string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id))
+	return mac.Sum(nil)
+}
+
+// Service creates invites and enforces their limits as participants use them.
+type Service struct {
+	repo   storage.InviteRepository
+	signer *Signer
+	now    func() time.Time
+}
+
+// NewService creates a Service that stores invites in the repository.
+func NewService(repo storage.InviteRepository, signer *Signer) *Service {
+	return &Service{
+		repo:   repo,
+		signer: signer,
+		now:    time.Now,
+	}
+}
+
+// Create creates an invite for the topic, and returns it along with its token.
+func (s *Service) Create(topicID, participant string, attributes map[string]string, expires time.Duration, maxUses int) (*domain.Invite, string, error) {
+	now := s.now()
+	inv := &domain.Invite{
+		ID:          uuid.NewString(),
+		TopicID:     topicID,
+		Participant: participant,
+		Attributes:  attributes,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(expires),
+		MaxUses:     maxUses,
+		Events:      []domain.InviteEvent{{Status: domain.InviteSent, At: now}},
+	}
+	if err := s.repo.SaveInvite(inv); err != nil {
+		return nil, "", fmt.Errorf("could not save invite: %w", err)
+	}
+	return inv, s.signer.Token(inv.ID), nil
+}
+
+// Open records that the participant opened the invite link, and returns the invite if it is still valid. An
+// invite that has been used up can still be opened, so a participant can return to an interview in progress.
+func (s *Service) Open(token string) (*domain.Invite, error) {
+	return s.update(token, func(inv *domain.Invite) error {
+		if err := s.check(inv); err != nil {
+			return err
+		}
+		if inv.Status() == domain.InviteSent {
+			s.record(inv, domain.InviteOpened)
+		}
+		return nil
+	})
+}
+
+// Start records that the participant started an interview with the invite, using up one of its uses.
+func (s *Service) Start(token string) (*domain.Invite, error) {
+	return s.update(token, func(inv *domain.Invite) error {
+		if err := s.check(inv); err != nil {
+			return err
+		}
+		if inv.MaxUses > 0 && inv.Uses >= inv.MaxUses {
+			return ErrUsedUp
+		}
+		inv.Uses++
+		s.record(inv, domain.InviteStarted)
+		return nil
+	})
+}
+
+// Complete records that the participant completed an interview started with the invite.
+func (s *Service) Complete(id string) error {
+	_, err := s.repo.UpdateInvite(id, func(inv *domain.Invite) error {
+		s.record(inv, domain.InviteCompleted)
+		return nil
+	})
+	return err
+}
+
+// Revoke stops the invite from being used again.
+func (s *Service) Revoke(id string) error {
+	_, err := s.repo.UpdateInvite(id, func(inv *domain.Invite) error {
+		if inv.RevokedAt == nil {
+			now := s.now()
+			inv.RevokedAt = &now
+			s.record(inv, domain.InviteRevoked)
+		}
+		return nil
+	})
+	return err
+}
+
+// update verifies the token and applies the update to its invite.
+func (s *Service) update(token string, update func(inv *domain.Invite) error) (*domain.Invite, error) {
+	id, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := s.repo.UpdateInvite(id, update)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	return inv, err
+}
+
+// check returns an error if the invite has been revoked or has expired.
+func (s *Service) check(inv *domain.Invite) error {
+	switch {
+	case inv.RevokedAt != nil:
+		return ErrRevoked
+	case !s.now().Before(inv.ExpiresAt):
+		return ErrExpired
+	}
+	return nil
+}
+
+// record adds an event to the invite.
+func (s *Service) record(inv *domain.Invite, status domain.InviteStatus) {
+	inv.Events = append(inv.Events, domain.InviteEvent{Status: status, At: s.now()})
+}
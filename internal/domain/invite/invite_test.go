@@ -0,0 +1,176 @@
+package invite
+
+import (
+	"encoding/base64"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository is an in-memory storage.InviteRepository for tests.
+type memoryRepository struct {
+	mu      sync.Mutex
+	invites map[string]domain.Invite
+}
+
+func (m *memoryRepository) SaveInvite(invite *domain.Invite) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.invites == nil {
+		m.invites = make(map[string]domain.Invite)
+	}
+	m.invites[invite.ID] = *invite
+	return nil
+}
+
+func (m *memoryRepository) GetInvite(id string) (*domain.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invite, ok := m.invites[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &invite, nil
+}
+
+func (m *memoryRepository) ListInvites() ([]*domain.Invite, error) {
+	return nil, nil
+}
+
+func (m *memoryRepository) UpdateInvite(id string, update func(*domain.Invite) error) (*domain.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invite, ok := m.invites[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	invite.Events = append([]domain.InviteEvent(nil), invite.Events...)
+	if err := update(&invite); err != nil {
+		return nil, err
+	}
+	m.invites[id] = invite
+	return &invite, nil
+}
+
+func newTestService(t *testing.T) (*Service, *memoryRepository, *time.Time) {
+	t.Helper()
+	signer, err := NewSigner(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.NoError(t, err)
+	repo := &memoryRepository{}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewService(repo, signer)
+	s.now = func() time.Time { return now }
+	return s, repo, &now
+}
+
+func TestSigner(t *testing.T) {
+	_, err := NewSigner(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	signer, err := NewSigner(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.NoError(t, err)
+	other, err := NewSigner(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", 32))))
+	require.NoError(t, err)
+
+	token := signer.Token("invite-1")
+	id, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "invite-1", id)
+
+	for _, token := range []string{other.Token("invite-1"), "invite-2" + token[len("invite-1"):], "invite-1", ""} {
+		_, err := signer.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken, token)
+	}
+}
+
+func TestService_Lifecycle(t *testing.T) {
+	s, repo, now := newTestService(t)
+
+	inv, token, err := s.Create("feedback", "ada@example.com", map[string]string{"company": "Acme"}, 7*24*time.Hour, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InviteSent, inv.Status())
+
+	opened, err := s.Open(token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InviteOpened, opened.Status())
+
+	started, err := s.Start(token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InviteStarted, started.Status())
+	assert.Equal(t, 1, started.Uses)
+
+	// A single-use invite can't start a second interview, but can still be opened.
+	_, err = s.Start(token)
+	assert.ErrorIs(t, err, ErrUsedUp)
+	_, err = s.Open(token)
+	assert.NoError(t, err)
+
+	*now = now.Add(time.Hour)
+	require.NoError(t, s.Complete(inv.ID))
+	stored, err := repo.GetInvite(inv.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InviteCompleted, stored.Status())
+	assert.Equal(t, []domain.InviteStatus{domain.InviteSent, domain.InviteOpened, domain.InviteStarted, domain.InviteCompleted}, statuses(stored))
+}
+
+func TestService_Limits(t *testing.T) {
+	t.Run("should reject expired invites", func(t *testing.T) {
+		s, _, now := newTestService(t)
+		_, token, err := s.Create("feedback", "", nil, time.Hour, 0)
+		require.NoError(t, err)
+
+		*now = now.Add(time.Hour)
+		_, err = s.Open(token)
+		assert.ErrorIs(t, err, ErrExpired)
+		_, err = s.Start(token)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("should reject revoked invites", func(t *testing.T) {
+		s, repo, _ := newTestService(t)
+		inv, token, err := s.Create("feedback", "", nil, time.Hour, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Revoke(inv.ID))
+		_, err = s.Open(token)
+		assert.ErrorIs(t, err, ErrRevoked)
+		_, err = s.Start(token)
+		assert.ErrorIs(t, err, ErrRevoked)
+
+		// Completing an interview that was already running doesn't undo the revocation.
+		require.NoError(t, s.Complete(inv.ID))
+		stored, err := repo.GetInvite(inv.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.InviteRevoked, stored.Status())
+	})
+
+	t.Run("should allow unlimited uses", func(t *testing.T) {
+		s, _, _ := newTestService(t)
+		_, token, err := s.Create("feedback", "", nil, time.Hour, 0)
+		require.NoError(t, err)
+		for range 3 {
+			_, err := s.Start(token)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("should reject tokens for unknown invites", func(t *testing.T) {
+		s, _, _ := newTestService(t)
+		_, err := s.Open(s.signer.Token("missing"))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func statuses(inv *domain.Invite) []domain.InviteStatus {
+	var out []domain.InviteStatus
+	for _, e := range inv.Events {
+		out = append(out, e.Status)
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package lint
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// Rephraser is implemented by question providers that can rewrite a question on request.
+type Rephraser interface {
+	// Rephrase rewrites the question, taking the supplied guidance into account.
+	Rephrase(question, guidance string) (string, error)
+}
+
+// QuestionReplacer is implemented by question providers that keep the conversation so far, so the question the
+// participant was actually asked can take the place of the one the provider generated.
+type QuestionReplacer interface {
+	// ReplaceQuestion replaces the last question in the conversation with the question.
+	ReplaceQuestion(question string)
+}
+
+// Guard is a QuestionProvider that checks each question before the participant sees it, and asks the
+// underlying provider to rephrase any question that has issues.
+type Guard struct {
+	provider  interview.QuestionProvider
+	rephraser Rephraser
+	linter    *Linter
+}
+
+// NewGuard wraps a provider with a live quality check. If the provider does not implement Rephraser,
+// questions with issues are logged and passed through unchanged.
+func NewGuard(provider interview.QuestionProvider, linter *Linter) *Guard {
+	g := &Guard{
+		provider: provider,
+		linter:   linter,
+	}
+	if r, ok := provider.(Rephraser); ok {
+		g.rephraser = r
+	}
+	return g
+}
+
+// NextQuestion returns the next question from the underlying provider, rephrased if necessary.
+func (g *Guard) NextQuestion(previousAnswer string) (string, bool) {
+	question, hasMore, err := g.TryNextQuestion(previousAnswer)
+	if err != nil {
+		slog.Error("Error getting next question", "error", err)
+		return "", false
+	}
+	return question, hasMore
+}
+
+// TryNextQuestion behaves like NextQuestion, but returns an error if the underlying provider failed.
+func (g *Guard) TryNextQuestion(previousAnswer string) (string, bool, error) {
+	var question string
+	var hasMore bool
+	if f, ok := g.provider.(interview.FallibleQuestionProvider); ok {
+		var err error
+		question, hasMore, err = f.TryNextQuestion(previousAnswer)
+		if err != nil {
+			return "", false, err
+		}
+	} else {
+		question, hasMore = g.provider.NextQuestion(previousAnswer)
+	}
+	if !hasMore {
+		return question, hasMore, nil
+	}
+	return g.check(question), hasMore, nil
+}
+
+// check returns the question, or a rephrased version of it if it has issues.
+func (g *Guard) check(question string) string {
+	result := g.linter.Check(question)
+	if result.OK() {
+		return question
+	}
+
+	slog.Debug("Question failed quality check", "question", question, "score", result.Score, "issues", result.Issues)
+	if g.rephraser == nil {
+		return question
+	}
+
+	rephrased, err := g.rephraser.Rephrase(question, Guidance(result))
+	if err != nil || strings.TrimSpace(rephrased) == "" {
+		slog.Warn("Could not rephrase question, asking it unchanged", "error", err)
+		return question
+	}
+
+	// Only use the rephrased question if it is actually an improvement.
+	if g.linter.Check(rephrased).Score <= result.Score {
+		return question
+	}
+	if r, ok := g.provider.(QuestionReplacer); ok {
+		r.ReplaceQuestion(rephrased)
+	}
+	return rephrased
+}
+
+// Summarize delegates to the underlying provider.
+func (g *Guard) Summarize(transcript *domain.Transcript) (string, error) {
+	return g.provider.Summarize(transcript)
+}
+
+// Resume delegates to the underlying provider, if it can pick up an interview part way through.
+func (g *Guard) Resume(transcript *domain.Transcript) error {
+	if r, ok := g.provider.(interview.Resumer); ok {
+		return r.Resume(transcript)
+	}
+	return nil
+}
+
+// Failovers reports the failovers made by the underlying provider, if it can make any.
+func (g *Guard) Failovers() []domain.Failover {
+	if f, ok := g.provider.(interview.FailoverReporter); ok {
+		return f.Failovers()
+	}
+	return nil
+}
+
+// ReceiveAttachments passes the files to the underlying provider, if it can take them.
+func (g *Guard) ReceiveAttachments(attachments []domain.Attachment) {
+	if r, ok := g.provider.(interview.AttachmentReceiver); ok {
+		r.ReceiveAttachments(attachments)
+	}
+}
+
+// ReplaceQuestion replaces the last question asked by the underlying provider, if it keeps the conversation.
+func (g *Guard) ReplaceQuestion(question string) {
+	if r, ok := g.provider.(QuestionReplacer); ok {
+		r.ReplaceQuestion(question)
+	}
+}
+
+// QuestionCount reports the number of questions the underlying provider will ask, if it knows.
+func (g *Guard) QuestionCount() int {
+	if c, ok := g.provider.(interview.QuestionCounter); ok {
+		return c.QuestionCount()
+	}
+	return 0
+}
+
+// Describe reports the settings of the underlying provider, if it can describe them.
+func (g *Guard) Describe() *domain.ProviderInfo {
+	if d, ok := g.provider.(interview.Describer); ok {
+		return d.Describe()
+	}
+	return nil
+}
+
+// Guidance describes the issues in a result in a form suitable to pass to a Rephraser.
+func Guidance(result Result) string {
+	var b strings.Builder
+	for _, issue := range result.Issues {
+		fmt.Fprintf(&b, "- %s: %s\n", issue.Rule, issue.Message)
+	}
+	return b.String()
+}
+
+// Ensure Guard implements the domain interface.
+var _ interview.QuestionProvider = (*Guard)(nil)
+var _ interview.FallibleQuestionProvider = (*Guard)(nil)
+var _ interview.Resumer = (*Guard)(nil)
+var _ interview.FailoverReporter = (*Guard)(nil)
+var _ interview.Describer = (*Guard)(nil)
+var _ interview.QuestionCounter = (*Guard)(nil)
+var _ interview.AttachmentReceiver = (*Guard)(nil)
+var _ QuestionReplacer = (*Guard)(nil)
@@ -0,0 +1,194 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+)
+
+// Rule identifies a class of interviewing mistake.
+type Rule string
+
+// Rules that the linter checks for.
+const (
+	RuleLeading         Rule = "leading"
+	RuleDoubleBarrelled Rule = "double-barrelled"
+	RuleClosed          Rule = "closed"
+	RuleJargon          Rule = "jargon"
+)
+
+// weights are the number of points a question loses for each rule it breaks.
+var weights = map[Rule]int{
+	RuleLeading:         40,
+	RuleDoubleBarrelled: 30,
+	RuleClosed:          20,
+	RuleJargon:          10,
+}
+
+// DefaultJargon is the list of terms that are always flagged as jargon.
+var DefaultJargon = []string{
+	"synergy",
+	"leverage",
+	"paradigm",
+	"value proposition",
+	"north star",
+	"product-market fit",
+	"stakeholder alignment",
+	"kpi",
+	"okr",
+	"roi",
+	"mvp",
+	"nps",
+	"tam",
+	"jtbd",
+	"jobs to be done",
+}
+
+var (
+	leadingPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(don't|doesn't|wouldn't|isn't|aren't|won't|didn't|can't|shouldn't) you (think|agree|find|feel|say)\b`),
+		regexp.MustCompile(`(?i)\bwould(n't)? you agree\b`),
+		regexp.MustCompile(`(?i)\bdo you agree (that|with)\b`),
+		regexp.MustCompile(`(?i)\b(surely|obviously|clearly|of course)\b`),
+		regexp.MustCompile(`(?i)\bhow (much|great|useful|helpful|easy) (do|would|did) you (love|like|enjoy|find)\b`),
+		regexp.MustCompile(`(?i)\b(isn't it|aren't they|don't you|right)\?\s*$`),
+	}
+	closedPattern        = regexp.MustCompile(`(?i)^(do|does|did|is|are|was|were|have|has|had|can|could|would|will|should|shall|may)(n't)?\b`)
+	politeOpenPattern    = regexp.MustCompile(`(?i)^(can|could|would) you (please )?(tell|describe|walk|explain|share|talk)\b`)
+	doubleBarrelPattern  = regexp.MustCompile(`(?i)\b(and|or)\s+(how|what|why|when|where|who|which|do|does|did|is|are|was|were|would|could|can|should|have|has)\b`)
+	andOrPattern         = regexp.MustCompile(`(?i)\band/or\b`)
+	sentenceSplitPattern = regexp.MustCompile(`[^.!?\n]+[.!?]*`)
+)
+
+// Issue is a single interviewing mistake found in a question.
+type Issue struct {
+	Rule    Rule   `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Result is the outcome of checking a single question.
+type Result struct {
+	Question string  `json:"question"`
+	Score    int     `json:"score"`
+	Issues   []Issue `json:"issues,omitempty"`
+}
+
+// OK reports whether the question had no issues.
+func (r Result) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Linter scores interview questions for common interviewing mistakes.
+type Linter struct {
+	jargon []string
+}
+
+// New creates a new Linter. Any supplied jargon is checked in addition to DefaultJargon.
+func New(jargon ...string) *Linter {
+	terms := make([]string, 0, len(DefaultJargon)+len(jargon))
+	for _, t := range append(append([]string{}, DefaultJargon...), jargon...) {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return &Linter{jargon: terms}
+}
+
+// Check scores a single question. Only the sentences that end in a question mark are considered,
+// so that any preamble the interviewer includes (such as an introduction) is not penalised.
+func (l *Linter) Check(question string) Result {
+	result := Result{Question: question, Score: 100}
+
+	asked := questionSentences(question)
+	if len(asked) == 0 {
+		return result
+	}
+
+	if len(asked) > 1 {
+		result.add(RuleDoubleBarrelled, "asks more than one question at once; split it up")
+	}
+
+	var leading, closed, doubleBarrelled bool
+	for _, s := range asked {
+		for _, p := range leadingPatterns {
+			if p.MatchString(s) {
+				leading = true
+			}
+		}
+		if closedPattern.MatchString(s) && !politeOpenPattern.MatchString(s) {
+			closed = true
+		}
+		if doubleBarrelPattern.MatchString(s) || andOrPattern.MatchString(s) {
+			doubleBarrelled = true
+		}
+	}
+
+	if leading {
+		result.add(RuleLeading, "suggests the answer the interviewer expects; ask neutrally")
+	}
+	if doubleBarrelled && len(asked) == 1 {
+		result.add(RuleDoubleBarrelled, "combines two questions in one; ask about each separately")
+	}
+	if closed {
+		result.add(RuleClosed, "can be answered with yes or no; try starting with how, what or why")
+	}
+
+	lower := strings.ToLower(strings.Join(asked, " "))
+	for _, term := range l.jargon {
+		if containsWord(lower, term) {
+			result.add(RuleJargon, "uses the jargon \""+term+"\"; use the participant's own words")
+		}
+	}
+
+	return result
+}
+
+// CheckTranscript scores every question in a transcript, in order.
+func (l *Linter) CheckTranscript(transcript *domain.Transcript) []Result {
+	results := make([]Result, 0, len(transcript.Entries))
+	for _, entry := range transcript.Entries {
+		results = append(results, l.Check(entry.Question))
+	}
+	return results
+}
+
+// add records an issue and deducts its weight from the score.
+func (r *Result) add(rule Rule, message string) {
+	r.Issues = append(r.Issues, Issue{Rule: rule, Message: message})
+	r.Score -= weights[rule]
+	if r.Score < 0 {
+		r.Score = 0
+	}
+}
+
+// questionSentences returns the trimmed sentences of the text that end in a question mark.
+func questionSentences(text string) []string {
+	var sentences []string
+	for _, s := range sentenceSplitPattern.FindAllString(text, -1) {
+		s = strings.TrimSpace(s)
+		if strings.HasSuffix(s, "?") {
+			sentences = append(sentences, s)
+		}
+	}
+	return sentences
+}
+
+// containsWord reports whether term appears in text on word boundaries.
+func containsWord(text, term string) bool {
+	for i := 0; ; {
+		j := strings.Index(text[i:], term)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(term)
+		if (start == 0 || !isWordByte(text[start-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
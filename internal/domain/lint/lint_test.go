@@ -0,0 +1,133 @@
+package lint
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func rules(r Result) []Rule {
+	var out []Rule
+	for _, i := range r.Issues {
+		out = append(out, i.Rule)
+	}
+	return out
+}
+
+func TestLinter_Check(t *testing.T) {
+	l := New("golden path")
+
+	tests := []struct {
+		name     string
+		question string
+		want     []Rule
+	}{
+		{"open question", "How do you currently deploy your services?", nil},
+		{"polite open question", "Could you walk me through your last incident?", nil},
+		{"preamble is ignored", "Hi! I'm an interviewer. Do not worry. What brings you here today?", nil},
+		{"leading", "Don't you think deployments are too slow?", []Rule{RuleLeading, RuleClosed}},
+		{"leading tag", "Deploys take too long, right?", []Rule{RuleLeading}},
+		{"double barrelled", "What tools do you use and how do they fit together?", []Rule{RuleDoubleBarrelled}},
+		{"two questions", "What do you use? Why that one?", []Rule{RuleDoubleBarrelled}},
+		{"closed", "Do you use Kubernetes?", []Rule{RuleClosed}},
+		{"jargon", "What is the ROI of your current setup?", []Rule{RuleJargon}},
+		{"custom jargon", "What stops you using the golden path?", []Rule{RuleJargon}},
+		{"jargon is matched on word boundaries", "What is your roadmap like?", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := l.Check(tt.question)
+			assert.Equal(t, tt.want, rules(result))
+			assert.Equal(t, tt.want == nil, result.OK())
+		})
+	}
+}
+
+func TestLinter_CheckTranscript(t *testing.T) {
+	transcript := &domain.Transcript{
+		Entries: []struct {
+			Question string `json:"question"`
+			Answer   string `json:"answer"`
+		}{
+			{Question: "What slows you down?", Answer: "Builds."},
+			{Question: "Surely builds are fast and do you cache them?", Answer: "No."},
+		},
+	}
+
+	results := New().CheckTranscript(transcript)
+	assert.Len(t, results, 2)
+	assert.Equal(t, 100, results[0].Score)
+	assert.Equal(t, 30, results[1].Score)
+}
+
+// fakeProvider is a QuestionProvider that can optionally rephrase questions.
+type fakeProvider struct {
+	questions []string
+	rephrased string
+	err       error
+	calls     int
+	replaced  string
+}
+
+func (f *fakeProvider) NextQuestion(string) (string, bool) {
+	if len(f.questions) == 0 {
+		return "", false
+	}
+	q := f.questions[0]
+	f.questions = f.questions[1:]
+	return q, true
+}
+
+func (f *fakeProvider) Summarize(*domain.Transcript) (string, error) {
+	return "", nil
+}
+
+func (f *fakeProvider) Rephrase(question, guidance string) (string, error) {
+	f.calls++
+	return f.rephrased, f.err
+}
+
+func (f *fakeProvider) ReplaceQuestion(question string) {
+	f.replaced = question
+}
+
+func TestGuard_NextQuestion(t *testing.T) {
+	t.Run("should pass good questions through unchanged", func(t *testing.T) {
+		p := &fakeProvider{questions: []string{"How do you deploy?"}}
+		q, more := NewGuard(p, New()).NextQuestion("")
+		assert.True(t, more)
+		assert.Equal(t, "How do you deploy?", q)
+		assert.Zero(t, p.calls)
+	})
+
+	t.Run("should rephrase bad questions", func(t *testing.T) {
+		p := &fakeProvider{questions: []string{"Do you deploy daily?"}, rephrased: "How often do you deploy?"}
+		q, _ := NewGuard(p, New()).NextQuestion("")
+		assert.Equal(t, "How often do you deploy?", q)
+		assert.Equal(t, 1, p.calls)
+		assert.Equal(t, "How often do you deploy?", p.replaced, "the provider should record the question that was asked")
+	})
+
+	t.Run("should keep the original question if rephrasing fails", func(t *testing.T) {
+		p := &fakeProvider{questions: []string{"Do you deploy daily?"}, err: errors.New("unavailable")}
+		q, _ := NewGuard(p, New()).NextQuestion("")
+		assert.Equal(t, "Do you deploy daily?", q)
+	})
+
+	t.Run("should keep the original question if the rephrasing is worse", func(t *testing.T) {
+		p := &fakeProvider{questions: []string{"Do you deploy daily?"}, rephrased: "Surely you deploy daily, right?"}
+		q, _ := NewGuard(p, New()).NextQuestion("")
+		assert.Equal(t, "Do you deploy daily?", q)
+		assert.Empty(t, p.replaced)
+	})
+
+	t.Run("should keep the original question if the rephrasing is no better", func(t *testing.T) {
+		p := &fakeProvider{questions: []string{"Do you deploy daily?"}, rephrased: "Do you deploy weekly?"}
+		q, _ := NewGuard(p, New()).NextQuestion("")
+		assert.Equal(t, "Do you deploy daily?", q)
+		assert.Empty(t, p.replaced)
+	})
+}
@@ -0,0 +1,156 @@
+package redaction
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Detector finds sensitive information in text.
+type Detector interface {
+	// Kind names the type of information the detector finds, such as "email".
+	Kind() string
+	// Find returns the start and end offsets of each match in the text.
+	Find(text string) [][]int
+}
+
+// Names of the built-in detectors.
+const (
+	KindEmail  = "email"
+	KindPhone  = "phone"
+	KindCard   = "card"
+	KindSecret = "secret"
+)
+
+// regexDetector is a Detector backed by one or more regular expressions, with an optional validation step.
+type regexDetector struct {
+	kind     string
+	patterns []*regexp.Regexp
+	valid    func(match string) bool
+}
+
+// Kind returns the kind of information the detector finds.
+func (d *regexDetector) Kind() string {
+	return d.kind
+}
+
+// Find returns the offsets of all valid matches of the detector's patterns.
+func (d *regexDetector) Find(text string) [][]int {
+	var matches [][]int
+	for _, p := range d.patterns {
+		for _, m := range p.FindAllStringIndex(text, -1) {
+			if d.valid == nil || d.valid(text[m[0]:m[1]]) {
+				matches = append(matches, m)
+			}
+		}
+	}
+	return matches
+}
+
+// Email detects email addresses.
+func Email() Detector {
+	return &regexDetector{
+		kind:     KindEmail,
+		patterns: []*regexp.Regexp{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	}
+}
+
+// Phone detects phone numbers with at least nine digits, which avoids matching most dates and versions.
+func Phone() Detector {
+	return &regexDetector{
+		kind:     KindPhone,
+		patterns: []*regexp.Regexp{regexp.MustCompile(`\+?\(?\d{1,4}\)?[\s.-]?\d{2,4}[\s.-]?\d{3,4}[\s.-]?\d{3,4}`)},
+		valid: func(match string) bool {
+			n := len(digits(match))
+			return n >= 9 && n <= 15
+		},
+	}
+}
+
+// Card detects payment card numbers, confirmed with the Luhn checksum.
+func Card() Detector {
+	return &regexDetector{
+		kind:     KindCard,
+		patterns: []*regexp.Regexp{regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)},
+		valid: func(match string) bool {
+			d := digits(match)
+			return len(d) >= 13 && len(d) <= 19 && luhn(d)
+		},
+	}
+}
+
+// Secret detects API keys, tokens, passwords and private keys.
+func Secret() Detector {
+	return &regexDetector{
+		kind: KindSecret,
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+			regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+			regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+			regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+			regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`),
+			regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),
+			regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`),
+			regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|password|passwd)\s*[:=]\s*\S+`),
+		},
+	}
+}
+
+// dictionaryDetector detects a fixed list of terms, such as customer names.
+type dictionaryDetector struct {
+	kind  string
+	terms []*regexp.Regexp
+}
+
+// Dictionary detects any of the given terms, case-insensitively and on word boundaries.
+func Dictionary(kind string, terms []string) Detector {
+	d := &dictionaryDetector{kind: kind}
+	for _, t := range terms {
+		if t = strings.TrimSpace(t); t != "" {
+			d.terms = append(d.terms, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(t)+`\b`))
+		}
+	}
+	return d
+}
+
+// Kind returns the name of the dictionary.
+func (d *dictionaryDetector) Kind() string {
+	return d.kind
+}
+
+// Find returns the offsets of every occurrence of a dictionary term.
+func (d *dictionaryDetector) Find(text string) [][]int {
+	var matches [][]int
+	for _, t := range d.terms {
+		matches = append(matches, t.FindAllStringIndex(text, -1)...)
+	}
+	return matches
+}
+
+// digits returns only the digits in s.
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// luhn reports whether a string of digits passes the Luhn checksum.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,226 @@
+package redaction
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+var (
+	// ErrUnknownDetector is returned by New for a detector that isn't built in.
+	ErrUnknownDetector = errors.New("unknown detector")
+	// ErrInvalidDictionary is returned by New for a dictionary whose name can't be used in a token.
+	ErrInvalidDictionary = errors.New("dictionary names may only contain letters, digits, '_' and '-'")
+	// ErrNotReversible is returned by Restore without a sealer to decrypt the original values.
+	ErrNotReversible = errors.New("redaction is not reversible")
+	// ErrInvalidToken is returned by Resume for a redaction whose token isn't one a Redactor issues.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// tokenPattern matches the tokens that the Redactor substitutes for sensitive values.
+var tokenPattern = regexp.MustCompile(`\[[A-Z0-9_-]+_\d+\]`)
+
+// dictionaryName matches the names of dictionaries that make tokens tokenPattern matches.
+var dictionaryName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateDictionaryName returns ErrInvalidDictionary if the name can't be used in the dictionary's tokens.
+func ValidateDictionaryName(name string) error {
+	if !dictionaryName.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidDictionary, name)
+	}
+	return nil
+}
+
+// tokenNumber captures the number at the end of a token.
+var tokenNumber = regexp.MustCompile(`_(\d+)\]$`)
+
+// Redactor replaces sensitive information in text with tokens such as "[EMAIL_1]". A Redactor is scoped to
+// a single interview, so the same value is always replaced by the same token within that interview.
+type Redactor struct {
+	detectors []Detector
+	sealer    *Sealer
+	tokens    map[string]string
+	counts    map[string]int
+	records   []domain.Redaction
+}
+
+// New creates a Redactor using the named built-in detectors (all of them if none are named) and a detector
+// for each dictionary. If a sealer is supplied, the original values are kept encrypted so they can be restored.
+func New(detectors []string, dictionaries map[string][]string, sealer *Sealer) (*Redactor, error) {
+	r := &Redactor{
+		sealer: sealer,
+		tokens: make(map[string]string),
+		counts: make(map[string]int),
+	}
+
+	if len(detectors) == 0 {
+		detectors = []string{KindSecret, KindEmail, KindCard, KindPhone}
+	}
+	for _, name := range detectors {
+		switch strings.ToLower(name) {
+		case KindEmail:
+			r.detectors = append(r.detectors, Email())
+		case KindPhone:
+			r.detectors = append(r.detectors, Phone())
+		case KindCard:
+			r.detectors = append(r.detectors, Card())
+		case KindSecret:
+			r.detectors = append(r.detectors, Secret())
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownDetector, name)
+		}
+	}
+
+	// Sort the dictionaries so the detectors are applied in a stable order.
+	names := make([]string, 0, len(dictionaries))
+	for name := range dictionaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ValidateDictionaryName(name); err != nil {
+			return nil, err
+		}
+		r.detectors = append(r.detectors, Dictionary(name, dictionaries[name]))
+	}
+
+	return r, nil
+}
+
+// match is a span of text found by a detector.
+type match struct {
+	start, end int
+	kind       string
+}
+
+// Redact replaces every sensitive value in the text with a token.
+func (r *Redactor) Redact(text string) (string, error) {
+	var matches []match
+	for _, d := range r.detectors {
+		for _, m := range d.Find(text) {
+			matches = append(matches, match{start: m[0], end: m[1], kind: d.Kind()})
+		}
+	}
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	// Prefer the earliest match, and the longest where two start at the same place. Detectors earlier in
+	// the list win ties, as the sort is stable.
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m.start < last {
+			continue
+		}
+		token, err := r.token(m.kind, text[m.start:m.end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text[last:m.start])
+		b.WriteString(token)
+		last = m.end
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), nil
+}
+
+// Redactions returns a record of every token issued so far.
+func (r *Redactor) Redactions() []domain.Redaction {
+	return append([]domain.Redaction(nil), r.records...)
+}
+
+// token returns the token for a value, issuing a new one if the value has not been seen before.
+func (r *Redactor) token(kind, value string) (string, error) {
+	key := kind + "\x00" + value
+	if t, ok := r.tokens[key]; ok {
+		return t, nil
+	}
+
+	// Tokens are upper-cased, so kinds that differ only in case share a count and never issue the same token.
+	prefix := strings.ToUpper(kind)
+	r.counts[prefix]++
+	token := fmt.Sprintf("[%s_%d]", prefix, r.counts[prefix])
+
+	record := domain.Redaction{Token: token, Kind: kind}
+	if r.sealer != nil {
+		sealed, err := r.sealer.Seal(value)
+		if err != nil {
+			return "", fmt.Errorf("could not seal redacted value: %w", err)
+		}
+		record.Sealed = sealed
+	}
+
+	r.tokens[key] = token
+	r.records = append(r.records, record)
+	return token, nil
+}
+
+// Resume restores the tokens issued earlier in an interrupted interview, so new values aren't given the same
+// tokens. If the originals were sealed, values seen before are given their earlier token again.
+func (r *Redactor) Resume(transcript *domain.Transcript) error {
+	for _, rd := range transcript.Redactions {
+		match := tokenNumber.FindStringSubmatch(rd.Token)
+		if match == nil {
+			return fmt.Errorf("%w: %s", ErrInvalidToken, rd.Token)
+		}
+		prefix := strings.ToUpper(rd.Kind)
+		if n, _ := strconv.Atoi(match[1]); n > r.counts[prefix] {
+			r.counts[prefix] = n
+		}
+
+		if r.sealer != nil && rd.Sealed != "" {
+			value, err := r.sealer.Open(rd.Sealed)
+			if err != nil {
+				return fmt.Errorf("could not restore %s: %w", rd.Token, err)
+			}
+			r.tokens[rd.Kind+"\x00"+value] = rd.Token
+		}
+		r.records = append(r.records, rd)
+	}
+	return nil
+}
+
+// Restore replaces the tokens in the text with the original values, using the sealer to decrypt them.
+func Restore(text string, redactions []domain.Redaction, sealer *Sealer) (string, error) {
+	if sealer == nil {
+		return "", ErrNotReversible
+	}
+
+	originals := make(map[string]string, len(redactions))
+	for _, rd := range redactions {
+		if rd.Sealed == "" {
+			continue
+		}
+		v, err := sealer.Open(rd.Sealed)
+		if err != nil {
+			return "", fmt.Errorf("could not restore %s: %w", rd.Token, err)
+		}
+		originals[rd.Token] = v
+	}
+
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if v, ok := originals[token]; ok {
+			return v
+		}
+		return token
+	}), nil
+}
+
+// Ensure Redactor implements the domain interfaces.
+var _ interview.Redactor = (*Redactor)(nil)
+var _ interview.Resumer = (*Redactor)(nil)
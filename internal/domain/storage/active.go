@@ -0,0 +1,14 @@
+package storage
+
+import "github.com/andrewhowdencom/vox/internal/domain"
+
+// ActiveInterviewRepository defines the interface for storing the interviews that are in progress, so they
+// survive a restart.
+type ActiveInterviewRepository interface {
+	// SaveActiveInterview saves the interview, replacing any earlier record with the same ID.
+	SaveActiveInterview(active *domain.ActiveInterview) error
+	ListActiveInterviews() ([]*domain.ActiveInterview, error)
+	// DeleteActiveInterview removes the interview once it has finished. Deleting a missing record is not an
+	// error.
+	DeleteActiveInterview(id string) error
+}
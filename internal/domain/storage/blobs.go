@@ -0,0 +1,9 @@
+package storage
+
+// BlobRepository defines the interface for storing the content of files, such as the attachments sent with
+// answers.
+type BlobRepository interface {
+	// SaveBlob saves the content, and returns the ID it can be retrieved with.
+	SaveBlob(data []byte) (string, error)
+	GetBlob(id string) ([]byte, error)
+}
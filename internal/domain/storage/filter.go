@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+)
+
+// InterviewFilter selects interviews by their metadata. Fields that are not set match every interview.
+type InterviewFilter struct {
+	ProjectID string
+	UserID    string
+	// Since and Until select interviews created in the range [Since, Until).
+	Since time.Time
+	Until time.Time
+	// Attributes must all be present on the interview with the same values.
+	Attributes map[string]string
+}
+
+// Matches reports whether the interview is selected by the filter.
+func (f InterviewFilter) Matches(interview *domain.Interview) bool {
+	if f.ProjectID != "" && interview.ProjectID != f.ProjectID {
+		return false
+	}
+	if f.UserID != "" && interview.UserID != f.UserID {
+		return false
+	}
+	if !f.Since.IsZero() && interview.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !interview.CreatedAt.Before(f.Until) {
+		return false
+	}
+	for k, v := range f.Attributes {
+		if got, ok := interview.Attributes[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterviewFilter_Matches(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	interview := &domain.Interview{
+		UserID:     "ada",
+		ProjectID:  "feedback",
+		CreatedAt:  created,
+		Attributes: map[string]string{"company": "Acme", "plan": "pro"},
+	}
+
+	testCases := []struct {
+		name   string
+		filter InterviewFilter
+		want   bool
+	}{
+		{name: "empty filter", filter: InterviewFilter{}, want: true},
+		{name: "matching project", filter: InterviewFilter{ProjectID: "feedback"}, want: true},
+		{name: "other project", filter: InterviewFilter{ProjectID: "discovery"}, want: false},
+		{name: "other user", filter: InterviewFilter{UserID: "grace"}, want: false},
+		{name: "since is inclusive", filter: InterviewFilter{Since: created}, want: true},
+		{name: "until is exclusive", filter: InterviewFilter{Until: created}, want: false},
+		{name: "within range", filter: InterviewFilter{Since: created.Add(-time.Hour), Until: created.Add(time.Hour)}, want: true},
+		{name: "matching attributes", filter: InterviewFilter{Attributes: map[string]string{"company": "Acme"}}, want: true},
+		{name: "other attribute value", filter: InterviewFilter{Attributes: map[string]string{"company": "Globex"}}, want: false},
+		{name: "missing attribute", filter: InterviewFilter{Attributes: map[string]string{"region": "eu"}}, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.filter.Matches(interview))
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package storage
+
+import "github.com/andrewhowdencom/vox/internal/domain"
+
+// InstallationRepository defines the interface for storing the Slack workspaces vox is installed in.
+type InstallationRepository interface {
+	// SaveInstallation saves the installation, replacing any earlier one for the same workspace.
+	SaveInstallation(installation *domain.Installation) error
+	GetInstallation(teamID string) (*domain.Installation, error)
+	// DeleteInstallation removes the installation once vox is uninstalled. Deleting a missing installation is
+	// not an error.
+	DeleteInstallation(teamID string) error
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+)
+
+// ErrNotFound is returned when a record does not exist.
+var ErrNotFound = errors.New("not found")
+
+// InviteRepository defines the interface for storing and retrieving invites.
+type InviteRepository interface {
+	SaveInvite(invite *domain.Invite) error
+	GetInvite(id string) (*domain.Invite, error)
+	ListInvites() ([]*domain.Invite, error)
+	// UpdateInvite applies the update to the stored invite atomically, and saves it if the update succeeds.
+	UpdateInvite(id string, update func(invite *domain.Invite) error) (*domain.Invite, error)
+}
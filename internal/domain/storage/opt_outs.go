@@ -0,0 +1,12 @@
+package storage
+
+// OptOutRepository defines the interface for storing the phone numbers that have opted out of messages from
+// vox, such as by replying STOP to a text message.
+type OptOutRepository interface {
+	// SaveOptOut records that the number has opted out.
+	SaveOptOut(number string) error
+	IsOptedOut(number string) (bool, error)
+	// DeleteOptOut records that the number has opted back in. Deleting a number that hasn't opted out is not
+	// an error.
+	DeleteOptOut(number string) error
+}
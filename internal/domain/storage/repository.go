@@ -9,5 +9,10 @@ type Repository interface {
 	GetTranscript(interviewID string) (*domain.Transcript, error)
 	GetSummary(interviewID string) (*domain.Summary, error)
 	ListInterviews() ([]*domain.Interview, error)
+	// UpdateInterview applies the update to the stored interview atomically, and saves it if the update
+	// succeeds.
+	UpdateInterview(id string, update func(interview *domain.Interview) error) (*domain.Interview, error)
+	// DeleteInterview removes an interview, along with its transcript and summary.
+	DeleteInterview(id string) error
 	Close() error
 }
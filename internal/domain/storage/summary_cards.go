@@ -0,0 +1,11 @@
+package storage
+
+import "github.com/andrewhowdencom/vox/internal/domain"
+
+// SummaryCardRepository defines the interface for storing the interview summaries posted to Slack.
+type SummaryCardRepository interface {
+	SaveSummaryCard(card *domain.SummaryCard) error
+	// GetSummaryCard returns the card posted in the message with the timestamp, or ErrNotFound if the
+	// message isn't a summary card.
+	GetSummaryCard(channelID, timestamp string) (*domain.SummaryCard, error)
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/storage/bbolt"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain/invite"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// inviteRepository is the part of the repository used to manage invites.
+type inviteRepository interface {
+	storage.InviteRepository
+	Close() error
+}
+
+// NewInviteCmd creates a new cobra command for the "invite" command.
+func NewInviteCmd() *cobra.Command {
+	return newInviteCmd(func() (inviteRepository, error) {
+		return bbolt.NewRepository()
+	})
+}
+
+func newInviteCmd(repoFn func() (inviteRepository, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invite",
+		Short: "Invite participants from outside the organisation",
+		Long: `Create signed links that let participants take an interview in their web browser, served by
+"vox serve".`,
+	}
+
+	cmd.AddCommand(newInviteCreateCmd(repoFn))
+	cmd.AddCommand(newInviteListCmd(repoFn))
+	cmd.AddCommand(newInviteRevokeCmd(repoFn))
+
+	return cmd
+}
+
+func newInviteCreateCmd(repoFn func() (inviteRepository, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an invite link for a topic",
+		Long:  `Create an invite link for a topic, optionally bound to a participant and their attributes.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topicID, _ := cmd.Flags().GetString("topic")
+			participant, _ := cmd.Flags().GetString("participant")
+			attributes, _ := cmd.Flags().GetStringToString("attr")
+			maxUses, _ := cmd.Flags().GetInt("max-uses")
+			expiresFlag, _ := cmd.Flags().GetString("expires")
+
+			expires, err := parseExpiry(expiresFlag)
+			if err != nil {
+				return err
+			}
+			if maxUses < 0 {
+				return fmt.Errorf("--max-uses must not be negative")
+			}
+
+			var cfg config.Config
+			if err := viper.Unmarshal(&cfg); err != nil {
+				return fmt.Errorf("error unmarshalling config: %w", err)
+			}
+
+			var topic *config.Topic
+			for i, t := range cfg.Interviews {
+				if strings.EqualFold(t.ID, topicID) {
+					topic = &cfg.Interviews[i]
+					break
+				}
+			}
+			if topic == nil {
+				return fmt.Errorf("topic '%s' not found", topicID)
+			}
+
+			service, closeFn, err := newInviteService(cfg, repoFn)
+			if err != nil {
+				return err
+			}
+			defer closeFn()
+
+			inv, token, err := service.Create(topic.ID, participant, attributes, expires, maxUses)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Invite %s expires %s.\n", inv.ID, inv.ExpiresAt.Format(time.RFC1123))
+			fmt.Fprintln(out, strings.TrimSuffix(cfg.Invites.BaseURL, "/")+"/invite/"+token)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("topic", "", "The topic of the interview")
+	cmd.Flags().String("expires", "7d", "How long the invite is valid for, such as 7d or 12h")
+	cmd.Flags().Int("max-uses", 1, "The number of interviews that can be started with the invite (0 for no limit)")
+	cmd.Flags().String("participant", "", "Who the invite is for, such as their email address")
+	cmd.Flags().StringToString("attr", nil, "Attributes to record on the interview, such as company=Acme")
+	cmd.MarkFlagRequired("topic")
+
+	return cmd
+}
+
+func newInviteListCmd(repoFn func() (inviteRepository, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all invites",
+		Long:  `List all invites and how far each participant has got.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := repoFn()
+			if err != nil {
+				return fmt.Errorf("could not create repository: %w", err)
+			}
+			defer repo.Close()
+
+			invites, err := repo.ListInvites()
+			if err != nil {
+				return fmt.Errorf("could not list invites: %w", err)
+			}
+
+			if len(invites) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No invites found.")
+				return nil
+			}
+
+			tbl := table.New("ID", "Topic", "Participant", "Status", "Uses", "Expires At")
+			tbl.WithWriter(cmd.OutOrStdout())
+
+			for _, i := range invites {
+				uses := strconv.Itoa(i.Uses)
+				if i.MaxUses > 0 {
+					uses += "/" + strconv.Itoa(i.MaxUses)
+				}
+				tbl.AddRow(i.ID, i.TopicID, i.Participant, i.Status(), uses, i.ExpiresAt.String())
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+}
+
+func newInviteRevokeCmd(repoFn func() (inviteRepository, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke [id]",
+		Short: "Revoke an invite",
+		Long:  `Revoke an invite, so that its link can no longer be used.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := repoFn()
+			if err != nil {
+				return fmt.Errorf("could not create repository: %w", err)
+			}
+			defer repo.Close()
+
+			// Revoking doesn't need to sign anything, so the key is not required.
+			if err := invite.NewService(repo, nil).Revoke(args[0]); err != nil {
+				return fmt.Errorf("could not revoke invite: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Invite %s revoked.\n", args[0])
+			return nil
+		},
+	}
+}
+
+// newInviteService creates an invite service from the configuration, returning a function to close its repository.
+func newInviteService(cfg config.Config, repoFn func() (inviteRepository, error)) (*invite.Service, func() error, error) {
+	if cfg.Invites.Key == "" {
+		return nil, nil, fmt.Errorf("invites.key must be set to create invite links")
+	}
+	signer, err := invite.NewSigner(cfg.Invites.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo, err := repoFn()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create repository: %w", err)
+	}
+	return invite.NewService(repo, signer), repo.Close, nil
+}
+
+// parseExpiry parses a duration, additionally accepting a number of days such as "7d".
+func parseExpiry(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --expires %q: use a positive duration such as 7d or 12h", s)
+	}
+	return d, nil
+}
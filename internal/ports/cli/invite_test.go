@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryInviteRepository is an in-memory inviteRepository for tests.
+type memoryInviteRepository struct {
+	mu      sync.Mutex
+	invites map[string]domain.Invite
+}
+
+func (m *memoryInviteRepository) SaveInvite(inv *domain.Invite) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.invites[inv.ID] = *inv
+	return nil
+}
+
+func (m *memoryInviteRepository) GetInvite(id string) (*domain.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.invites[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &inv, nil
+}
+
+func (m *memoryInviteRepository) ListInvites() ([]*domain.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*domain.Invite
+	for _, inv := range m.invites {
+		out = append(out, &inv)
+	}
+	return out, nil
+}
+
+func (m *memoryInviteRepository) UpdateInvite(id string, update func(*domain.Invite) error) (*domain.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.invites[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	if err := update(&inv); err != nil {
+		return nil, err
+	}
+	m.invites[id] = inv
+	return &inv, nil
+}
+
+func (m *memoryInviteRepository) Close() error { return nil }
+
+func TestInviteCmd(t *testing.T) {
+	viper.Set("interviews", []map[string]any{{"id": "feedback", "provider": "static"}})
+	viper.Set("invites.key", base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	viper.Set("invites.base_url", "https://vox.example.com/")
+	defer func() {
+		viper.Set("interviews", nil)
+		viper.Set("invites.key", "")
+		viper.Set("invites.base_url", "")
+	}()
+
+	repo := &memoryInviteRepository{invites: make(map[string]domain.Invite)}
+	run := func(args ...string) (string, error) {
+		cmd := newInviteCmd(func() (inviteRepository, error) { return repo, nil })
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("create", "--topic", "feedback", "--expires", "7d", "--participant", "ada@example.com", "--attr", "company=Acme")
+	require.NoError(t, err)
+	assert.Contains(t, out, "https://vox.example.com/invite/")
+
+	require.Len(t, repo.invites, 1)
+	var inv domain.Invite
+	for _, v := range repo.invites {
+		inv = v
+	}
+	assert.Equal(t, "feedback", inv.TopicID)
+	assert.Equal(t, "ada@example.com", inv.Participant)
+	assert.Equal(t, map[string]string{"company": "Acme"}, inv.Attributes)
+	assert.Equal(t, 1, inv.MaxUses)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), inv.ExpiresAt, time.Minute)
+
+	out, err = run("list")
+	require.NoError(t, err)
+	assert.Contains(t, out, inv.ID)
+	assert.Contains(t, out, "sent")
+	assert.Contains(t, out, "0/1")
+
+	_, err = run("revoke", inv.ID)
+	require.NoError(t, err)
+	revoked, err := repo.GetInvite(inv.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InviteRevoked, revoked.Status())
+
+	_, err = run("create", "--topic", "missing")
+	assert.ErrorContains(t, err, "topic 'missing' not found")
+}
+
+func TestParseExpiry(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+	} {
+		got, err := parseExpiry(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"", "0d", "-1h", "soon"} {
+		_, err := parseExpiry(input)
+		assert.Error(t, err, input)
+	}
+}
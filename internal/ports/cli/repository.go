@@ -13,6 +13,7 @@ func NewRepositoryCmd() *cobra.Command {
 	cmd.AddCommand(NewRepositoryListCmd())
 	cmd.AddCommand(NewRepositoryViewCmd())
 	cmd.AddCommand(NewRepositoryExportCmd())
+	cmd.AddCommand(NewRepositoryLintCmd())
 
 	return cmd
 }
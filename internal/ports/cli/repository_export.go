@@ -1,14 +1,16 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
-	"strings"
+	"os"
+	"path/filepath"
 
 	"github.com/andrewhowdencom/vox/internal/domain"
 	"github.com/andrewhowdencom/vox/internal/adapters/storage/bbolt"
+	"github.com/andrewhowdencom/vox/internal/domain/redaction"
 	"github.com/andrewhowdencom/vox/internal/domain/storage"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // NewRepositoryExportCmd creates a new cobra command for the "repository export" command.
@@ -27,6 +29,8 @@ func newRepositoryExportCmd(repoFn func() (storage.Repository, error)) *cobra.Co
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id := args[0]
 			format, _ := cmd.Flags().GetString("format")
+			restore, _ := cmd.Flags().GetBool("restore")
+			attachmentsDir, _ := cmd.Flags().GetString("attachments")
 
 			repo, err := repoFn()
 			if err != nil {
@@ -47,41 +51,79 @@ func newRepositoryExportCmd(repoFn func() (storage.Repository, error)) *cobra.Co
 				return fmt.Errorf("could not get summary: %w", err)
 			}
 
-			output := struct {
-				*domain.Interview
-				*domain.Transcript
-				*domain.Summary
-			}{
-				interview,
-				transcript,
-				summary,
+			if restore {
+				if err := restoreRedactions(transcript, summary, viper.GetString("redaction.key")); err != nil {
+					return fmt.Errorf("could not restore redacted values: %w", err)
+				}
 			}
 
-			switch strings.ToLower(format) {
-			case "json":
-				b, err := json.MarshalIndent(output, "", "  ")
-				if err != nil {
-					return fmt.Errorf("could not marshal interview: %w", err)
-				}
-				fmt.Fprintln(cmd.OutOrStdout(), string(b))
-			case "text":
-				fmt.Fprintf(cmd.OutOrStdout(), "Interview ID: %s\n", interview.ID)
-				fmt.Fprintf(cmd.OutOrStdout(), "User: %s\n", interview.UserID)
-				fmt.Fprintf(cmd.OutOrStdout(), "Project: %s\n", interview.ProjectID)
-				fmt.Fprintf(cmd.OutOrStdout(), "Created At: %s\n\n", interview.CreatedAt.String())
-				fmt.Fprintln(cmd.OutOrStdout(), "--- Transcript ---")
-				for _, entry := range transcript.Entries {
-					fmt.Fprintf(cmd.OutOrStdout(), "Q: %s\nA: %s\n\n", entry.Question, entry.Answer)
+			if attachmentsDir != "" {
+				if err := saveAttachments(repo, transcript, attachmentsDir); err != nil {
+					return err
 				}
-				fmt.Fprintln(cmd.OutOrStdout(), "--- Summary ---")
-				fmt.Fprintln(cmd.OutOrStdout(), summary.Text)
-			default:
-				return fmt.Errorf("unknown format: %s", format)
 			}
 
+			output, err := domain.ExportInterview(interview, transcript, summary, format)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), output)
+
 			return nil
 		},
 	}
 	cmd.Flags().String("format", "json", "The format to export the interview in (json, text)")
+	cmd.Flags().Bool("restore", false, "Restore redacted values using the configured redaction key")
+	cmd.Flags().String("attachments", "", "Save the files sent with the answers to this directory")
 	return cmd
 }
+
+// saveAttachments writes the files sent with the answers to the directory, named after the answer they were sent
+// with.
+func saveAttachments(repo storage.Repository, transcript *domain.Transcript, dir string) error {
+	blobs, ok := repo.(storage.BlobRepository)
+	if !ok {
+		return fmt.Errorf("the repository doesn't store attachments")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("could not create attachments directory: %w", err)
+	}
+	for _, attachment := range transcript.Attachments {
+		if attachment.BlobID == "" {
+			continue
+		}
+		data, err := blobs.GetBlob(attachment.BlobID)
+		if err != nil {
+			return fmt.Errorf("could not get attachment '%s': %w", attachment.Name, err)
+		}
+		name := fmt.Sprintf("%d-%s", attachment.Entry+1, filepath.Base(attachment.Name))
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return fmt.Errorf("could not save attachment '%s': %w", attachment.Name, err)
+		}
+	}
+	return nil
+}
+
+// restoreRedactions replaces the redaction tokens in the transcript and summary with the original values.
+func restoreRedactions(transcript *domain.Transcript, summary *domain.Summary, key string) error {
+	if key == "" {
+		return fmt.Errorf("%w: redaction.key is not configured", redaction.ErrNotReversible)
+	}
+	sealer, err := redaction.NewSealer(key)
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range transcript.Entries {
+		if transcript.Entries[i].Answer, err = redaction.Restore(entry.Answer, transcript.Redactions, sealer); err != nil {
+			return err
+		}
+	}
+	if summary.Text, err = redaction.Restore(summary.Text, transcript.Redactions, sealer); err != nil {
+		return err
+	}
+	// The originals are now in the clear, so the sealed copies are no longer needed.
+	transcript.Redactions = nil
+
+	return nil
+}
@@ -2,13 +2,19 @@ package cli
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/redaction"
 	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRepositoryExportCmd(t *testing.T) {
@@ -32,6 +38,7 @@ func TestRepositoryExportCmd(t *testing.T) {
 		}{
 			{Question: "Q1", Answer: "A1"},
 		},
+		Attachments: []domain.Attachment{{Entry: 0, Name: "dashboard.png", MimeType: "image/png", Size: 4, BlobID: "blob-1"}},
 	}
 
 	// Set up the expected response from the mock repository
@@ -55,18 +62,20 @@ func TestRepositoryExportCmd(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Assert that the output contains the expected JSON
-	type output struct {
-		domain.Interview
-		domain.Transcript
-		domain.Summary
+	var out struct {
+		ID          string              `json:"id"`
+		UserID      string              `json:"user_id"`
+		ProjectID   string              `json:"project_id"`
+		Text        string              `json:"text"`
+		Attachments []domain.Attachment `json:"attachments"`
 	}
-	var out output
 	err = json.Unmarshal(b.Bytes(), &out)
 	assert.NoError(t, err)
-	assert.Equal(t, "1", out.Interview.ID)
-	assert.Equal(t, "user1", out.Interview.UserID)
-	assert.Equal(t, "project1", out.Interview.ProjectID)
-	assert.Equal(t, "This is a summary.", out.Summary.Text)
+	assert.Equal(t, "1", out.ID)
+	assert.Equal(t, "user1", out.UserID)
+	assert.Equal(t, "project1", out.ProjectID)
+	assert.Equal(t, "This is a summary.", out.Text)
+	assert.Equal(t, transcript.Attachments, out.Attachments)
 
 	// Execute the command with the --format=text flag
 	b.Reset()
@@ -84,6 +93,88 @@ func TestRepositoryExportCmd(t *testing.T) {
 	assert.Contains(t, outputStr, "--- Transcript ---")
 	assert.Contains(t, outputStr, "Q: Q1")
 	assert.Contains(t, outputStr, "A: A1")
+	assert.Contains(t, outputStr, "Attached: dashboard.png (image/png, 4 bytes)")
 	assert.Contains(t, outputStr, "--- Summary ---")
 	assert.Contains(t, outputStr, "This is a summary.")
 }
+
+func TestRepositoryExportCmd_Restore(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	sealer, err := redaction.NewSealer(key)
+	assert.NoError(t, err)
+	sealed, err := sealer.Seal("jane@example.com")
+	assert.NoError(t, err)
+
+	// Create a mock repository
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetInterview", "1").Return(&domain.Interview{ID: "1"}, nil)
+	mockRepo.On("GetSummary", "1").Return(&domain.Summary{Text: "Contact is [EMAIL_1]."}, nil)
+	mockRepo.On("GetTranscript", "1").Return(&domain.Transcript{
+		Entries: []struct {
+			Question string `json:"question"`
+			Answer   string `json:"answer"`
+		}{
+			{Question: "Q1", Answer: "Mail [EMAIL_1]"},
+		},
+		Redactions: []domain.Redaction{{Token: "[EMAIL_1]", Kind: "email", Sealed: sealed}},
+	}, nil)
+	mockRepo.On("Close").Return(nil)
+
+	cmd := newRepositoryExportCmd(func() (storage.Repository, error) {
+		return mockRepo, nil
+	})
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+
+	t.Run("should fail without a key", func(t *testing.T) {
+		cmd.SetArgs([]string{"1", "--format=text", "--restore"})
+		assert.Error(t, cmd.Execute())
+	})
+
+	t.Run("should restore the original values with the key", func(t *testing.T) {
+		viper.Set("redaction.key", key)
+		defer viper.Set("redaction.key", "")
+
+		b.Reset()
+		cmd.SetArgs([]string{"1", "--format=text", "--restore"})
+		assert.NoError(t, cmd.Execute())
+		assert.Contains(t, b.String(), "A: Mail jane@example.com")
+		assert.Contains(t, b.String(), "Contact is jane@example.com.")
+	})
+}
+
+func (m *MockRepository) SaveBlob(data []byte) (string, error) {
+	args := m.Called(data)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) GetBlob(id string) ([]byte, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func TestRepositoryExportCmd_Attachments(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetInterview", "1").Return(&domain.Interview{ID: "1"}, nil)
+	mockRepo.On("GetSummary", "1").Return(&domain.Summary{}, nil)
+	mockRepo.On("GetTranscript", "1").Return(&domain.Transcript{
+		Attachments: []domain.Attachment{{Entry: 1, Name: "dashboard.png", MimeType: "image/png", Size: 4, BlobID: "blob-1"}},
+	}, nil)
+	mockRepo.On("GetBlob", "blob-1").Return([]byte("\x89PNG"), nil)
+	mockRepo.On("Close").Return(nil)
+
+	cmd := newRepositoryExportCmd(func() (storage.Repository, error) {
+		return mockRepo, nil
+	})
+	dir := filepath.Join(t.TempDir(), "attachments")
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"1", "--attachments", dir})
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(filepath.Join(dir, "2-dashboard.png"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x89PNG"), data)
+}
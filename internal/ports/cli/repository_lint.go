@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/storage/bbolt"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain/lint"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewRepositoryLintCmd creates a new cobra command for the "repository lint" command.
+func NewRepositoryLintCmd() *cobra.Command {
+	return newRepositoryLintCmd(func() (storage.Repository, error) {
+		return bbolt.NewRepository()
+	})
+}
+
+func newRepositoryLintCmd(repoFn func() (storage.Repository, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [id]",
+		Short: "Check the interviewer's questions for common mistakes",
+		Long: `Check each question in an interview for common interviewing mistakes: leading phrasing,
+double-barrelled questions, closed questions and jargon.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			format, _ := cmd.Flags().GetString("format")
+
+			var cfg config.Config
+			if err := viper.Unmarshal(&cfg); err != nil {
+				return fmt.Errorf("error unmarshalling config: %w", err)
+			}
+
+			repo, err := repoFn()
+			if err != nil {
+				return fmt.Errorf("could not create repository: %w", err)
+			}
+			defer repo.Close()
+
+			interview, err := repo.GetInterview(id)
+			if err != nil {
+				return fmt.Errorf("could not get interview: %w", err)
+			}
+			transcript, err := repo.GetTranscript(id)
+			if err != nil {
+				return fmt.Errorf("could not get transcript: %w", err)
+			}
+
+			// Use any additional jargon configured for the interview's topic.
+			var jargon []string
+			for _, t := range cfg.Interviews {
+				if strings.EqualFold(t.ID, interview.ProjectID) {
+					jargon = t.Lint.Jargon
+					break
+				}
+			}
+			results := lint.New(jargon...).CheckTranscript(transcript)
+
+			switch strings.ToLower(format) {
+			case "json":
+				b, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return fmt.Errorf("could not marshal results: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			case "text":
+				flagged := 0
+				tbl := table.New("#", "Score", "Issues", "Question")
+				tbl.WithWriter(cmd.OutOrStdout())
+				for i, r := range results {
+					var issues []string
+					for _, issue := range r.Issues {
+						issues = append(issues, string(issue.Rule))
+					}
+					if !r.OK() {
+						flagged++
+					}
+					tbl.AddRow(i+1, r.Score, strings.Join(issues, ", "), r.Question)
+				}
+				tbl.Print()
+				fmt.Fprintf(cmd.OutOrStdout(), "\n%d of %d questions flagged.\n", flagged, len(results))
+			default:
+				return fmt.Errorf("unknown format: %s", format)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().String("format", "text", "The format to output the results in (json, text)")
+	return cmd
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/lint"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryLintCmd(t *testing.T) {
+	// Create a mock repository
+	mockRepo := new(MockRepository)
+
+	// Create a sample interview
+	interview := &domain.Interview{
+		ID:        "1",
+		UserID:    "user1",
+		ProjectID: "project1",
+		CreatedAt: time.Now(),
+	}
+	transcript := &domain.Transcript{
+		Entries: []struct {
+			Question string `json:"question"`
+			Answer   string `json:"answer"`
+		}{
+			{Question: "How do you deploy today?", Answer: "With a script."},
+			{Question: "Don't you think that script is slow?", Answer: "Yes."},
+		},
+	}
+
+	// Set up the expected response from the mock repository
+	mockRepo.On("GetInterview", "1").Return(interview, nil)
+	mockRepo.On("GetTranscript", "1").Return(transcript, nil)
+	mockRepo.On("Close").Return(nil)
+
+	// Create the lint command with the mock repository
+	cmd := newRepositoryLintCmd(func() (storage.Repository, error) {
+		return mockRepo, nil
+	})
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"1"})
+
+	// Execute the command
+	err := cmd.Execute()
+
+	// Assert that the command executed successfully
+	assert.NoError(t, err)
+
+	// Assert that the output contains the expected table
+	output := b.String()
+	assert.Contains(t, output, "Score")
+	assert.Contains(t, output, "leading, closed")
+	assert.Contains(t, output, "1 of 2 questions flagged.")
+
+	// Execute the command with the --format=json flag
+	b.Reset()
+	cmd.SetArgs([]string{"1", "--format=json"})
+	err = cmd.Execute()
+
+	// Assert that the command executed successfully
+	assert.NoError(t, err)
+
+	var results []lint.Result
+	err = json.Unmarshal(b.Bytes(), &results)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].OK())
+	assert.Equal(t, lint.RuleLeading, results[1].Issues[0].Rule)
+}
@@ -40,6 +40,16 @@ func (m *MockRepository) ListInterviews() ([]*domain.Interview, error) {
 	return args.Get(0).([]*domain.Interview), args.Error(1)
 }
 
+func (m *MockRepository) UpdateInterview(id string, update func(interview *domain.Interview) error) (*domain.Interview, error) {
+	args := m.Called(id, update)
+	return args.Get(0).(*domain.Interview), args.Error(1)
+}
+
+func (m *MockRepository) DeleteInterview(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockRepository) Close() error {
 	args := m.Called()
 	return args.Error(0)
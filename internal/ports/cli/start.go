@@ -7,7 +7,11 @@ import (
 
 	"github.com/andrewhowdencom/vox/internal/config"
 	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/andrewhowdencom/vox/internal/domain/lint"
+	"github.com/andrewhowdencom/vox/internal/domain/redaction"
+	"github.com/andrewhowdencom/vox/internal/adapters/providers/fallback"
 	"github.com/andrewhowdencom/vox/internal/adapters/providers/gemini"
+	"github.com/andrewhowdencom/vox/internal/adapters/providers/openai"
 	"github.com/andrewhowdencom/vox/internal/adapters/providers/static"
 	"github.com/andrewhowdencom/vox/internal/adapters/storage/bbolt"
 	"github.com/andrewhowdencom/vox/internal/adapters/ui/terminal"
@@ -71,6 +75,9 @@ func runStart(cmd *cobra.Command, out io.Writer, cfg *config.Config, topicID, mo
 	if err != nil {
 		return err
 	}
+	if selectedTopic.Lint.Guard {
+		questionProvider = lint.NewGuard(questionProvider, lint.New(selectedTopic.Lint.Jargon...))
+	}
 
 	ui := terminal.New()
 
@@ -80,7 +87,19 @@ func runStart(cmd *cobra.Command, out io.Writer, cfg *config.Config, topicID, mo
 	}
 	defer repo.Close()
 
-	interviewToRun := interview.NewInterview(questionProvider, ui, repo)
+	var opts []interview.Option
+	if cfg.Redaction.Enabled {
+		redactor, err := newRedactor(cfg)
+		if err != nil {
+			return fmt.Errorf("could not create redactor: %w", err)
+		}
+		opts = append(opts, interview.WithRedactor(redactor))
+		if cfg.Redaction.Attachments {
+			opts = append(opts, interview.WithUnredactedAttachments())
+		}
+	}
+
+	interviewToRun := interview.NewInterview(questionProvider, ui, repo, opts...)
 	err = interviewToRun.Run(user, topicID)
 	if err != nil {
 		return err
@@ -89,9 +108,28 @@ func runStart(cmd *cobra.Command, out io.Writer, cfg *config.Config, topicID, mo
 	return nil
 }
 
-// newQuestionProvider creates a QuestionProvider based on the selected topic.
+// newQuestionProvider creates a QuestionProvider based on the selected topic. If the topic lists fallback
+// providers, they are chained behind the topic's provider.
 func newQuestionProvider(cmd *cobra.Command, cfg *config.Config, topic *config.Topic, model string) (interview.QuestionProvider, error) {
-	switch strings.ToLower(topic.Provider) {
+	p, err := newProvider(cmd, cfg, topic, topic.Provider, model)
+	if err != nil || len(topic.Fallback) == 0 {
+		return p, err
+	}
+
+	members := []fallback.Member{{Name: strings.ToLower(topic.Provider), Provider: p}}
+	for _, name := range topic.Fallback {
+		p, err := newProvider(cmd, cfg, topic, name, model)
+		if err != nil {
+			return nil, fmt.Errorf("could not create fallback provider '%s': %w", name, err)
+		}
+		members = append(members, fallback.Member{Name: strings.ToLower(name), Provider: p})
+	}
+	return fallback.New(fallback.NewHealth(fallback.DefaultCooldown, fallback.DefaultMaxCooldown), members...), nil
+}
+
+// newProvider creates the named QuestionProvider for the selected topic.
+func newProvider(cmd *cobra.Command, cfg *config.Config, topic *config.Topic, name, model string) (interview.QuestionProvider, error) {
+	switch strings.ToLower(name) {
 	case "static":
 		return static.New(topic.Questions), nil
 	case "gemini":
@@ -107,14 +145,30 @@ func newQuestionProvider(cmd *cobra.Command, cfg *config.Config, topic *config.T
 		finalPrompt := buildGeminiPrompt(cfg, topic.Prompt)
 		// We need to cast the concrete type to the interface type.
 		// Since gemini.New returns (interview.QuestionProvider, error), we can do this.
-		p, err := gemini.New(cfg, gemini.Model(model), gemini.APIKey(apiKey), gemini.Prompt(finalPrompt))
+		p, err := gemini.New(cfg, gemini.Model(model), gemini.APIKey(apiKey), gemini.Prompt(finalPrompt), gemini.WithHistory(topic.History.Strategy, topic.History.Turns), gemini.WithGeneration(cfg.Providers.Gemini.Generation.Merge(topic.Generation)))
 		if err != nil {
 			return nil, err
 		}
 		return p, nil
+	case "openai":
+		finalPrompt := buildGeminiPrompt(cfg, topic.Prompt)
+		return openai.New(cfg, openai.BaseURL(cfg.Providers.OpenAI.BaseURL), openai.Model(cfg.Providers.OpenAI.Model), openai.APIKey(cfg.Providers.OpenAI.APIKey), openai.Prompt(finalPrompt), topic.Generation)
 	default:
-		return nil, fmt.Errorf("unknown provider '%s'", topic.Provider)
+		return nil, fmt.Errorf("unknown provider '%s'", name)
+	}
+}
+
+// newRedactor creates a Redactor for a single interview from the redaction configuration.
+func newRedactor(cfg *config.Config) (*redaction.Redactor, error) {
+	var sealer *redaction.Sealer
+	if cfg.Redaction.Key != "" {
+		var err error
+		sealer, err = redaction.NewSealer(cfg.Redaction.Key)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return redaction.New(cfg.Redaction.Detectors, cfg.Redaction.Dictionaries, sealer)
 }
 
 // buildGeminiPrompt constructs the final prompt for the Gemini provider.
@@ -23,25 +23,9 @@ func TestBuildGeminiPrompt(t *testing.T) {
 
 	t.Run("should use a custom system prompt when one is configured", func(t *testing.T) {
 		cfg := &config.Config{
-			Providers: struct {
-				Gemini struct {
-					APIKey      string `mapstructure:"api_key"`
-					Model       string
-					Interviewer struct {
-						Prompt string
-					}
-				}
-			}{
-				Gemini: struct {
-					APIKey      string `mapstructure:"api_key"`
-					Model       string
-					Interviewer struct {
-						Prompt string
-					}
-				}{
-					Interviewer: struct {
-						Prompt string
-					}{
+			Providers: config.Providers{
+				Gemini: config.Gemini{
+					Interviewer: config.Interviewer{
 						Prompt: "Custom system prompt",
 					},
 				},
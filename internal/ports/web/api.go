@@ -0,0 +1,255 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/session"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/google/uuid"
+)
+
+// Scopes that can be granted to an API token.
+const (
+	scopeSessionsWrite  = "sessions:write"
+	scopeInterviewsRead = "interviews:read"
+)
+
+// maxQuestionWait is the longest a client can wait for the next question in a single request.
+const maxQuestionWait = 60 * time.Second
+
+//go:embed static/openapi.yaml
+var openAPIDocument []byte
+
+// apiHandlerFunc handles an API request from an authenticated client.
+type apiHandlerFunc func(w http.ResponseWriter, r *http.Request, client config.APIToken)
+
+// apiQuestion is a question in an API response.
+type apiQuestion struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// apiSession is a session in an API response.
+type apiSession struct {
+	ID       string         `json:"id"`
+	Topic    string         `json:"topic"`
+	Status   session.Status `json:"status"`
+	Question *apiQuestion   `json:"question,omitempty"`
+	Summary  string         `json:"summary,omitempty"`
+}
+
+// registerAPIRoutes adds the handlers for the versioned JSON API.
+func (s *Server) registerAPIRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(openAPIDocument)
+	})
+	mux.HandleFunc("POST /api/v1/sessions", s.authenticate(scopeSessionsWrite, s.createAPISession))
+	mux.HandleFunc("GET /api/v1/sessions/{id}", s.authenticate(scopeSessionsWrite, s.getAPISession))
+	mux.HandleFunc("DELETE /api/v1/sessions/{id}", s.authenticate(scopeSessionsWrite, s.endAPISession))
+	mux.HandleFunc("GET /api/v1/sessions/{id}/question", s.authenticate(scopeSessionsWrite, s.getAPIQuestion))
+	mux.HandleFunc("POST /api/v1/sessions/{id}/answers", s.authenticate(scopeSessionsWrite, s.answerAPIQuestion))
+	mux.HandleFunc("GET /api/v1/sessions/{id}/summary", s.authenticate(scopeSessionsWrite, s.getAPISummary))
+	s.registerRepositoryRoutes(mux)
+}
+
+// authenticate only calls the handler for requests with a bearer token that has been granted the scope.
+func (s *Server) authenticate(scope string, next apiHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || presented == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="vox"`)
+			writeAPIError(w, http.StatusUnauthorized, "a bearer token is required")
+			return
+		}
+
+		for _, token := range s.config.API.Tokens {
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(token.Token)) != 1 {
+				continue
+			}
+			if !slices.Contains(token.Scopes, scope) {
+				writeAPIError(w, http.StatusForbidden, "the token does not have the "+scope+" scope")
+				return
+			}
+			next(w, r, token)
+			return
+		}
+
+		w.Header().Set("WWW-Authenticate", `Bearer realm="vox", error="invalid_token"`)
+		writeAPIError(w, http.StatusUnauthorized, "the token is not valid")
+	}
+}
+
+// createAPISession starts a new interview for a topic.
+func (s *Server) createAPISession(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	var body struct {
+		Topic      string            `json:"topic"`
+		User       string            `json:"user"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnswerSize)).Decode(&body); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "the request body must be a JSON object")
+		return
+	}
+
+	topic := findTopic(s.config, body.Topic)
+	if topic == nil {
+		writeAPIError(w, http.StatusNotFound, "topic '"+body.Topic+"' not found")
+		return
+	}
+
+	userID := body.User
+	if userID == "" {
+		userID = "api:" + client.Name
+	}
+	attributes := maps.Clone(body.Attributes)
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	attributes["api_client"] = client.Name
+
+	ui, err := s.startSession(topic, uuid.NewString(), client.Name, userID, nil, interview.WithAttributes(attributes))
+	if err != nil {
+		slog.Error("Error starting API interview", "error", err, "topic_id", topic.ID, "client", client.Name)
+		writeAPIError(w, http.StatusInternalServerError, "could not start the interview")
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/sessions/"+ui.ID)
+	writeJSON(w, http.StatusCreated, newAPISession(ui))
+}
+
+// getAPISession reports how far a session has got.
+func (s *Server) getAPISession(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	ui := s.session(r.PathValue("id"), client.Name)
+	if ui == nil {
+		writeAPIError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPISession(ui))
+}
+
+// endAPISession finishes the interview early. It is summarised with the answers given so far.
+func (s *Server) endAPISession(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	ui := s.session(r.PathValue("id"), client.Name)
+	if ui == nil {
+		writeAPIError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	ui.Stop()
+	writeJSON(w, http.StatusAccepted, newAPISession(ui))
+}
+
+// getAPIQuestion returns the question waiting for an answer. With the wait parameter, in seconds, the
+// request waits for the next question to be ready.
+func (s *Server) getAPIQuestion(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	ui := s.session(r.PathValue("id"), client.Name)
+	if ui == nil {
+		writeAPIError(w, http.StatusNotFound, "session not found")
+		return
+	}
+
+	if seconds, err := strconv.Atoi(r.URL.Query().Get("wait")); err == nil && seconds > 0 {
+		wait := min(time.Duration(seconds)*time.Second, maxQuestionWait)
+		ctx, cancel := context.WithTimeout(r.Context(), wait)
+		defer cancel()
+		for ui.Status() == session.StatusPending {
+			if _, err := ui.Wait(ctx, len(ui.Events(0))); err != nil {
+				break
+			}
+		}
+	}
+
+	switch ui.Status() {
+	case session.StatusWaiting:
+		current, _ := ui.Current()
+		writeJSON(w, http.StatusOK, apiQuestion{Number: current.Number, Text: current.Question})
+	case session.StatusPending:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeAPIError(w, http.StatusGone, "the session has ended")
+	}
+}
+
+// answerAPIQuestion submits the answer to the current question.
+func (s *Server) answerAPIQuestion(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	ui := s.session(r.PathValue("id"), client.Name)
+	if ui == nil {
+		writeAPIError(w, http.StatusNotFound, "session not found")
+		return
+	}
+
+	var body struct {
+		Answer string `json:"answer"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnswerSize)).Decode(&body); err != nil || body.Answer == "" {
+		writeAPIError(w, http.StatusBadRequest, "an answer is required")
+		return
+	}
+
+	switch err := ui.Answer(body.Answer); {
+	case errors.Is(err, session.ErrNotWaiting):
+		writeAPIError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, session.ErrClosed):
+		writeAPIError(w, http.StatusGone, err.Error())
+	case err != nil:
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, newAPISession(ui))
+	}
+}
+
+// getAPISummary returns the summary of a completed interview.
+func (s *Server) getAPISummary(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	ui := s.session(r.PathValue("id"), client.Name)
+	if ui == nil {
+		writeAPIError(w, http.StatusNotFound, "session not found")
+		return
+	}
+
+	summary, ok := ui.Summary()
+	switch {
+	case ok:
+		writeJSON(w, http.StatusOK, map[string]string{"summary": summary})
+	case ui.Status() == session.StatusFailed:
+		writeAPIError(w, http.StatusGone, errInterviewFailed.Error())
+	default:
+		writeAPIError(w, http.StatusConflict, "the interview is still in progress")
+	}
+}
+
+// newAPISession describes a session for an API response.
+func newAPISession(ui *remoteSession) apiSession {
+	resp := apiSession{ID: ui.ID, Topic: ui.topicID, Status: ui.Status()}
+	if current, ok := ui.Current(); ok {
+		resp.Question = &apiQuestion{Number: current.Number, Text: current.Question}
+	}
+	resp.Summary, _ = ui.Summary()
+	return resp
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Error writing JSON response", "error", err)
+	}
+}
+
+// writeAPIError writes an error response in the API's format.
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,167 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPIServer creates a test server with the API enabled for two clients that can run sessions, and one
+// that cannot.
+func newTestAPIServer(t *testing.T) (*Server, *memoryRepository, *httptest.Server) {
+	t.Helper()
+	s, repo, _ := newTestServer(t)
+	s.config.API.Tokens = []config.APIToken{
+		{Name: "portal", Token: "portal-token", Scopes: []string{scopeSessionsWrite}},
+		{Name: "survey", Token: "survey-token", Scopes: []string{scopeSessionsWrite}},
+		{Name: "reporting", Token: "reporting-token"},
+	}
+	mux := http.NewServeMux()
+	s.registerAPIRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, repo, server
+}
+
+// apiRequest makes an API request with the token, decoding the JSON response into out if it is set.
+func apiRequest(t *testing.T, method, url, token, body string, out any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp
+}
+
+func TestAPI_Authentication(t *testing.T) {
+	_, _, server := newTestAPIServer(t)
+
+	t.Run("should require a token", func(t *testing.T) {
+		var body map[string]string
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sessions", "", `{"topic":"feedback"}`, &body)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+		assert.NotEmpty(t, body["error"])
+	})
+
+	t.Run("should reject unknown tokens", func(t *testing.T) {
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sessions", "nope", `{"topic":"feedback"}`, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("should reject tokens without the scope", func(t *testing.T) {
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sessions", "reporting-token", `{"topic":"feedback"}`, nil)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("should serve the OpenAPI document without a token", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v1/openapi.yaml")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), "openapi: 3.1.0")
+	})
+}
+
+func TestAPI_Session(t *testing.T) {
+	s, repo, server := newTestAPIServer(t)
+
+	t.Run("should reject unknown topics", func(t *testing.T) {
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sessions", "portal-token", `{"topic":"missing"}`, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should run an interview", func(t *testing.T) {
+		var created apiSession
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sessions", "portal-token",
+			`{"topic":"feedback","user":"ada","attributes":{"plan":"pro"}}`, &created)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "feedback", created.Topic)
+		assert.Equal(t, "/api/v1/sessions/"+created.ID, resp.Header.Get("Location"))
+		base := server.URL + "/api/v1/sessions/" + created.ID
+
+		// Sessions are only visible to the client that started them, and not to browsers.
+		resp = apiRequest(t, http.MethodGet, base, "survey-token", "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Nil(t, s.session(created.ID, ""))
+
+		var question apiQuestion
+		resp = apiRequest(t, http.MethodGet, base+"/question?wait=5", "portal-token", "", &question)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, apiQuestion{Number: 1, Text: "What do you like?"}, question)
+
+		resp = apiRequest(t, http.MethodGet, base+"/summary", "portal-token", "", nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = apiRequest(t, http.MethodPost, base+"/answers", "portal-token", `{"answer":""}`, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp = apiRequest(t, http.MethodPost, base+"/answers", "portal-token", `{"answer":"The speed."}`, nil)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		resp = apiRequest(t, http.MethodGet, base+"/question?wait=5", "portal-token", "", &question)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, question.Number)
+
+		resp = apiRequest(t, http.MethodPost, base+"/answers", "portal-token", `{"answer":"Nothing."}`, nil)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		resp = apiRequest(t, http.MethodGet, base+"/question?wait=5", "portal-token", "", nil)
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+
+		var session apiSession
+		apiRequest(t, http.MethodGet, base, "portal-token", "", &session)
+		assert.Equal(t, "completed", string(session.Status))
+
+		var summary map[string]string
+		resp = apiRequest(t, http.MethodGet, base+"/summary", "portal-token", "", &summary)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, summary, "summary")
+
+		stored, err := repo.GetInterview("interview-1")
+		require.NoError(t, err)
+		assert.Equal(t, "ada", stored.UserID)
+		assert.Equal(t, map[string]string{"plan": "pro", "api_client": "portal"}, stored.Attributes)
+	})
+
+	t.Run("should summarise an interview that is ended early", func(t *testing.T) {
+		var created apiSession
+		apiRequest(t, http.MethodPost, server.URL+"/api/v1/sessions", "portal-token", `{"topic":"feedback"}`, &created)
+		base := server.URL + "/api/v1/sessions/" + created.ID
+
+		resp := apiRequest(t, http.MethodGet, base+"/question?wait=5", "portal-token", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = apiRequest(t, http.MethodPost, base+"/answers", "portal-token", `{"answer":"The speed."}`, nil)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		resp = apiRequest(t, http.MethodDelete, base, "portal-token", "", nil)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		ui := s.session(created.ID, "portal")
+		require.NotNil(t, ui)
+		require.Eventually(t, ui.Ended, time.Second, 10*time.Millisecond)
+
+		resp = apiRequest(t, http.MethodGet, base+"/summary", "portal-token", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		transcript, err := repo.GetTranscript("interview-1")
+		require.NoError(t, err)
+		require.Len(t, transcript.Entries, 1)
+		assert.Equal(t, "The speed.", transcript.Entries[0].Answer)
+	})
+}
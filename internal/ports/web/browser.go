@@ -0,0 +1,250 @@
+package web
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/session"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/google/uuid"
+)
+
+// Settings for interviews conducted in a web browser.
+const (
+	// sessionIdleTimeout is how long a participant has to answer a question before the interview is abandoned.
+	sessionIdleTimeout = 30 * time.Minute
+	// sessionRetention is how long a finished session is kept, so a participant who reconnects can still
+	// see the summary.
+	sessionRetention = 10 * time.Minute
+	// keepAliveInterval is how often a comment is sent on an idle event stream, so proxies don't close it.
+	keepAliveInterval = 25 * time.Second
+	// maxAnswerSize limits the size of a submitted answer.
+	maxAnswerSize = 64 << 10
+)
+
+// errInterviewFailed is shown to the participant in place of the internal error.
+var errInterviewFailed = errors.New("the interview could not be completed")
+
+//go:embed static/interview.html
+var interviewPage string
+
+var interviewTemplate = template.Must(template.New("interview").Parse(interviewPage))
+
+// interviewPageData is rendered by the interview page.
+type interviewPageData struct {
+	Topic *config.Topic
+	// StartURL is where the page posts to start a new session.
+	StartURL string
+}
+
+// remoteSession is an interview conducted through a session that a remote client drives.
+type remoteSession struct {
+	*session.UI
+	topicID string
+	// owner is the name of the API client that started the session, or empty for sessions started in a browser.
+	owner string
+}
+
+// registerBrowserRoutes adds the handlers for interviews conducted in a web browser.
+func (s *Server) registerBrowserRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /interview/{topic}", s.createInterviewPageHandler())
+	mux.HandleFunc("POST /interview/{topic}/sessions", s.createBrowserSessionHandler())
+	mux.HandleFunc("GET /interview/sessions/{id}/events", s.createSessionEventsHandler())
+	mux.HandleFunc("POST /interview/sessions/{id}/answers", s.createSessionAnswerHandler())
+}
+
+// browserTopic returns the topic if it can be taken in a web browser.
+func (s *Server) browserTopic(r *http.Request) *config.Topic {
+	topic := findTopic(s.config, r.PathValue("topic"))
+	if topic == nil || !topic.Browser {
+		return nil
+	}
+	return topic
+}
+
+// createInterviewPageHandler serves the page that participants use to take an interview.
+func (s *Server) createInterviewPageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		topic := s.browserTopic(r)
+		if topic == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		renderInterviewPage(w, interviewPageData{Topic: topic, StartURL: "/interview/" + url.PathEscape(topic.ID) + "/sessions"})
+	}
+}
+
+// renderInterviewPage writes the page that participants use to take an interview.
+func renderInterviewPage(w http.ResponseWriter, data interviewPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := interviewTemplate.Execute(w, data); err != nil {
+		slog.Error("Error rendering interview page", "error", err)
+	}
+}
+
+// createBrowserSessionHandler starts a new interview for the topic.
+func (s *Server) createBrowserSessionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		topic := s.browserTopic(r)
+		if topic == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		id := uuid.NewString()
+		ui, err := s.startSession(topic, id, "", "browser:"+id, nil)
+		if err != nil {
+			slog.Error("Error starting browser interview", "error", err, "topic_id", topic.ID)
+			http.Error(w, "could not start the interview", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"id": ui.ID})
+	}
+}
+
+// startSession runs an interview for the topic in the background, conducted through a session that a remote
+// client drives. If done is set, it is called with the result of the interview once it finishes.
+func (s *Server) startSession(topic *config.Topic, id, owner, userID string, done func(error), opts ...interview.Option) (*remoteSession, error) {
+	ui := &remoteSession{UI: session.New(id, session.WithIdleTimeout(sessionIdleTimeout)), topicID: topic.ID, owner: owner}
+	interviewToRun, err := s.newInterview(topic, ui.UI, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.sessions[id] = ui
+	s.mu.Unlock()
+
+	go func() {
+		slog.Info("Starting session interview", "session_id", id, "topic_id", topic.ID)
+		err := interviewToRun.Run(userID, topic.ID)
+		if err != nil {
+			slog.Error("Error running interview", "error", err, "session_id", id)
+			ui.Fail(errInterviewFailed)
+		} else {
+			ui.Close()
+		}
+		if done != nil {
+			done(err)
+		}
+
+		time.AfterFunc(sessionRetention, func() {
+			s.mu.Lock()
+			delete(s.sessions, id)
+			s.mu.Unlock()
+		})
+	}()
+	return ui, nil
+}
+
+// session returns the session with the given ID, or nil if there is none or it belongs to a different owner.
+func (s *Server) session(id, owner string) *remoteSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ui, ok := s.sessions[id]
+	if !ok || ui.owner != owner {
+		return nil
+	}
+	return ui
+}
+
+// createSessionEventsHandler streams the events of a session as Server-Sent Events. A client that reconnects
+// with the Last-Event-ID header receives only the events it missed.
+func (s *Server) createSessionEventsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ui := s.session(r.PathValue("id"), "")
+		if ui == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		after, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+		if ui.Ended() && len(ui.Events(after)) == 0 {
+			// Tell the browser not to reconnect.
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			ctx, cancel := context.WithTimeout(r.Context(), keepAliveInterval)
+			events, err := ui.Wait(ctx, after)
+			cancel()
+
+			switch {
+			case errors.Is(err, context.DeadlineExceeded) && r.Context().Err() == nil:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			case err != nil:
+				return
+			case len(events) == 0:
+				return
+			}
+
+			for _, event := range events {
+				data, err := json.Marshal(event)
+				if err != nil {
+					slog.Error("Error marshalling session event", "error", err)
+					return
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+				after = event.ID
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// createSessionAnswerHandler submits the participant's answer to the current question.
+func (s *Server) createSessionAnswerHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ui := s.session(r.PathValue("id"), "")
+		if ui == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		var body struct {
+			Answer string `json:"answer"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnswerSize)).Decode(&body); err != nil || body.Answer == "" {
+			http.Error(w, "an answer is required", http.StatusBadRequest)
+			return
+		}
+
+		switch err := ui.Answer(body.Answer); {
+		case errors.Is(err, session.ErrNotWaiting):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, session.ErrClosed):
+			http.Error(w, err.Error(), http.StatusGone)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}
+}
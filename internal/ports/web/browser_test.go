@@ -0,0 +1,248 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/session"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository is an in-memory storage.Repository for tests.
+type memoryRepository struct {
+	mu          sync.Mutex
+	interviews  map[string]*domain.Interview
+	transcripts map[string]*domain.Transcript
+	summaries   map[string]*domain.Summary
+	invites     map[string]domain.Invite
+	actives     map[string]domain.ActiveInterview
+	cards       map[string]domain.SummaryCard
+	teams       map[string]domain.Installation
+	blobs       map[string][]byte
+	optOuts     map[string]bool
+}
+
+// SaveInterview stores the interview as "interview-1", unless it already has an ID.
+func (m *memoryRepository) SaveInterview(interview *domain.Interview, transcript *domain.Transcript, summary *domain.Summary) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.interviews == nil {
+		m.interviews = make(map[string]*domain.Interview)
+		m.transcripts = make(map[string]*domain.Transcript)
+		m.summaries = make(map[string]*domain.Summary)
+	}
+	if interview.ID == "" {
+		interview.ID = "interview-1"
+	}
+	m.interviews[interview.ID] = interview
+	m.transcripts[interview.ID] = transcript
+	m.summaries[interview.ID] = summary
+	return interview.ID, nil
+}
+
+func (m *memoryRepository) GetInterview(id string) (*domain.Interview, error) {
+	return get(m, m.interviews, id)
+}
+
+func (m *memoryRepository) GetTranscript(id string) (*domain.Transcript, error) {
+	return get(m, m.transcripts, id)
+}
+
+func (m *memoryRepository) GetSummary(id string) (*domain.Summary, error) {
+	return get(m, m.summaries, id)
+}
+
+func (m *memoryRepository) ListInterviews() ([]*domain.Interview, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Collect(maps.Values(m.interviews)), nil
+}
+
+func (m *memoryRepository) UpdateInterview(id string, update func(interview *domain.Interview) error) (*domain.Interview, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.interviews[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	interview := *stored
+	if err := update(&interview); err != nil {
+		return nil, err
+	}
+	m.interviews[id] = &interview
+	return &interview, nil
+}
+
+func (m *memoryRepository) DeleteInterview(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.interviews[id]; !ok {
+		return storage.ErrNotFound
+	}
+	// Like the bbolt repository, attachments are deleted unless another transcript still has them.
+	if transcript, ok := m.transcripts[id]; ok {
+		for _, attachment := range transcript.Attachments {
+			shared := false
+			for otherID, other := range m.transcripts {
+				shared = shared || (otherID != id && slices.ContainsFunc(other.Attachments, func(a domain.Attachment) bool { return a.BlobID == attachment.BlobID }))
+			}
+			if !shared {
+				delete(m.blobs, attachment.BlobID)
+			}
+		}
+	}
+	delete(m.interviews, id)
+	delete(m.transcripts, id)
+	delete(m.summaries, id)
+	return nil
+}
+
+func (m *memoryRepository) Close() error { return nil }
+
+func get[T any](m *memoryRepository, records map[string]*T, id string) (*T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := records[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return record, nil
+}
+
+// newTestServer creates a server with a static topic that can be taken in a browser, and one that cannot.
+func newTestServer(t *testing.T) (*Server, *memoryRepository, *httptest.Server) {
+	t.Helper()
+	repo := &memoryRepository{}
+	s := &Server{
+		config: &config.Config{Interviews: []config.Topic{
+			{ID: "feedback", Name: "Product Feedback", Provider: "static", Browser: true, Questions: []string{"What do you like?", "What would you change?"}},
+			{ID: "internal", Provider: "static", Questions: []string{"Secret?"}},
+		}},
+		activeInterviews: make(map[string]chatInterview),
+		sessions:         make(map[string]*remoteSession),
+		mu:               &sync.Mutex{},
+		repo:             repo,
+		progress:         repo,
+		blobs:            repo,
+		events:           newEventLog(eventTTL),
+		eventQueue:       newEventQueue(),
+	}
+	mux := http.NewServeMux()
+	s.registerBrowserRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, repo, server
+}
+
+func TestBrowserInterview(t *testing.T) {
+	_, repo, server := newTestServer(t)
+
+	t.Run("should only serve topics with browser enabled", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/interview/feedback")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), "Product Feedback")
+
+		resp, err = http.Get(server.URL + "/interview/internal")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, err = http.Post(server.URL+"/interview/internal/sessions", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should run an interview over server-sent events", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/interview/feedback/sessions", "application/json", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created struct{ ID string }
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		resp.Body.Close()
+
+		base := server.URL + "/interview/sessions/" + created.ID
+		stream, err := http.Get(base + "/events")
+		require.NoError(t, err)
+		defer stream.Body.Close()
+		assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+		events := readEvents(stream.Body)
+
+		answer := func(text string) int {
+			resp, err := http.Post(base+"/answers", "application/json", strings.NewReader(`{"answer":"`+text+`"}`))
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		event := <-events
+		assert.Equal(t, session.Event{ID: 1, Type: session.EventQuestion, Number: 1, Question: "What do you like?"}, event)
+		assert.Equal(t, http.StatusAccepted, answer("The speed."))
+		assert.Equal(t, session.EventAnswer, (<-events).Type)
+
+		event = <-events
+		assert.Equal(t, "What would you change?", event.Question)
+		assert.Equal(t, http.StatusAccepted, answer("Nothing."))
+
+		var types []session.EventType
+		for event := range events {
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, []session.EventType{session.EventAnswer, session.EventSummary, session.EventEnd}, types)
+		assert.Equal(t, http.StatusGone, answer("Too late."))
+
+		interview, err := repo.GetInterview("interview-1")
+		require.NoError(t, err)
+		assert.Equal(t, "browser:"+created.ID, interview.UserID)
+
+		// A client reconnecting after the end is told not to reconnect again.
+		req, _ := http.NewRequest(http.MethodGet, base+"/events", nil)
+		req.Header.Set("Last-Event-ID", "6")
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("should reject unknown sessions", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/interview/sessions/nope/answers", "application/json", strings.NewReader(`{"answer":"hi"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+// readEvents decodes a stream of server-sent events, closing the channel when the stream ends.
+func readEvents(r io.Reader) <-chan session.Event {
+	events := make(chan session.Event)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var event session.Event
+			if err := json.Unmarshal([]byte(data), &event); err == nil {
+				events <- event
+			}
+		}
+	}()
+	return events
+}
@@ -0,0 +1,33 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	goslack "github.com/slack-go/slack"
+)
+
+// cancelSlackInterviews cancels every interview in progress that the user who used the command is taking,
+// whether in a direct message or a thread. Nothing is saved.
+func (s *Server) cancelSlackInterviews(command goslack.SlashCommand) {
+	s.mu.Lock()
+	var cancelled int
+	for _, active := range s.activeInterviews {
+		if ui, ok := active.(*slack.UI); ok && string(ui.UserID) == command.UserID {
+			ui.Cancel()
+			cancelled++
+		}
+	}
+	s.mu.Unlock()
+
+	text := "You don't have an interview in progress."
+	switch {
+	case cancelled == 1:
+		text = "Your interview has been cancelled."
+	case cancelled > 1:
+		text = fmt.Sprintf("Your %d interviews have been cancelled.", cancelled)
+	}
+	slog.Info("Cancelling interviews for user", "user_id", command.UserID, "count", cancelled)
+	s.slackClient.PostEphemeral(command.ChannelID, command.UserID, goslack.MsgOptionText(text, false))
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	goslack "github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activeCount returns the number of interviews in progress in chat tools.
+func (s *Server) activeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.activeInterviews)
+}
+
+func TestCancelSlackInterviews(t *testing.T) {
+	s, repo, _ := newTestServer(t)
+	api, client := newFakeSlackAPI(t)
+	s.slackClient = slack.NewDispatcher(client)
+	topic := findTopic(s.config, "feedback")
+
+	done := make(chan struct{}, 3)
+	start := func(key string, ui *slack.UI) {
+		require.True(t, s.claimInterview(key, ui))
+		go func() {
+			s.runSlackInterview(key, ui, topic)
+			done <- struct{}{}
+		}()
+	}
+	start("U123", slack.New(client, "D123", "U123"))
+	start(threadKey("C123", "1.1"), slack.New(client, "C123", "U123", slack.WithThread("1.1")))
+	start("U456", slack.New(client, "D456", "U456"))
+	require.Eventually(t, func() bool { return s.activeCount() == 3 }, time.Second, time.Millisecond)
+
+	s.cancelSlackInterviews(goslack.SlashCommand{UserID: "U123", ChannelID: "C123"})
+
+	for range 2 {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			require.Fail(t, "the interview was not cancelled")
+		}
+	}
+	assert.Equal(t, 1, s.activeCount())
+	assert.NotNil(t, s.findSlackInterview("U456", "D456", ""))
+
+	ephemerals := api.sent("chat.postEphemeral")
+	require.Len(t, ephemerals, 1)
+	assert.Equal(t, "Your 2 interviews have been cancelled.", ephemerals[0].Get("text"))
+
+	_, err := repo.GetTranscript("interview-1")
+	assert.Error(t, err, "cancelled interviews should not be saved")
+	_, ok := repo.activeInterview("U123")
+	assert.False(t, ok)
+}
+
+func TestSlackInterview_Abandoned(t *testing.T) {
+	s, repo, _ := newTestServer(t)
+	_, client := newFakeSlackAPI(t)
+	s.slackClient = slack.NewDispatcher(client)
+	topic := findTopic(s.config, "feedback")
+	topic.Idle.AbandonAfter = 20 * time.Millisecond
+
+	ui := slack.New(client, "D123", "U123", slackOptions(topic)...)
+	require.True(t, s.claimInterview("U123", ui))
+	done := make(chan struct{})
+	go func() {
+		s.runSlackInterview("U123", ui, topic)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return s.findSlackInterview("U123", "D123", "") != nil }, time.Second, time.Millisecond)
+	s.handleCallbackEvent(threadMessage("U123", "D123", "", "The speed."))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "the interview was not abandoned")
+	}
+	assert.Zero(t, s.activeCount())
+
+	saved, err := repo.GetInterview("interview-1")
+	require.NoError(t, err)
+	assert.True(t, saved.Abandoned)
+	transcript, err := repo.GetTranscript("interview-1")
+	require.NoError(t, err)
+	require.Len(t, transcript.Entries, 1)
+	assert.Equal(t, "The speed.", transcript.Entries[0].Answer)
+}
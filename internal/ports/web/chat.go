@@ -0,0 +1,63 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+)
+
+// chatInterview is the UI of an interview held in a chat tool, such as Slack, Teams or Discord, over email, or by text message.
+// Interviews in progress in every chat tool are kept in activeInterviews, so a participant can only be asked
+// one set of questions in each conversation at a time.
+type chatInterview interface {
+	interview.InterviewUI
+	// Say sends the participant a message that doesn't need an answer.
+	Say(text string) error
+	// Cancel ends the interview without saving it.
+	Cancel()
+	// Close marks the interview as finished, so messages still waiting to be passed to it are dropped.
+	Close()
+}
+
+// claimInterview makes the UI the interview in progress under the key. It reports false, leaving the
+// interview in progress alone, if there already is one.
+func (s *Server) claimInterview(key string, ui chatInterview) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, busy := s.activeInterviews[key]; busy {
+		return false
+	}
+	s.activeInterviews[key] = ui
+	return true
+}
+
+// runChatInterview runs an interview with the user about the topic, which must already be the interview in
+// progress under the key, until it finishes.
+func (s *Server) runChatInterview(key string, ui chatInterview, userID string, topic *config.Topic) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeInterviews, key)
+		s.mu.Unlock()
+		ui.Close()
+		slog.Info("Interview finished for user", "user_id", userID, "key", key)
+	}()
+
+	slog.Info("Starting interview for user", "user_id", userID, "key", key, "topic_id", topic.ID)
+	interviewToRun, err := s.newInterview(topic, ui)
+	if err != nil {
+		slog.Error("Error creating interview", "error", err)
+		if err := ui.Say("Sorry, the interview could not be started."); err != nil {
+			slog.Error("Error telling user the interview could not be started", "error", err, "user_id", userID)
+		}
+		return
+	}
+	err = interviewToRun.Run(userID, topic.ID)
+	switch {
+	case errors.Is(err, interview.ErrCancelled):
+		slog.Info("Interview cancelled", "user_id", userID)
+	case err != nil:
+		slog.Error("Error running interview", "error", err, "user_id", userID)
+	}
+}
@@ -0,0 +1,290 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	goslack "github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+// actionCommand is the action ID of buttons that run a slash command for the user who pressed them, such as
+// the next page of a list. Each button's value is the command to run, without the leading "/vox".
+const actionCommand = "vox_command"
+
+const (
+	// slashPageSize is the number of items on each page of a slash command's output.
+	slashPageSize = 10
+	// maxMessageBlocks is the most blocks Slack accepts in a message.
+	maxMessageBlocks = 50
+)
+
+// errNotPM is returned when someone other than a PM asks for other people's interviews.
+var errNotPM = errors.New("only PMs can see other people's interviews")
+
+// respond sends the output of a slash command to the user who used it, through the command's response URL, so
+// that only they see it. Unlike an ephemeral message, this works in channels vox isn't a member of. If replace
+// is set, the output replaces the message the command was run from.
+func respond(command goslack.SlashCommand, replace bool, text string, blocks ...goslack.Block) error {
+	msg := &goslack.WebhookMessage{
+		ResponseType:    goslack.ResponseTypeEphemeral,
+		Text:            text,
+		ReplaceOriginal: replace,
+	}
+	if len(blocks) > 0 {
+		if len(blocks) > maxMessageBlocks {
+			blocks = blocks[:maxMessageBlocks]
+		}
+		msg.Blocks = &goslack.Blocks{BlockSet: blocks}
+	}
+	if err := goslack.PostWebhook(command.ResponseURL, msg); err != nil {
+		return fmt.Errorf("could not respond to command: %w", err)
+	}
+	return nil
+}
+
+// handleCommandActions runs the slash commands behind the buttons that were pressed, replacing the message
+// they were pressed in with the output. It reports whether there were any.
+func (s *Server) handleCommandActions(callback goslack.InteractionCallback) bool {
+	var handled bool
+	for _, action := range callback.ActionCallback.BlockActions {
+		if action.ActionID != actionCommand {
+			continue
+		}
+		handled = true
+		s.runSlashCommand(goslack.SlashCommand{
+			TeamID:      callback.Team.ID,
+			ChannelID:   callback.Channel.ID,
+			UserID:      callback.User.ID,
+			Text:        action.Value,
+			ResponseURL: callback.ResponseURL,
+		}, true)
+	}
+	return handled
+}
+
+// commandButton creates a button that runs the slash command when pressed.
+func commandButton(label, text string) *goslack.ButtonBlockElement {
+	return goslack.NewButtonBlockElement(actionCommand, text, plainText(label))
+}
+
+// pageBlocks creates the buttons to move to the pages either side of the current one. text is the command
+// that lists the items, to which the page is added.
+func pageBlocks(text string, page, pages int) []goslack.Block {
+	var buttons []goslack.BlockElement
+	if page > 1 {
+		buttons = append(buttons, commandButton("Previous", fmt.Sprintf("%s --page %d", text, page-1)))
+	}
+	if page < pages {
+		buttons = append(buttons, commandButton("Next", fmt.Sprintf("%s --page %d", text, page+1)))
+	}
+	if len(buttons) == 0 {
+		return nil
+	}
+	return []goslack.Block{goslack.NewActionBlock("", buttons...)}
+}
+
+// paginate returns the range of items to show on the page, and the number of pages.
+func paginate(items, page int) (start, end, pages int, err error) {
+	pages = max(1, (items+slashPageSize-1)/slashPageSize)
+	if page < 1 || page > pages {
+		return 0, 0, 0, fmt.Errorf("page must be between 1 and %d", pages)
+	}
+	start = (page - 1) * slashPageSize
+	return start, min(start+slashPageSize, items), pages, nil
+}
+
+// allowedInterview returns the interview if the user took it, or is a PM. Otherwise it reports that the
+// interview was not found, so as not to reveal which interviews exist.
+func (s *Server) allowedInterview(userID, id string) (*domain.Interview, error) {
+	interview, err := s.repo.GetInterview(id)
+	if err != nil || (interview.UserID != userID && !s.isPM(userID)) {
+		return nil, fmt.Errorf("interview '%s' not found", id)
+	}
+	return interview, nil
+}
+
+// newSlackRepositoryCmd creates the "repository" command for Slack, which mirrors `vox interview repository`.
+// Participants see their own interviews, and PMs see everyone's.
+func (s *Server) newSlackRepositoryCmd(command goslack.SlashCommand, replace bool) *cobra.Command {
+	repositoryCmd := &cobra.Command{
+		Use:   "repository",
+		Short: "Browse the interviews in the repository",
+	}
+
+	var topicID, user string
+	var page int
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List interviews, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := storage.InterviewFilter{ProjectID: topicID, UserID: command.UserID}
+			if user != "" {
+				match := userMention.FindStringSubmatch(user)
+				if match == nil {
+					return fmt.Errorf("mention the participant, such as @ada, instead of '%s'", user)
+				}
+				filter.UserID = match[1]
+			}
+			pm := s.isPM(command.UserID)
+			if pm && user == "" {
+				filter.UserID = ""
+			}
+			if filter.UserID != command.UserID && !pm {
+				return errNotPM
+			}
+
+			interviews, err := s.matchingInterviews(filter)
+			if err != nil {
+				return fmt.Errorf("could not list interviews: %w", err)
+			}
+			if len(interviews) == 0 {
+				return respond(command, replace, "No interviews found.")
+			}
+			start, end, pages, err := paginate(len(interviews), page)
+			if err != nil {
+				return err
+			}
+
+			text := fmt.Sprintf("Interviews %d–%d of %d", start+1, end, len(interviews))
+			blocks := []goslack.Block{goslack.NewContextBlock("", markdownText(text))}
+			for _, interview := range interviews[start:end] {
+				line := fmt.Sprintf("*%s* · <@%s> · %s\n`%s`", s.interviewTopicName(interview), interview.UserID, interviewStatus(interview), interview.ID)
+				view := commandButton("View", "interview repository view "+interview.ID)
+				blocks = append(blocks, goslack.NewSectionBlock(markdownText(line), nil, goslack.NewAccessory(view)))
+			}
+
+			list := "interview repository list"
+			if topicID != "" {
+				list += " --topic " + topicID
+			}
+			if user != "" {
+				list += fmt.Sprintf(" --user <@%s>", filter.UserID)
+			}
+			blocks = append(blocks, pageBlocks(list, page, pages)...)
+			return respond(command, replace, text, blocks...)
+		},
+	}
+	listCmd.Flags().StringVar(&topicID, "topic", "", "Only list interviews about this topic")
+	listCmd.Flags().StringVar(&user, "user", "", "Only list interviews with this participant, such as @ada")
+	listCmd.Flags().IntVar(&page, "page", 1, "The page of interviews to show")
+	repositoryCmd.AddCommand(listCmd)
+
+	var full bool
+	viewCmd := &cobra.Command{
+		Use:   "view [id]",
+		Short: "View a single interview",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interview, err := s.allowedInterview(command.UserID, args[0])
+			if err != nil {
+				return err
+			}
+			blocks, err := s.interviewBlocks(interview, full)
+			if err != nil {
+				return err
+			}
+			if !full {
+				blocks = append(blocks, goslack.NewActionBlock("",
+					commandButton("Show transcript", fmt.Sprintf("interview repository view %s --full", interview.ID)),
+					commandButton("Export", "interview repository export "+interview.ID),
+				))
+			}
+			return respond(command, replace, "Interview "+interview.ID, blocks...)
+		},
+	}
+	viewCmd.Flags().BoolVar(&full, "full", false, "Show the full transcript as well as the summary")
+	repositoryCmd.AddCommand(viewCmd)
+
+	var format string
+	exportCmd := &cobra.Command{
+		Use:   "export [id]",
+		Short: "Send yourself a single interview as a file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interview, err := s.allowedInterview(command.UserID, args[0])
+			if err != nil {
+				return err
+			}
+			format = strings.ToLower(format)
+			content, err := s.exportInterview(interview, format)
+			if err != nil {
+				return err
+			}
+
+			channel, _, _, err := s.slackClient.OpenConversation(&goslack.OpenConversationParameters{Users: []string{command.UserID}})
+			if err != nil {
+				return fmt.Errorf("could not open conversation: %w", err)
+			}
+			_, err = s.slackClient.UploadFileV2(goslack.UploadFileV2Parameters{
+				Channel:  channel.ID,
+				Content:  content,
+				FileSize: len(content),
+				Filename: fmt.Sprintf("interview-%s.%s", interview.ID, format),
+				Title:    fmt.Sprintf("%s with %s", s.interviewTopicName(interview), interview.UserID),
+			})
+			if err != nil {
+				return fmt.Errorf("could not upload export: %w", err)
+			}
+			return respond(command, replace, "The export has been sent to you in a direct message.")
+		},
+	}
+	exportCmd.Flags().StringVar(&format, "format", "json", "The format to export the interview in (json, text)")
+	repositoryCmd.AddCommand(exportCmd)
+
+	return repositoryCmd
+}
+
+// exportInterview formats the interview, its transcript and its summary as `vox interview repository export`
+// does. Redacted values are not restored.
+func (s *Server) exportInterview(interview *domain.Interview, format string) (string, error) {
+	transcript, err := s.repo.GetTranscript(interview.ID)
+	if err != nil {
+		return "", fmt.Errorf("could not get transcript: %w", err)
+	}
+	summary, err := s.repo.GetSummary(interview.ID)
+	if err != nil {
+		return "", fmt.Errorf("could not get summary: %w", err)
+	}
+
+	return domain.ExportInterview(interview, transcript, summary, format)
+}
+
+// newSlackTopicsCmd creates the "topics" command for Slack, which lists the topics interviews can be started
+// about.
+func (s *Server) newSlackTopicsCmd(command goslack.SlashCommand, replace bool) *cobra.Command {
+	var page int
+	cmd := &cobra.Command{
+		Use:   "topics",
+		Short: "List the topics you can be interviewed about",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topics := s.config.Interviews
+			if len(topics) == 0 {
+				return respond(command, replace, "No topics are configured.")
+			}
+			start, end, pages, err := paginate(len(topics), page)
+			if err != nil {
+				return err
+			}
+
+			text := fmt.Sprintf("Topics %d–%d of %d", start+1, end, len(topics))
+			blocks := []goslack.Block{goslack.NewContextBlock("", markdownText(text))}
+			for _, topic := range topics[start:end] {
+				line := fmt.Sprintf("*%s*\n`/vox interview start --topic %s`", topicName(&topic), topic.ID)
+				if len(topic.Questions) > 0 {
+					line += fmt.Sprintf(" · %d questions", len(topic.Questions))
+				}
+				blocks = append(blocks, goslack.NewSectionBlock(markdownText(line), nil, nil))
+			}
+			blocks = append(blocks, pageBlocks("topics", page, pages)...)
+			return respond(command, replace, text, blocks...)
+		},
+	}
+	cmd.Flags().IntVar(&page, "page", 1, "The page of topics to show")
+	return cmd
+}
@@ -0,0 +1,156 @@
+package web
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	goslack "github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slashCommand creates the slash command sent when the user runs /vox with the text.
+func slashCommand(api *fakeSlackAPI, userID, text string) goslack.SlashCommand {
+	return goslack.SlashCommand{UserID: userID, ChannelID: "C123", Text: text, ResponseURL: api.url + "/response"}
+}
+
+// lastResponse returns the body of the last response sent to a slash command.
+func lastResponse(t *testing.T, api *fakeSlackAPI) string {
+	t.Helper()
+	responses := api.sent("response")
+	require.NotEmpty(t, responses)
+	return responses[len(responses)-1].Get("body")
+}
+
+func TestSlackRepositoryList(t *testing.T) {
+	t.Run("should list participants' own interviews", func(t *testing.T) {
+		s, _, api := newTestHomeServer(t)
+		s.handleSlashCommand(slashCommand(api, "U123", "interview repository list"))
+
+		response := lastResponse(t, api)
+		assert.Contains(t, response, `"response_type":"ephemeral"`)
+		assert.Contains(t, response, "Interviews 1–2 of 2")
+		assert.Contains(t, response, `"value":"interview repository view completed"`)
+		assert.Contains(t, response, `"value":"interview repository view abandoned"`)
+		assert.NotContains(t, response, "other")
+	})
+
+	t.Run("should only let PMs list other people's interviews", func(t *testing.T) {
+		s, _, api := newTestHomeServer(t)
+		s.handleSlashCommand(slashCommand(api, "U123", "interview repository list --user <@U456|bob>"))
+		assert.Empty(t, api.sent("response"))
+		ephemerals := api.sent("chat.postEphemeral")
+		require.Len(t, ephemerals, 1)
+		assert.Equal(t, "Error: only PMs can see other people's interviews", ephemerals[0].Get("text"))
+
+		s.handleSlashCommand(slashCommand(api, "UPM", "interview repository list"))
+		assert.Contains(t, lastResponse(t, api), "Interviews 1–3 of 3")
+
+		s.handleSlashCommand(slashCommand(api, "UPM", "interview repository list --user <@U456|bob>"))
+		response := lastResponse(t, api)
+		assert.Contains(t, response, "Interviews 1–1 of 1")
+		assert.Contains(t, response, `"value":"interview repository view other"`)
+	})
+
+	t.Run("should page through the interviews", func(t *testing.T) {
+		s, repo, api := newTestHomeServer(t)
+		for i := range 10 {
+			interview := &domain.Interview{ID: fmt.Sprintf("extra-%d", i), UserID: "U123", ProjectID: "feedback", CreatedAt: time.Now()}
+			_, err := repo.SaveInterview(interview, &domain.Transcript{}, &domain.Summary{})
+			require.NoError(t, err)
+		}
+
+		s.handleSlashCommand(slashCommand(api, "U123", "interview repository list --topic feedback"))
+		response := lastResponse(t, api)
+		assert.Contains(t, response, "Interviews 1–10 of 12")
+		assert.Contains(t, response, `"replace_original":false`)
+		assert.Contains(t, response, `"value":"interview repository list --topic feedback --page 2"`)
+		assert.NotContains(t, response, "Previous")
+
+		var callback goslack.InteractionCallback
+		callback.Type = goslack.InteractionTypeBlockActions
+		callback.User.ID = "U123"
+		callback.Channel.ID = "C123"
+		callback.ResponseURL = api.url + "/response"
+		callback.ActionCallback.BlockActions = []*goslack.BlockAction{{ActionID: actionCommand, Value: "interview repository list --topic feedback --page 2"}}
+		s.handleBlockActions(callback)
+
+		response = lastResponse(t, api)
+		assert.Contains(t, response, "Interviews 11–12 of 12")
+		assert.Contains(t, response, `"replace_original":true`)
+		assert.Contains(t, response, `"value":"interview repository list --topic feedback --page 1"`)
+		assert.NotContains(t, response, "Next")
+	})
+}
+
+func TestSlackRepositoryView(t *testing.T) {
+	s, _, api := newTestHomeServer(t)
+
+	t.Run("should not show other people's interviews to participants", func(t *testing.T) {
+		s.handleSlashCommand(slashCommand(api, "U123", "interview repository view other"))
+		assert.Empty(t, api.sent("response"))
+		ephemerals := api.sent("chat.postEphemeral")
+		require.NotEmpty(t, ephemerals)
+		assert.Equal(t, "Error: interview 'other' not found", ephemerals[len(ephemerals)-1].Get("text"))
+	})
+
+	t.Run("should show the summary to PMs", func(t *testing.T) {
+		s.handleSlashCommand(slashCommand(api, "UPM", "interview repository view other"))
+		response := lastResponse(t, api)
+		assert.Contains(t, response, "Wants an export button.")
+		assert.NotContains(t, response, "Exports.")
+		assert.Contains(t, response, `"value":"interview repository view other --full"`)
+	})
+
+	t.Run("should show the transcript with --full", func(t *testing.T) {
+		s.handleSlashCommand(slashCommand(api, "U123", "interview repository view completed --full"))
+		assert.Contains(t, lastResponse(t, api), `*What would you change?*\nNothing.`)
+	})
+}
+
+func TestSlackRepositoryExport(t *testing.T) {
+	s, _, api := newTestHomeServer(t)
+
+	s.handleSlashCommand(slashCommand(api, "U123", "interview repository export completed --format text"))
+
+	uploads := api.sent("upload")
+	require.Len(t, uploads, 1)
+	assert.Contains(t, uploads[0].Get("file"), "Interview ID: completed")
+	assert.Contains(t, uploads[0].Get("file"), "Q: What do you like?\nA: The speed.")
+	completed := api.sent("files.completeUploadExternal")
+	require.Len(t, completed, 1)
+	assert.Equal(t, "D999", completed[0].Get("channel_id"))
+	assert.Contains(t, lastResponse(t, api), "The export has been sent to you in a direct message.")
+}
+
+func TestSlackTopics(t *testing.T) {
+	s, _, api := newTestHomeServer(t)
+
+	s.handleSlashCommand(slashCommand(api, "U123", "topics"))
+
+	response := lastResponse(t, api)
+	assert.Contains(t, response, "Topics 1–2 of 2")
+	assert.Contains(t, response, "*Product Feedback*")
+	assert.Contains(t, response, "/vox interview start --topic internal")
+}
+
+func TestSlackInterviewStart(t *testing.T) {
+	t.Run("should not replace an interview already in progress", func(t *testing.T) {
+		s, _, _ := newTestServer(t)
+		api, client := newFakeSlackAPI(t)
+		s.slackClient = slack.NewDispatcher(client)
+		existing := slack.New(client, "D123", "U123")
+		s.activeInterviews["U123"] = existing
+
+		s.handleSlashCommand(slashCommand(api, "U123", "interview start --topic feedback"))
+
+		assert.Same(t, existing, s.findSlackInterview("U123", "D123", ""))
+		ephemerals := api.sent("chat.postEphemeral")
+		require.Len(t, ephemerals, 1)
+		assert.Contains(t, ephemerals[0].Get("text"), "You already have an interview in progress.")
+		assert.Empty(t, api.sent("chat.postMessage"))
+	})
+}
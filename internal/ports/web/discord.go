@@ -0,0 +1,226 @@
+package web
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/discord"
+	"github.com/andrewhowdencom/vox/internal/config"
+)
+
+// maxInteractionSize is the largest interaction accepted by /discord/interactions.
+const maxInteractionSize = 1 << 20
+
+// discordCommands are the slash commands vox registers for its Discord application.
+var discordCommands = []discord.Command{{
+	Name:        "vox",
+	Description: "Take part in an interview",
+	Options: []discord.Command{
+		{
+			Name:        "start",
+			Description: "Start an interview about a topic",
+			Type:        discord.OptionSubcommand,
+			Options: []discord.Command{
+				{Name: "topic", Description: "The ID of the interview topic", Type: discord.OptionString, Required: true},
+				{Name: "thread", Description: "Hold the interview in a thread in this channel, instead of a direct message", Type: discord.OptionBoolean},
+			},
+		},
+		{Name: "cancel", Description: "Cancel your interviews in progress, without saving them", Type: discord.OptionSubcommand},
+	},
+}}
+
+// discordBot holds interviews in Discord.
+type discordBot struct {
+	applicationID string
+	publicKey     ed25519.PublicKey
+	client        *discord.Client
+}
+
+// discordKey identifies an interview held in a direct message or thread in Discord in activeInterviews.
+func discordKey(channelID string) string {
+	return "discord/" + channelID
+}
+
+// newDiscordBot creates the bot configured in the discord section of the configuration.
+func newDiscordBot(cfg *config.Config) (*discordBot, error) {
+	publicKey, err := discord.ParsePublicKey(cfg.Discord.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	var opts []discord.ClientOption
+	if cfg.Discord.APIURL != "" {
+		opts = append(opts, discord.WithAPIURL(cfg.Discord.APIURL))
+	}
+	return &discordBot{
+		applicationID: cfg.Discord.ApplicationID,
+		publicKey:     publicKey,
+		client:        discord.NewClient(cfg.Discord.BotToken, opts...),
+	}, nil
+}
+
+// registerDiscordRoutes adds the handler for the interactions Discord sends the bot.
+func (s *Server) registerDiscordRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /discord/interactions", s.createDiscordInteractionHandler())
+}
+
+// createDiscordInteractionHandler checks that each interaction was signed by Discord, and replies to it.
+func (s *Server) createDiscordInteractionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInteractionSize))
+		if err != nil {
+			slog.Error("Error reading request body", "error", err)
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := discord.Verify(s.discord.publicKey, r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body); err != nil {
+			slog.Error("Error verifying Discord interaction", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var interaction discord.Interaction
+		if err := json.Unmarshal(body, &interaction); err != nil {
+			slog.Error("Error parsing Discord interaction", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var response *discord.InteractionResponse
+		switch interaction.Type {
+		case discord.InteractionPing:
+			response = &discord.InteractionResponse{Type: discord.ResponsePong}
+		case discord.InteractionApplicationCommand:
+			response = s.handleDiscordCommand(interaction)
+		case discord.InteractionMessageComponent, discord.InteractionModalSubmit:
+			response = s.handleDiscordAnswer(interaction)
+		default:
+			slog.Debug("Ignoring Discord interaction", "type", interaction.Type)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// discordReply is a reply to an interaction that only the user who used it can see.
+func discordReply(text string) *discord.InteractionResponse {
+	return &discord.InteractionResponse{Type: discord.ResponseMessage, Data: &discord.ResponseData{Content: text, Flags: discord.FlagEphemeral}}
+}
+
+// handleDiscordCommand runs the /vox slash command.
+func (s *Server) handleDiscordCommand(interaction discord.Interaction) *discord.InteractionResponse {
+	userID := interaction.UserID()
+	slog.Debug("Handling Discord command", "subcommand", interaction.Subcommand(), "user_id", userID, "channel_id", interaction.ChannelID)
+
+	switch interaction.Subcommand() {
+	case "start":
+		topicID, _ := interaction.Option("topic").(string)
+		topic := findTopic(s.config, topicID)
+		if topic == nil {
+			return discordReply(fmt.Sprintf("Error: topic '%s' not found", topicID))
+		}
+		inThread, _ := interaction.Option("thread").(bool)
+		if inThread && interaction.GuildID == "" {
+			return discordReply("Interviews can only be held in a thread in a server channel.")
+		}
+		return s.startDiscordInterview(interaction, topic, inThread)
+	case "cancel":
+		return discordReply(s.cancelDiscordInterviews(userID))
+	}
+	return discordReply("Unknown command. Use `/vox start` or `/vox cancel`.")
+}
+
+// startDiscordInterview starts an interview with the user who used the command, in a direct message or in a
+// new thread in the channel the command was used in.
+func (s *Server) startDiscordInterview(interaction discord.Interaction, topic *config.Topic, inThread bool) *discord.InteractionResponse {
+	userID := interaction.UserID()
+	name := topicName(topic)
+
+	var channelID, intro, reply string
+	var err error
+	if inThread {
+		channelID, err = s.discord.client.StartThread(interaction.ChannelID, "Interview: "+name)
+		intro = fmt.Sprintf("<@%s> is being interviewed about **%s**. Press **Answer** on each question to reply.", userID, name)
+		reply = fmt.Sprintf("Your interview is in <#%s>.", channelID)
+	} else {
+		channelID, err = s.discord.client.CreateDM(userID)
+		intro = fmt.Sprintf("Let's talk about **%s**. Press **Answer** on each question to reply.", name)
+		reply = "Your interview has started. Check your direct messages."
+	}
+	if err != nil {
+		slog.Error("Failed to start Discord interview", "error", err, "user_id", userID, "thread", inThread)
+		return discordReply("Error: the interview could not be started. Can vox send you direct messages, or start threads here?")
+	}
+
+	key := discordKey(channelID)
+	ui := discord.New(s.discord.client, channelID, userID)
+	if !s.claimInterview(key, ui) {
+		return discordReply("You already have an interview in progress. Use `/vox cancel` to cancel it.")
+	}
+	go func() {
+		if err := ui.Say(intro); err != nil {
+			slog.Error("Error introducing Discord interview", "error", err, "user_id", userID)
+		}
+		s.runChatInterview(key, ui, userID, topic)
+	}()
+	return discordReply(reply)
+}
+
+// cancelDiscordInterviews cancels every interview in progress in Discord with the user, returning the reply
+// to send them. Nothing is saved.
+func (s *Server) cancelDiscordInterviews(userID string) string {
+	s.mu.Lock()
+	var cancelled int
+	for _, active := range s.activeInterviews {
+		if ui, ok := active.(*discord.UI); ok && ui.UserID == userID {
+			ui.Cancel()
+			cancelled++
+		}
+	}
+	s.mu.Unlock()
+
+	slog.Info("Cancelling Discord interviews for user", "user_id", userID, "count", cancelled)
+	switch {
+	case cancelled == 1:
+		return "Your interview has been cancelled."
+	case cancelled > 1:
+		return fmt.Sprintf("Your %d interviews have been cancelled.", cancelled)
+	}
+	return "You don't have an interview in progress."
+}
+
+// handleDiscordAnswer handles a button pressed on a question, or the form submitted to answer it. The button to
+// answer a question opens the form.
+func (s *Server) handleDiscordAnswer(interaction discord.Interaction) *discord.InteractionResponse {
+	s.mu.Lock()
+	ui, ok := s.activeInterviews[discordKey(interaction.ChannelID)].(*discord.UI)
+	s.mu.Unlock()
+	if !ok || interaction.Data == nil {
+		return discordReply("This interview has finished.")
+	}
+	if ui.UserID != interaction.UserID() {
+		return discordReply("Only the person being interviewed can answer.")
+	}
+
+	if form, ok := discord.AnswerForm(interaction.Data.CustomID); ok {
+		return form
+	}
+	if !ui.HandleInteraction(interaction) {
+		return discordReply("That question has already been answered.")
+	}
+	if interaction.Type != discord.InteractionModalSubmit {
+		return &discord.InteractionResponse{Type: discord.ResponseDeferredUpdate}
+	}
+
+	// The answer is shown in the conversation, as the participant can't send it as a message themselves.
+	answer := "> " + strings.ReplaceAll(strings.TrimSpace(interaction.FormValue(discord.FieldAnswer)), "\n", "\n> ")
+	return &discord.InteractionResponse{Type: discord.ResponseMessage, Data: &discord.ResponseData{
+		Content:         truncate(answer, 2000),
+		AllowedMentions: &discord.AllowedMentions{Parse: []string{}},
+	}}
+}
@@ -0,0 +1,227 @@
+package web
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiscord stands in for Discord: it signs the interactions sent to the bot, and records the messages the
+// bot sends to each channel. Direct message channels are named after their recipient.
+type fakeDiscord struct {
+	url string
+	key ed25519.PrivateKey
+
+	mu       sync.Mutex
+	messages map[string][]discord.Message
+}
+
+func newFakeDiscord(t *testing.T) (*fakeDiscord, ed25519.PublicKey) {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	f := &fakeDiscord{key: privateKey, messages: make(map[string][]discord.Message)}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	f.url = server.URL
+	mux.HandleFunc("POST /users/@me/channels", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]string{"id": "dm-" + body["recipient_id"]})
+	})
+	mux.HandleFunc("POST /channels/{id}/threads", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"id": "thread-" + r.PathValue("id")})
+	})
+	mux.HandleFunc("POST /channels/{id}/messages", func(w http.ResponseWriter, r *http.Request) {
+		var message discord.Message
+		json.NewDecoder(r.Body).Decode(&message)
+		f.mu.Lock()
+		f.messages[r.PathValue("id")] = append(f.messages[r.PathValue("id")], message)
+		n := len(f.messages[r.PathValue("id")])
+		f.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]string{"id": strconv.Itoa(n)})
+	})
+	return f, publicKey
+}
+
+// sent returns the messages the bot sent to the channel.
+func (f *fakeDiscord) sent(channelID string) []discord.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]discord.Message(nil), f.messages[channelID]...)
+}
+
+// send sends the bot the interaction, signed by Discord, returning the status and the decoded response.
+func (f *fakeDiscord) send(t *testing.T, server *httptest.Server, interaction discord.Interaction) (int, discord.InteractionResponse) {
+	t.Helper()
+	body, err := json.Marshal(interaction)
+	require.NoError(t, err)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/discord/interactions", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(f.key, append([]byte(timestamp), body...))))
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var response discord.InteractionResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	return resp.StatusCode, response
+}
+
+// discordCommand returns the /vox slash command used by the user in the channel.
+func discordCommand(userID, channelID, subcommand string, options ...discord.InteractionOption) discord.Interaction {
+	return discord.Interaction{
+		Type:      discord.InteractionApplicationCommand,
+		GuildID:   "G1",
+		ChannelID: channelID,
+		Member:    &discord.Member{User: &discord.User{ID: userID}},
+		Data: &discord.InteractionData{Name: "vox", Options: []discord.InteractionOption{
+			{Name: subcommand, Type: discord.OptionSubcommand, Options: options},
+		}},
+	}
+}
+
+// discordPress returns the button pressed, or form submitted, by the user in the channel.
+func discordPress(userID, channelID, customID, answer string) discord.Interaction {
+	interaction := discord.Interaction{
+		Type:      discord.InteractionMessageComponent,
+		ChannelID: channelID,
+		User:      &discord.User{ID: userID},
+		Data:      &discord.InteractionData{CustomID: customID},
+	}
+	if answer != "" {
+		interaction.Type = discord.InteractionModalSubmit
+		interaction.Data.Components = []discord.Component{{Type: discord.ComponentActionRow, Components: []discord.Component{
+			{Type: discord.ComponentTextInput, CustomID: discord.FieldAnswer, Value: answer},
+		}}}
+	}
+	return interaction
+}
+
+// newTestDiscordServer creates a server with Discord configured against the fake.
+func newTestDiscordServer(t *testing.T) (*Server, *memoryRepository, *fakeDiscord, *httptest.Server) {
+	t.Helper()
+	fake, publicKey := newFakeDiscord(t)
+	s, repo, _ := newTestServer(t)
+	s.config.Discord.ApplicationID = "A1"
+	s.config.Discord.PublicKey = hex.EncodeToString(publicKey)
+	s.config.Discord.BotToken = "token"
+	s.config.Discord.APIURL = fake.url
+	bot, err := newDiscordBot(s.config)
+	require.NoError(t, err)
+	s.discord = bot
+
+	mux := http.NewServeMux()
+	s.registerDiscordRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, repo, fake, server
+}
+
+func TestDiscordInteractions(t *testing.T) {
+	s, repo, fake, server := newTestDiscordServer(t)
+	topic := discord.InteractionOption{Name: "topic", Type: discord.OptionString, Value: "feedback"}
+	waitFor := func(channelID string, n int) {
+		t.Helper()
+		require.Eventually(t, func() bool { return len(fake.sent(channelID)) >= n }, 2*time.Second, 5*time.Millisecond)
+	}
+
+	t.Run("should reject interactions that aren't signed by Discord", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/discord/interactions", "application/json", bytes.NewBufferString(`{"type":1}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("should reject interactions that are too large", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/discord/interactions", "application/json", bytes.NewReader(make([]byte, maxInteractionSize+1)))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+
+	t.Run("should reply to pings", func(t *testing.T) {
+		status, response := fake.send(t, server, discord.Interaction{Type: discord.InteractionPing})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, discord.ResponsePong, response.Type)
+	})
+
+	t.Run("should report an unknown topic", func(t *testing.T) {
+		missing := discord.InteractionOption{Name: "topic", Type: discord.OptionString, Value: "missing"}
+		_, response := fake.send(t, server, discordCommand("ada", "C1", "start", missing))
+		require.NotNil(t, response.Data)
+		assert.Equal(t, "Error: topic 'missing' not found", response.Data.Content)
+		assert.Equal(t, discord.FlagEphemeral, response.Data.Flags)
+	})
+
+	t.Run("should interview the user in a direct message", func(t *testing.T) {
+		_, response := fake.send(t, server, discordCommand("ada", "C1", "start", topic))
+		require.NotNil(t, response.Data)
+		assert.Equal(t, "Your interview has started. Check your direct messages.", response.Data.Content)
+		waitFor("dm-ada", 2)
+		assert.Equal(t, "What do you like?\n-# Question 1 of 2", fake.sent("dm-ada")[1].Content)
+
+		t.Run("should only let the participant answer", func(t *testing.T) {
+			_, response := fake.send(t, server, discordPress("grace", "dm-ada", "vox_answer:1", ""))
+			assert.Equal(t, "Only the person being interviewed can answer.", response.Data.Content)
+		})
+
+		_, response = fake.send(t, server, discordPress("ada", "dm-ada", "vox_answer:1", ""))
+		assert.Equal(t, discord.ResponseModal, response.Type)
+		require.NotNil(t, response.Data)
+		_, response = fake.send(t, server, discordPress("ada", "dm-ada", response.Data.CustomID, "The reports\n@everyone"))
+		assert.Equal(t, discord.ResponseMessage, response.Type)
+		assert.Equal(t, "> The reports\n> @everyone", response.Data.Content)
+		assert.Empty(t, response.Data.AllowedMentions.Parse)
+
+		waitFor("dm-ada", 3)
+		_, response = fake.send(t, server, discordPress("ada", "dm-ada", "vox_skip:2", ""))
+		assert.Equal(t, discord.ResponseDeferredUpdate, response.Type)
+		require.Eventually(t, func() bool { return s.activeCount() == 0 }, 2*time.Second, 5*time.Millisecond)
+
+		transcript, err := repo.GetTranscript("interview-1")
+		require.NoError(t, err)
+		require.NotEmpty(t, transcript.Entries)
+		assert.Equal(t, "The reports\n@everyone", transcript.Entries[0].Answer)
+		interview, err := repo.GetInterview("interview-1")
+		require.NoError(t, err)
+		assert.Equal(t, "ada", interview.UserID)
+
+		t.Run("should ignore buttons once the interview has finished", func(t *testing.T) {
+			_, response := fake.send(t, server, discordPress("ada", "dm-ada", "vox_skip:2", ""))
+			assert.Equal(t, "This interview has finished.", response.Data.Content)
+		})
+	})
+
+	t.Run("should cancel the interview in progress in a thread", func(t *testing.T) {
+		thread := discord.InteractionOption{Name: "thread", Type: discord.OptionBoolean, Value: true}
+		_, response := fake.send(t, server, discordCommand("grace", "C2", "start", topic, thread))
+		assert.Equal(t, "Your interview is in <#thread-C2>.", response.Data.Content)
+		waitFor("thread-C2", 2)
+
+		_, response = fake.send(t, server, discordCommand("grace", "C2", "start", topic, thread))
+		assert.Equal(t, "You already have an interview in progress. Use `/vox cancel` to cancel it.", response.Data.Content)
+
+		_, response = fake.send(t, server, discordCommand("grace", "C2", "cancel"))
+		assert.Equal(t, "Your interview has been cancelled.", response.Data.Content)
+		waitFor("thread-C2", 3)
+		assert.Equal(t, "This interview was cancelled, and your answers have been discarded.", fake.sent("thread-C2")[2].Content)
+	})
+}
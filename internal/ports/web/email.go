@@ -0,0 +1,286 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/email"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultEmailPollInterval is how often the mailbox is checked for replies if the configuration doesn't say.
+	defaultEmailPollInterval = time.Minute
+	// maxEmailSize is the largest email accepted by /email/inbound.
+	maxEmailSize = 10 << 20
+)
+
+// emailChannel holds interviews over email.
+type emailChannel struct {
+	from   string
+	domain string
+	sender email.Sender
+	// imap is nil if replies are only delivered to /email/inbound.
+	imap         *email.IMAPClient
+	pollInterval time.Duration
+}
+
+// emailKey identifies an interview held over email in activeInterviews.
+func emailKey(interviewID string) string {
+	return "email/" + interviewID
+}
+
+// newEmailChannel creates the channel configured in the email section of the configuration.
+func newEmailChannel(cfg *config.Config) *emailChannel {
+	from := cfg.Email.From
+	if address, err := mail.ParseAddress(from); err == nil {
+		from = address.Address
+	}
+	channel := &emailChannel{
+		from:         cfg.Email.From,
+		domain:       email.Domain(from),
+		sender:       email.NewSMTPSender(cfg.Email.SMTP.Address, cfg.Email.SMTP.Username, cfg.Email.SMTP.Password),
+		pollInterval: cfg.Email.IMAP.PollInterval,
+	}
+	if channel.pollInterval == 0 {
+		channel.pollInterval = defaultEmailPollInterval
+	}
+	if imap := cfg.Email.IMAP; imap.Address != "" {
+		var opts []email.IMAPOption
+		if imap.Mailbox != "" {
+			opts = append(opts, email.WithMailbox(imap.Mailbox))
+		}
+		if imap.Plaintext {
+			opts = append(opts, email.WithPlaintext())
+		}
+		channel.imap = email.NewIMAPClient(imap.Address, imap.Username, imap.Password, opts...)
+	}
+	return channel
+}
+
+// registerEmailRoutes adds the handlers for starting interviews over email and, if a token is configured, for
+// replies delivered by a mail provider.
+func (s *Server) registerEmailRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v1/email-interviews", s.authenticate(scopeSessionsWrite, s.createEmailInterview))
+	if s.config.Email.InboundToken != "" {
+		mux.HandleFunc("POST /email/inbound", s.createInboundEmailHandler())
+	}
+}
+
+// createEmailInterview starts an interview about a topic with the participant at an email address.
+func (s *Server) createEmailInterview(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	var body struct {
+		Topic   string `json:"topic"`
+		Address string `json:"address"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnswerSize)).Decode(&body); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "the request body must be a JSON object")
+		return
+	}
+	address, err := mail.ParseAddress(body.Address)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "a valid email address is required")
+		return
+	}
+	topic := findTopic(s.config, body.Topic)
+	if topic == nil {
+		writeAPIError(w, http.StatusNotFound, "topic '"+body.Topic+"' not found")
+		return
+	}
+
+	now := time.Now()
+	participant := strings.ToLower(address.Address)
+	active := &domain.ActiveInterview{
+		ID:        uuid.NewString(),
+		TopicID:   topic.ID,
+		UserID:    participant,
+		Via:       domain.ViaEmail,
+		ChannelID: participant,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+	ui := s.newEmailUI(active, topic, false)
+	s.claimInterview(emailKey(active.ID), ui)
+	slog.Info("Starting interview over email", "interview_id", active.ID, "topic_id", topic.ID, "client", client.Name)
+	go s.continueEmailInterview(active, ui, topic)
+
+	writeJSON(w, http.StatusCreated, map[string]string{"id": active.ID, "topic": topic.ID, "address": participant})
+}
+
+// newEmailUI creates the UI for an interview over email. The question waiting for an answer is saved each time
+// one is sent, so a resumed interview doesn't send it again.
+func (s *Server) newEmailUI(active *domain.ActiveInterview, topic *config.Topic, resumed bool) *email.UI {
+	name := topicName(topic)
+	opts := []email.Option{email.WithAskHook(func(n int, question string) {
+		active.Asked = n
+		active.Pending = question
+		active.UpdatedAt = time.Now()
+		if s.progress == nil {
+			return
+		}
+		if err := s.progress.SaveActiveInterview(active); err != nil {
+			slog.Error("Error saving interview progress", "error", err, "interview_id", active.ID)
+		}
+	})}
+	if resumed {
+		opts = append(opts, email.WithResume(active.Asked, active.Pending))
+	} else {
+		intro := fmt.Sprintf("Hello,\n\nWe'd like to ask you a few questions about %s. Reply to each email to answer it; there's no need to answer them all at once.", name)
+		opts = append(opts, email.WithIntro(intro))
+	}
+	return email.New(s.email.sender, s.email.from, active.ChannelID, active.ID, "Interview: "+name, opts...)
+}
+
+// continueEmailInterview runs an interview over email, picking up after any answers already in its transcript.
+// Its progress is saved after each question and answer, so it can be restored by restoreEmailInterviews
+// however long the participant takes to reply.
+func (s *Server) continueEmailInterview(active *domain.ActiveInterview, ui *email.UI, topic *config.Topic) {
+	key := emailKey(active.ID)
+	var opts []interview.Option
+	if s.progress != nil {
+		if err := s.progress.SaveActiveInterview(active); err != nil {
+			slog.Error("Error saving interview progress", "error", err, "interview_id", active.ID)
+		}
+		opts = append(opts, interview.WithCheckpointer(&progressCheckpointer{store: s.progress, active: active}))
+	}
+	if len(active.Transcript.Entries) > 0 {
+		opts = append(opts, interview.WithTranscript(&active.Transcript))
+	}
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeInterviews, key)
+		s.mu.Unlock()
+		ui.Close()
+		if s.progress != nil {
+			if err := s.progress.DeleteActiveInterview(active.ID); err != nil {
+				slog.Error("Error removing interview progress", "error", err, "interview_id", active.ID)
+			}
+		}
+		slog.Info("Interview finished for user", "user_id", active.UserID, "key", key)
+	}()
+
+	interviewToRun, err := s.newInterview(topic, ui, opts...)
+	if err != nil {
+		slog.Error("Error creating interview", "error", err)
+		return
+	}
+	err = interviewToRun.Run(active.UserID, topic.ID)
+	switch {
+	case errors.Is(err, interview.ErrCancelled):
+		slog.Info("Interview cancelled", "user_id", active.UserID)
+	case err != nil:
+		slog.Error("Error running interview", "error", err, "user_id", active.UserID)
+	}
+}
+
+// restoreEmailInterviews picks up the interviews over email that were in progress when the server last stopped.
+// The question waiting for an answer isn't sent again.
+func (s *Server) restoreEmailInterviews() {
+	actives, err := s.progress.ListActiveInterviews()
+	if err != nil {
+		slog.Error("Error listing interviews in progress", "error", err)
+		return
+	}
+
+	for _, active := range actives {
+		if active.Via != domain.ViaEmail {
+			continue
+		}
+		topic := findTopic(s.config, active.TopicID)
+		if topic == nil {
+			slog.Warn("Discarding interview in progress for a topic that no longer exists", "topic_id", active.TopicID, "user_id", active.UserID)
+			s.discardActiveInterview(active)
+			continue
+		}
+
+		ui := s.newEmailUI(active, topic, true)
+		s.claimInterview(emailKey(active.ID), ui)
+		slog.Info("Restoring interview in progress", "user_id", active.UserID, "topic_id", active.TopicID, "answers", len(active.Transcript.Entries))
+		go s.continueEmailInterview(active, ui, topic)
+	}
+}
+
+// createInboundEmailHandler takes the replies delivered by a mail provider, each as a raw MIME message.
+func (s *Server) createInboundEmailHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(s.config.Email.InboundToken)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEmailSize))
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		switch err := s.receiveEmail(raw); {
+		case errors.Is(err, email.ErrMalformed):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, email.ErrNotWaiting):
+			// The provider tries again later, by when the interview has been picked up.
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
+
+// receiveEmail passes a reply to the interview it is for. Emails that aren't replies from the participant to
+// an interview in progress are ignored. It returns email.ErrNotWaiting if the reply should be passed on again
+// later.
+func (s *Server) receiveEmail(raw []byte) error {
+	reply, err := email.ParseReply(raw)
+	if err != nil {
+		slog.Warn("Ignoring email that can't be read", "error", err)
+		return err
+	}
+	interviewID, n, ok := reply.Thread(s.email.domain)
+	if !ok {
+		slog.Info("Ignoring email that isn't a reply to an interview", "from", reply.From, "subject", reply.Subject)
+		return nil
+	}
+
+	s.mu.Lock()
+	ui, ok := s.activeInterviews[emailKey(interviewID)].(*email.UI)
+	s.mu.Unlock()
+	switch {
+	case !ok:
+		slog.Info("Ignoring reply to an interview that isn't in progress", "interview_id", interviewID, "from", reply.From)
+		return nil
+	case reply.From != ui.Address:
+		slog.Warn("Ignoring reply from someone other than the participant", "interview_id", interviewID, "from", reply.From)
+		return nil
+	case reply.Text == "":
+		slog.Info("Ignoring empty reply", "interview_id", interviewID, "question", n)
+		return nil
+	}
+
+	err = ui.Answer(n, reply.Text)
+	if errors.Is(err, email.ErrStale) {
+		slog.Info("Ignoring reply to a question that has already been answered", "interview_id", interviewID, "question", n)
+		return nil
+	}
+	return err
+}
+
+// watchEmail passes each unread email in the mailbox to receiveEmail, leaving those it can't take yet unread.
+func (s *Server) watchEmail(ctx context.Context) {
+	s.email.imap.Watch(ctx, s.email.pollInterval, func(raw []byte) bool {
+		return !errors.Is(s.receiveEmail(raw), email.ErrNotWaiting)
+	})
+}
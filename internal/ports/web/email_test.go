@@ -0,0 +1,151 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer records the emails sent through it.
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []*mail.Message
+}
+
+func (f *fakeMailer) Send(from string, to []string, message []byte) error {
+	msg, err := mail.ReadMessage(bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// messages returns the emails sent so far.
+func (f *fakeMailer) messages() []*mail.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*mail.Message(nil), f.sent...)
+}
+
+// newTestEmailServer creates a server that sends emails through a fake, and takes replies on /email/inbound.
+func newTestEmailServer(t *testing.T) (*Server, *memoryRepository, *fakeMailer, *httptest.Server) {
+	t.Helper()
+	s, repo, _ := newTestServer(t)
+	s.config.API.Tokens = []config.APIToken{{Name: "crm", Token: "crm-token", Scopes: []string{scopeSessionsWrite}}}
+	s.config.Email.From = "Vox <research@example.com>"
+	s.config.Email.InboundToken = "inbound-token"
+	mailer := &fakeMailer{}
+	s.email = &emailChannel{from: s.config.Email.From, domain: "example.com", sender: mailer}
+
+	mux := http.NewServeMux()
+	s.registerEmailRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, repo, mailer, server
+}
+
+// deliver delivers a reply from the address to the question's email to /email/inbound, returning the status.
+func deliver(t *testing.T, server *httptest.Server, from, inReplyTo, text string) int {
+	t.Helper()
+	raw := fmt.Sprintf("From: %s\r\nSubject: Re: Interview\r\nIn-Reply-To: %s\r\n\r\n%s\r\n\r\nOn Mon, 6 Jan 2025, Vox <research@example.com> wrote:\r\n> A question\r\n", from, inReplyTo, text)
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/email/inbound", bytes.NewBufferString(raw))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer inbound-token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestEmailInterview(t *testing.T) {
+	s, repo, mailer, server := newTestEmailServer(t)
+	waitFor := func(n int) {
+		t.Helper()
+		require.Eventually(t, func() bool { return len(mailer.messages()) >= n }, 2*time.Second, 5*time.Millisecond)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/email-interviews", "crm-token", `{"topic":"feedback","address":"Ada <Ada@Example.org>"}`, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	waitFor(1)
+	first := mailer.messages()[0]
+	assert.Equal(t, "ada@example.org", first.Header.Get("To"))
+	questionID := "<vox." + created.ID + ".1@example.com>"
+	assert.Equal(t, questionID, first.Header.Get("Message-ID"))
+
+	t.Run("should reject replies without the inbound token", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/email/inbound", "message/rfc822", bytes.NewBufferString("From: ada@example.org\r\n\r\nhi"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("should ignore replies from anyone else", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, deliver(t, server, "grace@example.org", questionID, "Nothing."))
+		time.Sleep(50 * time.Millisecond)
+		assert.Len(t, mailer.messages(), 1)
+	})
+
+	assert.Equal(t, http.StatusOK, deliver(t, server, "Ada <ada@example.org>", questionID, "The reports."))
+	waitFor(2)
+	second := mailer.messages()[1]
+	assert.Equal(t, questionID, second.Header.Get("In-Reply-To"))
+	active, ok := repo.activeInterview(created.ID)
+	require.True(t, ok)
+	assert.Equal(t, domain.ViaEmail, active.Via)
+	assert.Equal(t, 2, active.Asked)
+	assert.Equal(t, "What would you change?", active.Pending)
+
+	assert.Equal(t, http.StatusOK, deliver(t, server, "ada@example.org", "<vox."+created.ID+".2@example.com>", "Faster exports."))
+	require.Eventually(t, func() bool { return s.activeCount() == 0 }, 2*time.Second, 5*time.Millisecond)
+
+	transcript, err := repo.GetTranscript("interview-1")
+	require.NoError(t, err)
+	require.Len(t, transcript.Entries, 2)
+	assert.Equal(t, "The reports.", transcript.Entries[0].Answer)
+	assert.Equal(t, "Faster exports.", transcript.Entries[1].Answer)
+	_, ok = repo.activeInterview(created.ID)
+	assert.False(t, ok)
+}
+
+func TestRestoreEmailInterviews(t *testing.T) {
+	s, repo, mailer, server := newTestEmailServer(t)
+	active := &domain.ActiveInterview{ID: "a1", TopicID: "feedback", UserID: "ada@example.org", Via: domain.ViaEmail, ChannelID: "ada@example.org", Asked: 1, Pending: "What do you like most?", StartedAt: time.Now()}
+	require.NoError(t, repo.SaveActiveInterview(active))
+	// Interviews in Slack are left to restoreSlackInterviews.
+	require.NoError(t, repo.SaveActiveInterview(&domain.ActiveInterview{ID: "slack", TopicID: "feedback", UserID: "U123", ChannelID: "C123"}))
+
+	s.restoreEmailInterviews()
+	assert.Equal(t, 1, s.activeCount())
+
+	// The reply to the question sent before the restart is taken, without the question being sent again, even
+	// though the provider doesn't ask it with the same text.
+	require.Eventually(t, func() bool {
+		return deliver(t, server, "ada@example.org", "<vox.a1.1@example.com>", "The reports.") == http.StatusOK
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return len(mailer.messages()) >= 1 }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, "<vox.a1.2@example.com>", mailer.messages()[0].Header.Get("Message-ID"))
+
+	assert.Equal(t, http.StatusOK, deliver(t, server, "ada@example.org", "<vox.a1.2@example.com>", "Faster exports."))
+	require.Eventually(t, func() bool { return s.activeCount() == 0 }, 2*time.Second, 5*time.Millisecond)
+	transcript, err := repo.GetTranscript("interview-1")
+	require.NoError(t, err)
+	require.Len(t, transcript.Entries, 2)
+	assert.Equal(t, "What do you like most?", transcript.Entries[0].Question)
+	assert.Equal(t, "The reports.", transcript.Entries[0].Answer)
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// eventTTL is how long an event ID is remembered. Slack retries an event it thinks wasn't acknowledged up to
+// three times, the last about five minutes after the first attempt.
+const eventTTL = time.Hour
+
+// eventLog remembers the IDs of the events received recently, so events that Slack retries are only handled
+// once.
+type eventLog struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	seen map[string]time.Time
+	// now returns the current time, and can be replaced in tests.
+	now func() time.Time
+}
+
+// newEventLog creates an eventLog that remembers each event ID for the ttl.
+func newEventLog(ttl time.Duration) *eventLog {
+	return &eventLog{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+// firstSeen records the event ID, reporting whether it is the first time it has been seen within the ttl.
+// Expired IDs are forgotten as new ones are recorded.
+func (l *eventLog) firstSeen(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for seenID, expires := range l.seen {
+		if !now.Before(expires) {
+			delete(l.seen, seenID)
+		}
+	}
+	if _, ok := l.seen[id]; ok {
+		return false
+	}
+	l.seen[id] = now.Add(l.ttl)
+	return true
+}
+
+// eventQueue handles the events in each conversation one at a time, in the order they arrived, so two quick
+// messages from a participant are answers in the order they sent them. Events in different conversations are
+// handled at the same time.
+type eventQueue struct {
+	mu sync.Mutex
+	// pending holds the events waiting in each conversation. A conversation is only in it while a worker is
+	// handling its events.
+	pending map[string][]func()
+}
+
+// newEventQueue creates an empty eventQueue.
+func newEventQueue() *eventQueue {
+	return &eventQueue{pending: make(map[string][]func())}
+}
+
+// push queues the handler behind the others in the conversation, starting a worker for the conversation if it
+// has none.
+func (q *eventQueue) push(key string, handle func()) {
+	q.mu.Lock()
+	pending, working := q.pending[key]
+	q.pending[key] = append(pending, handle)
+	q.mu.Unlock()
+	if !working {
+		go q.work(key)
+	}
+}
+
+// work handles the events queued in the conversation until there are none left.
+func (q *eventQueue) work(key string) {
+	for {
+		q.mu.Lock()
+		pending := q.pending[key]
+		if len(pending) == 0 {
+			delete(q.pending, key)
+			q.mu.Unlock()
+			return
+		}
+		q.pending[key] = pending[1:]
+		q.mu.Unlock()
+		pending[0]()
+	}
+}
+
+// conversationKey returns the conversation a callback event belongs to: the thread or channel a message was
+// sent in, or the channel of a reaction or app home.
+func conversationKey(eventsAPIEvent slackevents.EventsAPIEvent) string {
+	switch ev := eventsAPIEvent.InnerEvent.Data.(type) {
+	case *slackevents.MessageEvent:
+		return eventsAPIEvent.TeamID + "/" + threadKey(ev.Channel, ev.ThreadTimeStamp)
+	case *slackevents.ReactionAddedEvent:
+		return eventsAPIEvent.TeamID + "/" + ev.Item.Channel
+	case *slackevents.ReactionRemovedEvent:
+		return eventsAPIEvent.TeamID + "/" + ev.Item.Channel
+	case *slackevents.AppHomeOpenedEvent:
+		return eventsAPIEvent.TeamID + "/" + ev.Channel
+	}
+	return eventsAPIEvent.TeamID
+}
+
+// dispatchEvent queues a callback event to be handled in the background, unless it has already been handled.
+// It returns straight away, so Slack can be sent an acknowledgement before the event is handled.
+func (s *Server) dispatchEvent(eventsAPIEvent slackevents.EventsAPIEvent, retryAttempt int) {
+	if callback, ok := eventsAPIEvent.Data.(*slackevents.EventsAPICallbackEvent); ok && callback.EventID != "" {
+		if !s.events.firstSeen(callback.EventID) {
+			slog.Debug("Ignoring duplicate slack event", "event_id", callback.EventID, "retry_attempt", retryAttempt)
+			return
+		}
+	}
+	s.eventQueue.push(conversationKey(eventsAPIEvent), func() { s.handleCallbackEvent(eventsAPIEvent) })
+}
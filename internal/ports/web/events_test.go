@@ -0,0 +1,159 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLog(t *testing.T) {
+	now := time.Now()
+	log := newEventLog(time.Minute)
+	log.now = func() time.Time { return now }
+
+	assert.True(t, log.firstSeen("Ev1"))
+	assert.False(t, log.firstSeen("Ev1"))
+	assert.True(t, log.firstSeen("Ev2"))
+
+	now = now.Add(time.Minute)
+	assert.True(t, log.firstSeen("Ev1"), "event IDs should be forgotten after the ttl")
+	assert.Len(t, log.seen, 1)
+}
+
+func TestEventQueue(t *testing.T) {
+	queue := newEventQueue()
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []string
+	handle := func(name string) func() {
+		return func() {
+			if name == "first" {
+				<-release
+			}
+			mu.Lock()
+			handled = append(handled, name)
+			mu.Unlock()
+		}
+	}
+	handledNames := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), handled...)
+	}
+
+	queue.push("T1/C1", handle("first"))
+	queue.push("T1/C1", handle("second"))
+	queue.push("T1/C2", handle("other"))
+
+	t.Run("should handle other conversations while one is busy", func(t *testing.T) {
+		require.Eventually(t, func() bool { return len(handledNames()) == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"other"}, handledNames())
+	})
+
+	t.Run("should handle a conversation's events in the order they arrived", func(t *testing.T) {
+		close(release)
+		require.Eventually(t, func() bool { return len(handledNames()) == 3 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"other", "first", "second"}, handledNames())
+	})
+
+	t.Run("should stop the workers once the queues are empty", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			queue.mu.Lock()
+			defer queue.mu.Unlock()
+			return len(queue.pending) == 0
+		}, time.Second, time.Millisecond)
+	})
+}
+
+// replaySlackFixture sends the Slack payload in testdata/slack to the handler, signed as Slack would sign it.
+// If retry is set, the request is sent as that retry of an earlier attempt.
+func replaySlackFixture(t *testing.T, target, name, retry string) *http.Response {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "slack", name))
+	require.NoError(t, err)
+
+	req := signedSlackRequest(t, target, string(body))
+	req.Header.Set("Content-Type", "application/json")
+	if retry != "" {
+		req.Header.Set("X-Slack-Retry-Num", retry)
+		req.Header.Set("X-Slack-Retry-Reason", "http_timeout")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestSlackEventHandler(t *testing.T) {
+	s, _, _ := newTestServer(t)
+	s.signingSecret = testSigningSecret
+	server := httptest.NewServer(s.createSlackEventHandler())
+	t.Cleanup(server.Close)
+
+	direct := slack.New(nil, "D024BE91L", "U2147483697")
+	thread := slack.New(nil, "C024BE91L", "U2147483697", slack.WithThread("1355517500.000001"))
+	s.activeInterviews["U2147483697"] = direct
+	s.activeInterviews[threadKey("C024BE91L", "1355517500.000001")] = thread
+
+	// noAnswer checks that nothing more is passed to the interview.
+	noAnswer := func(t *testing.T, ui *slack.UI) {
+		t.Helper()
+		select {
+		case answer := <-ui.AnswerChan:
+			assert.Fail(t, "unexpected answer", answer)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	t.Run("should answer the URL verification challenge", func(t *testing.T) {
+		resp := replaySlackFixture(t, server.URL, "url_verification.json", "")
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P", string(body))
+	})
+
+	t.Run("should acknowledge messages before they are answered, and ignore retries", func(t *testing.T) {
+		// Nothing is waiting for an answer yet, so each request would hang if it were handled synchronously.
+		for _, retry := range []string{"", "1", "2"} {
+			resp := replaySlackFixture(t, server.URL, "message_im.json", retry)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		select {
+		case answer := <-direct.AnswerChan:
+			assert.Equal(t, "Mostly the speed of the search.", answer)
+		case <-time.After(time.Second):
+			require.Fail(t, "the message was not passed to the interview")
+		}
+		noAnswer(t, direct)
+	})
+
+	t.Run("should route replies in a thread to the interview held in it", func(t *testing.T) {
+		resp := replaySlackFixture(t, server.URL, "message_thread_reply.json", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		select {
+		case answer := <-thread.AnswerChan:
+			assert.Equal(t, "We export everything to a spreadsheet.", answer)
+		case <-time.After(time.Second):
+			require.Fail(t, "the reply was not passed to the interview")
+		}
+		noAnswer(t, direct)
+	})
+
+	t.Run("should ignore messages from bots", func(t *testing.T) {
+		resp := replaySlackFixture(t, server.URL, "message_bot.json", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		noAnswer(t, direct)
+	})
+}
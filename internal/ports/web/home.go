@@ -0,0 +1,365 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	goslack "github.com/slack-go/slack"
+)
+
+// Action IDs of the buttons on the App Home tab. Each button's value is the ID of the interview it acts on,
+// except for the link to an interview in progress.
+const (
+	actionHomeOpen   = "vox_home_open"
+	actionHomeView   = "vox_home_view"
+	actionHomeResume = "vox_home_resume"
+	actionHomeDelete = "vox_home_delete"
+)
+
+const (
+	// homeParticipantLimit is the number of their own interviews shown to a participant.
+	homeParticipantLimit = 10
+	// homeTopicLimit is the number of recent interviews shown to PMs for each topic.
+	homeTopicLimit = 3
+	// summaryPreviewLength is the number of characters of a summary shown on the App Home tab.
+	summaryPreviewLength = 200
+	// maxViewBlocks is the most blocks Slack accepts in a view.
+	maxViewBlocks = 100
+	// maxTextLength is the most characters Slack accepts in a section's text.
+	maxTextLength = 3000
+)
+
+// errInterviewInProgress is returned when a participant tries to resume an interview while they are taking
+// another one in a direct message.
+var errInterviewInProgress = errors.New("interview already in progress")
+
+// isPM reports whether the user is a member of one of the configured PM user groups.
+func (s *Server) isPM(userID string) bool {
+	for _, group := range s.config.Slack.PMGroups {
+		members, err := s.slackClient.GetUserGroupMembers(group)
+		if err != nil {
+			slog.Error("Error listing user group members", "error", err, "user_group", group)
+			continue
+		}
+		if slices.Contains(members, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// publishHome publishes the user's App Home tab.
+func (s *Server) publishHome(userID string) {
+	view, err := s.homeView(userID)
+	if err != nil {
+		slog.Error("Error building App Home", "error", err, "user_id", userID)
+		return
+	}
+	if _, err := s.slackClient.PublishView(userID, view, ""); err != nil {
+		slog.Error("Error publishing App Home", "error", err, "user_id", userID)
+	}
+}
+
+// homeView builds the App Home tab for the user. Everyone sees their own interviews, and PMs also see the
+// most recent interviews for each topic.
+func (s *Server) homeView(userID string) (goslack.HomeTabViewRequest, error) {
+	interviews, err := s.repo.ListInterviews()
+	if err != nil {
+		return goslack.HomeTabViewRequest{}, fmt.Errorf("could not list interviews: %w", err)
+	}
+	slices.SortFunc(interviews, compareInterviews)
+
+	blocks := []goslack.Block{goslack.NewHeaderBlock(plainText("Your interviews"))}
+	blocks = append(blocks, s.activeHomeBlocks(userID)...)
+
+	var own int
+	for _, interview := range interviews {
+		if interview.UserID != userID || own == homeParticipantLimit {
+			continue
+		}
+		own++
+
+		text := fmt.Sprintf("*%s*\n%s", s.interviewTopicName(interview), interviewStatus(interview))
+		blocks = append(blocks, goslack.NewSectionBlock(markdownText(text), nil, nil))
+
+		buttons := []goslack.BlockElement{goslack.NewButtonBlockElement(actionHomeView, interview.ID, plainText("View"))}
+		if interview.Abandoned {
+			buttons = append(buttons, goslack.NewButtonBlockElement(actionHomeResume, interview.ID, plainText("Resume")).WithStyle(goslack.StylePrimary))
+		}
+		remove := goslack.NewButtonBlockElement(actionHomeDelete, interview.ID, plainText("Delete")).WithStyle(goslack.StyleDanger)
+		remove.Confirm = goslack.NewConfirmationBlockObject(
+			plainText("Delete interview?"),
+			plainText("Your answers and the summary will be deleted. This can't be undone."),
+			plainText("Delete"),
+			plainText("Cancel"),
+		)
+		buttons = append(buttons, remove)
+		blocks = append(blocks, goslack.NewActionBlock("", buttons...))
+	}
+	if len(blocks) == 1 {
+		blocks = append(blocks, goslack.NewContextBlock("", markdownText("You haven't taken any interviews yet. Start one with `/vox interview start --topic <topic>`.")))
+	}
+
+	if len(s.config.Slack.PMGroups) > 0 && s.isPM(userID) {
+		blocks = append(blocks, s.topicHomeBlocks(interviews)...)
+	}
+
+	if len(blocks) > maxViewBlocks {
+		blocks = blocks[:maxViewBlocks]
+	}
+	return goslack.HomeTabViewRequest{Type: goslack.VTHomeTab, Blocks: goslack.Blocks{BlockSet: blocks}}, nil
+}
+
+// activeHomeBlocks lists the user's interviews in progress, with a link to the conversation each is held in.
+func (s *Server) activeHomeBlocks(userID string) []goslack.Block {
+	if s.progress == nil {
+		return nil
+	}
+	actives, err := s.progress.ListActiveInterviews()
+	if err != nil {
+		slog.Error("Error listing interviews in progress", "error", err)
+		return nil
+	}
+	slices.SortFunc(actives, func(a, b *domain.ActiveInterview) int { return b.StartedAt.Compare(a.StartedAt) })
+
+	var blocks []goslack.Block
+	for _, active := range actives {
+		if active.UserID != userID || active.Via != "" {
+			continue
+		}
+		name := active.TopicID
+		if topic := findTopic(s.config, active.TopicID); topic != nil {
+			name = topicName(topic)
+		}
+		text := fmt.Sprintf("*%s*\nIn progress, %d answered so far", name, len(active.Transcript.Entries))
+		open := goslack.NewButtonBlockElement(actionHomeOpen, "", plainText("Open"))
+		open.URL = "https://slack.com/app_redirect?channel=" + active.ChannelID
+		blocks = append(blocks, goslack.NewSectionBlock(markdownText(text), nil, goslack.NewAccessory(open)))
+	}
+	return blocks
+}
+
+// topicHomeBlocks lists the most recent interviews for each topic, with a preview of their summaries.
+func (s *Server) topicHomeBlocks(interviews []*domain.Interview) []goslack.Block {
+	blocks := []goslack.Block{goslack.NewDividerBlock(), goslack.NewHeaderBlock(plainText("Recent interviews"))}
+	for _, topic := range s.config.Interviews {
+		var recent []*domain.Interview
+		var total int
+		for _, interview := range interviews {
+			if interview.ProjectID != topic.ID {
+				continue
+			}
+			total++
+			if len(recent) < homeTopicLimit {
+				recent = append(recent, interview)
+			}
+		}
+
+		text := fmt.Sprintf("*%s* · %d interviews", topicName(&topic), total)
+		blocks = append(blocks, goslack.NewSectionBlock(markdownText(text), nil, nil))
+		for _, interview := range recent {
+			text := fmt.Sprintf("<@%s> · %s", interview.UserID, interviewStatus(interview))
+			if summary, err := s.repo.GetSummary(interview.ID); err == nil && summary.Text != "" {
+				text += "\n>" + truncate(strings.Join(strings.Fields(summary.Text), " "), summaryPreviewLength)
+			}
+			view := goslack.NewButtonBlockElement(actionHomeView, interview.ID, plainText("View"))
+			blocks = append(blocks, goslack.NewSectionBlock(markdownText(text), nil, goslack.NewAccessory(view)))
+		}
+	}
+	return blocks
+}
+
+// handleHomeActions handles the buttons pressed on the App Home tab. Participants can view, resume and delete
+// their own interviews, and PMs can view anyone's.
+func (s *Server) handleHomeActions(callback goslack.InteractionCallback) {
+	userID := callback.User.ID
+	var changed bool
+	for _, action := range callback.ActionCallback.BlockActions {
+		if action.ActionID == actionHomeOpen {
+			// Slack follows the link itself.
+			continue
+		}
+		interview, err := s.repo.GetInterview(action.Value)
+		if err != nil {
+			slog.Error("Error finding interview", "error", err, "interview_id", action.Value)
+			continue
+		}
+		own := interview.UserID == userID
+
+		switch {
+		case action.ActionID == actionHomeView && (own || s.isPM(userID)):
+			if err := s.openTranscript(callback.TriggerID, interview); err != nil {
+				slog.Error("Error showing interview", "error", err, "interview_id", interview.ID)
+			}
+		case action.ActionID == actionHomeResume && own && interview.Abandoned:
+			err := s.resumeInterview(interview)
+			if errors.Is(err, errInterviewInProgress) {
+				s.notify(userID, "You already have an interview in progress. Use `/vox interview cancel` to cancel it.")
+			} else if err != nil {
+				slog.Error("Error resuming interview", "error", err, "interview_id", interview.ID)
+			}
+			changed = true
+		case action.ActionID == actionHomeDelete && own:
+			if err := s.repo.DeleteInterview(interview.ID); err != nil {
+				slog.Error("Error deleting interview", "error", err, "interview_id", interview.ID)
+			}
+			changed = true
+		default:
+			slog.Warn("Ignoring App Home action the user isn't allowed to take", "action_id", action.ActionID, "user_id", userID, "interview_id", interview.ID)
+		}
+	}
+	if changed {
+		s.publishHome(userID)
+	}
+}
+
+// openTranscript shows the interview's summary and transcript in a modal.
+func (s *Server) openTranscript(triggerID string, interview *domain.Interview) error {
+	blocks, err := s.interviewBlocks(interview, true)
+	if err != nil {
+		return err
+	}
+	if len(blocks) > maxViewBlocks {
+		blocks = blocks[:maxViewBlocks]
+	}
+
+	_, err = s.slackClient.OpenView(triggerID, goslack.ModalViewRequest{
+		Type:   goslack.VTModal,
+		Title:  plainText("Interview"),
+		Close:  plainText("Close"),
+		Blocks: goslack.Blocks{BlockSet: blocks},
+	})
+	return err
+}
+
+// interviewBlocks describes the interview and its summary, followed by its transcript if withTranscript is
+// set.
+func (s *Server) interviewBlocks(interview *domain.Interview, withTranscript bool) ([]goslack.Block, error) {
+	summary, err := s.repo.GetSummary(interview.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get summary: %w", err)
+	}
+
+	byline := fmt.Sprintf("<@%s> · %s · %s", interview.UserID, s.interviewTopicName(interview), interviewStatus(interview))
+	blocks := []goslack.Block{goslack.NewContextBlock("", markdownText(byline))}
+	if summary.Text != "" {
+		blocks = append(blocks, goslack.NewSectionBlock(markdownText(truncate("*Summary*\n"+summary.Text, maxTextLength)), nil, nil))
+	}
+	if !withTranscript {
+		return blocks, nil
+	}
+
+	transcript, err := s.repo.GetTranscript(interview.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get transcript: %w", err)
+	}
+	blocks = append(blocks, goslack.NewDividerBlock())
+	for _, entry := range transcript.Entries {
+		text := fmt.Sprintf("*%s*\n%s", entry.Question, entry.Answer)
+		blocks = append(blocks, goslack.NewSectionBlock(markdownText(truncate(text, maxTextLength)), nil, nil))
+	}
+	return blocks, nil
+}
+
+// resumeInterview picks up an abandoned interview in a direct message with its participant, where it left
+// off. The abandoned interview is replaced by the resumed one once that has been saved.
+func (s *Server) resumeInterview(saved *domain.Interview) error {
+	topic := findTopic(s.config, saved.ProjectID)
+	if topic == nil {
+		return fmt.Errorf("topic '%s' not found", saved.ProjectID)
+	}
+	transcript, err := s.repo.GetTranscript(saved.ID)
+	if err != nil {
+		return fmt.Errorf("could not get transcript: %w", err)
+	}
+	channel, _, _, err := s.slackClient.OpenConversation(&goslack.OpenConversationParameters{Users: []string{saved.UserID}})
+	if err != nil {
+		return fmt.Errorf("could not open conversation: %w", err)
+	}
+
+	ui := slack.New(s.slackClient, slack.ChannelID(channel.ID), slack.UserID(saved.UserID), slackOptions(topic, slack.WithQuestionsAsked(len(transcript.Entries)))...)
+	if !s.claimInterview(saved.UserID, ui) {
+		return errInterviewInProgress
+	}
+	if err := ui.Say(fmt.Sprintf("Welcome back! Let's pick up your interview about *%s* where you left off.", topicName(topic))); err != nil {
+		s.mu.Lock()
+		delete(s.activeInterviews, saved.UserID)
+		s.mu.Unlock()
+		return err
+	}
+
+	active := &domain.ActiveInterview{
+		ID:         saved.UserID,
+		TopicID:    topic.ID,
+		UserID:     saved.UserID,
+		TeamID:     s.teamID,
+		ChannelID:  channel.ID,
+		Replaces:   saved.ID,
+		StartedAt:  time.Now(),
+		Transcript: *transcript,
+	}
+	go s.continueSlackInterview(active, ui, topic)
+	return nil
+}
+
+// notify sends the user a direct message.
+func (s *Server) notify(userID, text string) {
+	if _, _, err := s.slackClient.PostMessage(userID, goslack.MsgOptionText(text, false)); err != nil {
+		slog.Error("Error sending direct message", "error", err, "user_id", userID)
+	}
+}
+
+// interviewTopicName returns the name of the topic the interview was about, or its ID if the topic no longer
+// exists.
+func (s *Server) interviewTopicName(interview *domain.Interview) string {
+	if topic := findTopic(s.config, interview.ProjectID); topic != nil {
+		return topicName(topic)
+	}
+	return interview.ProjectID
+}
+
+// interviewStatus describes how the interview ended, and when, followed by any labels it has been triaged
+// with.
+func interviewStatus(interview *domain.Interview) string {
+	status := "Completed"
+	if interview.Abandoned {
+		status = "Abandoned"
+	}
+	status += " " + interview.CreatedAt.Format("2 Jan 2006")
+
+	var labels []string
+	for _, triage := range interview.Triage {
+		if !slices.Contains(labels, triage.Label) {
+			labels = append(labels, triage.Label)
+		}
+	}
+	if len(labels) > 0 {
+		status += " · " + strings.Join(labels, ", ")
+	}
+	return status
+}
+
+// truncate shortens the text to at most n characters, marking where it was cut.
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// plainText creates a plain text object for Block Kit.
+func plainText(text string) *goslack.TextBlockObject {
+	return goslack.NewTextBlockObject(goslack.PlainTextType, text, false, false)
+}
+
+// markdownText creates a markdown text object for Block Kit.
+func markdownText(text string) *goslack.TextBlockObject {
+	return goslack.NewTextBlockObject(goslack.MarkdownType, text, false, false)
+}
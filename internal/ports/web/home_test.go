@@ -0,0 +1,188 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	goslack "github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHomeServer creates a server with interviews by two participants, where members of the "S1" user
+// group are PMs.
+func newTestHomeServer(t *testing.T) (*Server, *memoryRepository, *fakeSlackAPI) {
+	t.Helper()
+	s, repo, _ := newTestServer(t)
+	api, client := newFakeSlackAPI(t)
+	s.slackClient = slack.NewDispatcher(client)
+	s.config.Slack.PMGroups = []string{"S1"}
+
+	save := func(id, userID string, abandoned bool, summary string, answers ...string) {
+		transcript := &domain.Transcript{}
+		for i, answer := range answers {
+			transcript.Entries = append(transcript.Entries, struct {
+				Question string `json:"question"`
+				Answer   string `json:"answer"`
+			}{Question: s.config.Interviews[0].Questions[i], Answer: answer})
+		}
+		interview := &domain.Interview{ID: id, UserID: userID, ProjectID: "feedback", CreatedAt: time.Now(), Abandoned: abandoned}
+		_, err := repo.SaveInterview(interview, transcript, &domain.Summary{Text: summary})
+		require.NoError(t, err)
+	}
+	save("completed", "U123", false, "Likes the speed.\nWould change nothing.", "The speed.", "Nothing.")
+	save("abandoned", "U123", true, "", "The search.")
+	save("other", "U456", false, "Wants an export button.", "The price.", "Exports.")
+	return s, repo, api
+}
+
+// homeAction creates the callback sent when the user presses a button on the App Home tab.
+func homeAction(userID, actionID, interviewID string) goslack.InteractionCallback {
+	var callback goslack.InteractionCallback
+	callback.Type = goslack.InteractionTypeBlockActions
+	callback.User.ID = userID
+	callback.TriggerID = "trigger-1"
+	callback.View.Type = goslack.VTHomeTab
+	callback.ActionCallback.BlockActions = []*goslack.BlockAction{{ActionID: actionID, Value: interviewID}}
+	return callback
+}
+
+func TestPublishHome(t *testing.T) {
+	t.Run("should show participants their own interviews", func(t *testing.T) {
+		s, _, api := newTestHomeServer(t)
+		s.publishHome("U123")
+
+		published := api.sent("views.publish")
+		require.Len(t, published, 1)
+		view := published[0].Get("body")
+		assert.Contains(t, view, `"user_id":"U123"`)
+		assert.Contains(t, view, "Completed")
+		assert.Contains(t, view, "Abandoned")
+		assert.Contains(t, view, `"action_id":"vox_home_resume","value":"abandoned"`)
+		assert.Contains(t, view, `"action_id":"vox_home_delete","value":"completed"`)
+		assert.NotContains(t, view, "other")
+		assert.NotContains(t, view, "Recent interviews")
+	})
+
+	t.Run("should show PMs recent interviews for each topic", func(t *testing.T) {
+		s, _, api := newTestHomeServer(t)
+		s.publishHome("UPM")
+
+		published := api.sent("views.publish")
+		require.Len(t, published, 1)
+		view := published[0].Get("body")
+		assert.Contains(t, view, "You haven't taken any interviews yet.")
+		assert.Contains(t, view, "Recent interviews")
+		assert.Contains(t, view, "*Product Feedback* · 3 interviews")
+		// The views are encoded as JSON, which escapes ">".
+		assert.Contains(t, view, `\u003eWants an export button.`)
+		assert.Contains(t, view, `\u003eLikes the speed. Would change nothing.`)
+		assert.Contains(t, view, `"action_id":"vox_home_view","value":"other"`)
+	})
+
+	t.Run("should be published when the App Home is opened", func(t *testing.T) {
+		s, _, api := newTestHomeServer(t)
+		s.signingSecret = testSigningSecret
+		server := httptest.NewServer(s.createSlackEventHandler())
+		t.Cleanup(server.Close)
+
+		resp := replaySlackFixture(t, server.URL, "app_home_opened.json", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Eventually(t, func() bool { return len(api.sent("views.publish")) == 1 }, time.Second, time.Millisecond)
+		assert.Contains(t, api.sent("views.publish")[0].Get("body"), `"user_id":"U2147483697"`)
+	})
+}
+
+func TestHandleHomeActions(t *testing.T) {
+	t.Run("should let participants delete their own interviews", func(t *testing.T) {
+		s, repo, api := newTestHomeServer(t)
+
+		s.handleBlockActions(homeAction("U456", actionHomeDelete, "completed"))
+		_, err := repo.GetInterview("completed")
+		assert.NoError(t, err, "participants can't delete other people's interviews")
+
+		s.handleBlockActions(homeAction("U123", actionHomeDelete, "completed"))
+		_, err = repo.GetInterview("completed")
+		assert.Error(t, err)
+		assert.Len(t, api.sent("views.publish"), 1, "the App Home should be refreshed")
+	})
+
+	t.Run("should show transcripts to their participants and PMs", func(t *testing.T) {
+		s, _, api := newTestHomeServer(t)
+
+		s.handleBlockActions(homeAction("U123", actionHomeView, "other"))
+		assert.Empty(t, api.sent("views.open"))
+
+		s.handleBlockActions(homeAction("UPM", actionHomeView, "other"))
+		opened := api.sent("views.open")
+		require.Len(t, opened, 1)
+		assert.Contains(t, opened[0].Get("body"), `"trigger_id":"trigger-1"`)
+		assert.Contains(t, opened[0].Get("body"), "Wants an export button.")
+		assert.Contains(t, opened[0].Get("body"), `*What would you change?*\nExports.`)
+	})
+
+	t.Run("should resume abandoned interviews where they left off", func(t *testing.T) {
+		s, repo, api := newTestHomeServer(t)
+
+		s.handleBlockActions(homeAction("U123", actionHomeResume, "abandoned"))
+		require.Eventually(t, func() bool { return s.findSlackInterview("U123", "D999", "") != nil }, time.Second, time.Millisecond)
+		_, err := repo.GetInterview("abandoned")
+		assert.NoError(t, err, "the abandoned interview should be kept until the resumed one is saved")
+
+		posts := api.sent("chat.postMessage")
+		require.NotEmpty(t, posts)
+		assert.Equal(t, "D999", posts[0].Get("channel"))
+		assert.Contains(t, posts[0].Get("text"), "Welcome back!")
+
+		s.handleCallbackEvent(threadMessage("U123", "D999", "", "Nothing."))
+		require.Eventually(t, func() bool { return s.activeCount() == 0 }, time.Second, time.Millisecond)
+
+		transcript, err := repo.GetTranscript("interview-1")
+		require.NoError(t, err)
+		require.Len(t, transcript.Entries, 2)
+		assert.Equal(t, "The search.", transcript.Entries[0].Answer)
+		assert.Equal(t, "Nothing.", transcript.Entries[1].Answer)
+		_, err = repo.GetInterview("abandoned")
+		assert.Error(t, err, "the abandoned interview should be replaced")
+	})
+
+	t.Run("should keep the attachments of a resumed interview", func(t *testing.T) {
+		s, repo, _ := newTestHomeServer(t)
+		blobID, err := repo.SaveBlob([]byte("\x89PNG"))
+		require.NoError(t, err)
+		transcript, err := repo.GetTranscript("abandoned")
+		require.NoError(t, err)
+		transcript.Attachments = []domain.Attachment{{Entry: 0, Name: "search.png", MimeType: "image/png", Size: 4, BlobID: blobID}}
+
+		s.handleBlockActions(homeAction("U123", actionHomeResume, "abandoned"))
+		require.Eventually(t, func() bool { return s.findSlackInterview("U123", "D999", "") != nil }, time.Second, time.Millisecond)
+		s.handleCallbackEvent(threadMessage("U123", "D999", "", "Nothing."))
+		require.Eventually(t, func() bool { return s.activeCount() == 0 }, time.Second, time.Millisecond)
+
+		_, err = repo.GetInterview("abandoned")
+		require.Error(t, err, "the abandoned interview should be replaced")
+		resumed, err := repo.GetTranscript("interview-1")
+		require.NoError(t, err)
+		require.Len(t, resumed.Attachments, 1)
+		assert.Equal(t, blobID, resumed.Attachments[0].BlobID)
+		data, err := repo.GetBlob(blobID)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("\x89PNG"), data)
+	})
+
+	t.Run("should not resume an interview for a participant with one in progress", func(t *testing.T) {
+		s, repo, api := newTestHomeServer(t)
+		require.True(t, s.claimInterview("U123", slack.New(s.slackClient, "D123", "U123")))
+
+		s.handleBlockActions(homeAction("U123", actionHomeResume, "abandoned"))
+		posts := api.sent("chat.postMessage")
+		require.Len(t, posts, 1)
+		assert.Contains(t, posts[0].Get("text"), "already have an interview in progress")
+		_, err := repo.GetInterview("abandoned")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	goslack "github.com/slack-go/slack"
+)
+
+// createInteractionHandler handles the Block Kit buttons pressed in Slack.
+func (s *Server) createInteractionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		verifier, err := goslack.NewSecretsVerifier(r.Header, s.signingSecret)
+		if err != nil {
+			// The signature headers are missing or out of date.
+			slog.Error("Error creating secrets verifier", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			slog.Error("Error reading request body", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		verifier.Write(body)
+		if err := verifier.Ensure(); err != nil {
+			slog.Error("Error verifying request signature", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewBuffer(body))
+		var callback goslack.InteractionCallback
+		if err := json.Unmarshal([]byte(r.FormValue("payload")), &callback); err != nil {
+			slog.Error("Error parsing interaction payload", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if callback.Type == goslack.InteractionTypeBlockActions {
+			go s.handleBlockActions(callback)
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// handleBlockActions routes the buttons pressed on a question to the participant's active interview, and
+// replaces the buttons with the participant's choice. Buttons on the App Home tab, and those that run a slash
+// command, are handled separately.
+func (s *Server) handleBlockActions(callback goslack.InteractionCallback) {
+	s, err := s.forTeam(callback.Team.ID)
+	if err != nil {
+		slog.Error("Ignoring interaction from workspace", "error", err, "team_id", callback.Team.ID)
+		return
+	}
+	if callback.View.Type == goslack.VTHomeTab {
+		s.handleHomeActions(callback)
+		return
+	}
+	if s.handleCommandActions(callback) || s.handleSummaryActions(callback) {
+		return
+	}
+
+	ui := s.findSlackInterview(callback.User.ID, callback.Channel.ID, callback.Message.ThreadTimestamp)
+	if ui == nil {
+		slog.Debug("No active interview found for user", "user_id", callback.User.ID)
+		return
+	}
+
+	for _, action := range callback.ActionCallback.BlockActions {
+		if !ui.HandleAction(action.ActionID, action.BlockID, action.Value) {
+			slog.Debug("Ignoring action on a question that isn't waiting for an answer", "action_id", action.ActionID, "block_id", action.BlockID)
+			continue
+		}
+
+		if callback.ResponseURL == "" {
+			continue
+		}
+		var choice string
+		switch {
+		case action.ActionID == slack.ActionSkip:
+			choice = "_Skipped_"
+		case action.ActionID == slack.ActionWrapUp:
+			choice = "_Wrapping up_"
+		case strings.HasPrefix(action.ActionID, slack.ActionChoice):
+			choice = action.Value
+		}
+		err := goslack.PostWebhook(callback.ResponseURL, &goslack.WebhookMessage{
+			Text:            callback.Message.Text + "\n> " + choice,
+			ReplaceOriginal: true,
+		})
+		if err != nil {
+			slog.Error("Error replacing question message", "error", err, "user_id", callback.User.ID)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	goslack "github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningSecret = "test-signing-secret"
+
+// fakeSlackClient records the messages posted to Slack.
+type fakeSlackClient struct {
+	mu    sync.Mutex
+	posts []url.Values
+}
+
+func (f *fakeSlackClient) PostMessage(channelID string, options ...goslack.MsgOption) (string, string, error) {
+	_, values, err := goslack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return "", "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.posts = append(f.posts, values)
+	return channelID, strconv.Itoa(len(f.posts)), nil
+}
+
+// posted returns the messages posted so far.
+func (f *fakeSlackClient) posted() []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]url.Values(nil), f.posts...)
+}
+
+// signedSlackRequest creates a request signed with the test signing secret, as Slack would send it.
+func signedSlackRequest(t *testing.T, target, body string) *http.Request {
+	t.Helper()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSigningSecret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req, err := http.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestInteractionHandler(t *testing.T) {
+	s, _, _ := newTestServer(t)
+	s.signingSecret = testSigningSecret
+	server := httptest.NewServer(s.createInteractionHandler())
+	t.Cleanup(server.Close)
+
+	replaced := make(chan goslack.WebhookMessage, 1)
+	responseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg goslack.WebhookMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		replaced <- msg
+	}))
+	t.Cleanup(responseServer.Close)
+
+	client := &fakeSlackClient{}
+	ui := slack.New(client, "D123", "U123")
+	s.activeInterviews["U123"] = ui
+
+	payload := func(actionID, blockID, value string) string {
+		b, err := json.Marshal(map[string]any{
+			"type":         "block_actions",
+			"user":         map[string]string{"id": "U123"},
+			"channel":      map[string]string{"id": "D123"},
+			"response_url": responseServer.URL,
+			"message":      map[string]string{"text": "Which plan? (Free / Pro)"},
+			"actions":      []map[string]string{{"action_id": actionID, "block_id": blockID, "value": value}},
+		})
+		require.NoError(t, err)
+		return url.Values{"payload": {string(b)}}.Encode()
+	}
+
+	t.Run("should reject requests that aren't signed", func(t *testing.T) {
+		resp, err := http.Post(server.URL, "application/x-www-form-urlencoded", strings.NewReader(payload(slack.ActionSkip, "vox_question_1_controls", "skip")))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("should answer the question with the button pressed", func(t *testing.T) {
+		answers := make(chan string, 1)
+		go func() {
+			answer, _ := ui.AskQuestion(interview.ParseQuestion("Which plan? [Free | Pro]"))
+			answers <- answer
+		}()
+		require.Eventually(t, func() bool { return len(client.posted()) == 1 }, time.Second, time.Millisecond)
+		assert.Contains(t, client.posted()[0].Get("blocks"), `"value":"Pro"`)
+
+		// The question may not be waiting for an answer straight after it was posted.
+		var answer string
+		require.Eventually(t, func() bool {
+			resp, err := http.DefaultClient.Do(signedSlackRequest(t, server.URL, payload(slack.ActionChoice+"_1", "vox_question_1", "Pro")))
+			require.NoError(t, err)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			select {
+			case answer = <-answers:
+				return true
+			case <-time.After(10 * time.Millisecond):
+				return false
+			}
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, "Pro", answer)
+
+		select {
+		case msg := <-replaced:
+			assert.True(t, msg.ReplaceOriginal)
+			assert.Equal(t, "Which plan? (Free / Pro)\n> Pro", msg.Text)
+		case <-time.After(time.Second):
+			require.Fail(t, "the question was not replaced with the answer")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/andrewhowdencom/vox/internal/domain/invite"
+	"github.com/google/uuid"
+)
+
+// registerInviteRoutes adds the handlers for interviews taken through an invite link.
+func (s *Server) registerInviteRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /invite/{token}", s.createInvitePageHandler())
+	mux.HandleFunc("POST /invite/{token}/sessions", s.createInviteSessionHandler())
+}
+
+// createInvitePageHandler serves the interview page for an invite, recording that it was opened.
+func (s *Server) createInvitePageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.PathValue("token")
+		inv, err := s.invites.Open(token)
+		if err != nil {
+			writeInviteError(w, err)
+			return
+		}
+
+		topic := findTopic(s.config, inv.TopicID)
+		if topic == nil {
+			slog.Warn("Invite refers to a topic that no longer exists", "invite_id", inv.ID, "topic_id", inv.TopicID)
+			http.NotFound(w, r)
+			return
+		}
+
+		renderInterviewPage(w, interviewPageData{Topic: topic, StartURL: "/invite/" + url.PathEscape(token) + "/sessions"})
+	}
+}
+
+// createInviteSessionHandler starts an interview with an invite, using up one of its uses.
+func (s *Server) createInviteSessionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inv, err := s.invites.Start(r.PathValue("token"))
+		if err != nil {
+			writeInviteError(w, err)
+			return
+		}
+
+		topic := findTopic(s.config, inv.TopicID)
+		if topic == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		userID := inv.Participant
+		if userID == "" {
+			userID = "invite:" + inv.ID
+		}
+		attributes := maps.Clone(inv.Attributes)
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+		attributes["invite_id"] = inv.ID
+
+		done := func(err error) {
+			if err != nil {
+				return
+			}
+			if err := s.invites.Complete(inv.ID); err != nil {
+				slog.Error("Error recording completed invite", "error", err, "invite_id", inv.ID)
+			}
+		}
+
+		ui, err := s.startSession(topic, uuid.NewString(), "", userID, done, interview.WithAttributes(attributes))
+		if err != nil {
+			slog.Error("Error starting invited interview", "error", err, "invite_id", inv.ID)
+			http.Error(w, "could not start the interview", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"id": ui.ID})
+	}
+}
+
+// writeInviteError responds with the status matching an invite error.
+func writeInviteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, invite.ErrInvalidToken):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, invite.ErrExpired), errors.Is(err, invite.ErrRevoked), errors.Is(err, invite.ErrUsedUp):
+		http.Error(w, err.Error(), http.StatusGone)
+	default:
+		slog.Error("Error using invite", "error", err)
+		http.Error(w, "could not open the invite", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,140 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/session"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/invite"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *memoryRepository) SaveInvite(inv *domain.Invite) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.invites == nil {
+		m.invites = make(map[string]domain.Invite)
+	}
+	m.invites[inv.ID] = *inv
+	return nil
+}
+
+func (m *memoryRepository) GetInvite(id string) (*domain.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.invites[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &inv, nil
+}
+
+func (m *memoryRepository) ListInvites() ([]*domain.Invite, error) { return nil, nil }
+
+func (m *memoryRepository) UpdateInvite(id string, update func(*domain.Invite) error) (*domain.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.invites[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	inv.Events = append([]domain.InviteEvent(nil), inv.Events...)
+	if err := update(&inv); err != nil {
+		return nil, err
+	}
+	m.invites[id] = inv
+	return &inv, nil
+}
+
+func TestInviteInterview(t *testing.T) {
+	s, repo, _ := newTestServer(t)
+	signer, err := invite.NewSigner(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.NoError(t, err)
+	s.invites = invite.NewService(repo, signer)
+
+	mux := http.NewServeMux()
+	s.registerBrowserRoutes(mux)
+	s.registerInviteRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// Invites work for topics that are not open to everyone.
+	inv, token, err := s.invites.Create("internal", "ada@example.com", map[string]string{"company": "Acme"}, time.Hour, 1)
+	require.NoError(t, err)
+
+	t.Run("should reject tampered tokens", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/invite/" + inv.ID + ".bogus")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should run an interview for the invited participant", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/invite/" + token)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), "/invite/"+token+"/sessions")
+
+		resp, err = http.Post(server.URL+"/invite/"+token+"/sessions", "application/json", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created struct{ ID string }
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		resp.Body.Close()
+
+		ui := s.session(created.ID, "")
+		require.NotNil(t, ui)
+		_, err = ui.Wait(t.Context(), 0)
+		require.NoError(t, err)
+		require.NoError(t, ui.Answer("Yes."))
+		var last session.Event
+		for {
+			events, err := ui.Wait(t.Context(), last.ID)
+			require.NoError(t, err)
+			if len(events) == 0 {
+				break
+			}
+			last = events[len(events)-1]
+		}
+		assert.Equal(t, session.EventEnd, last.Type)
+
+		interview, err := repo.GetInterview("interview-1")
+		require.NoError(t, err)
+		assert.Equal(t, "ada@example.com", interview.UserID)
+		assert.Equal(t, map[string]string{"company": "Acme", "invite_id": inv.ID}, interview.Attributes)
+
+		require.Eventually(t, func() bool {
+			stored, err := repo.GetInvite(inv.ID)
+			return err == nil && stored.Status() == domain.InviteCompleted
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("should only allow a single use", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/invite/"+token+"/sessions", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+	})
+
+	t.Run("should reject revoked invites", func(t *testing.T) {
+		inv, token, err := s.invites.Create("internal", "", nil, time.Hour, 0)
+		require.NoError(t, err)
+		require.NoError(t, s.invites.Revoke(inv.ID))
+
+		resp, err := http.Get(server.URL + "/invite/" + token)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+	})
+}
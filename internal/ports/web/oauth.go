@@ -0,0 +1,245 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/redaction"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	goslack "github.com/slack-go/slack"
+)
+
+const (
+	// oauthStateCookie holds the state sent to Slack when an installation starts, so the callback can check
+	// that it was started here.
+	oauthStateCookie = "vox_slack_oauth_state"
+	// oauthStateTTL is how long an installation can take.
+	oauthStateTTL = 10 * time.Minute
+	// slackAuthorizeURL is where workspace admins approve the installation.
+	slackAuthorizeURL = "https://slack.com/oauth/v2/authorize"
+)
+
+// botScopes are the scopes vox asks for when it is installed.
+var botScopes = []string{
+	"commands",
+	"chat:write",
+	"im:write",
+	"im:history",
+	"channels:history",
+	"groups:history",
+	"usergroups:read",
+	"files:read",
+	"files:write",
+	"reactions:read",
+}
+
+// errNotInstalled is returned for a workspace that hasn't installed vox.
+var errNotInstalled = errors.New("vox is not installed in the workspace")
+
+// slackOAuth installs vox in Slack workspaces with OAuth, and keeps a client for each workspace that has
+// installed it.
+type slackOAuth struct {
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	// sealer encrypts the bot tokens in the repository.
+	sealer        *redaction.Sealer
+	installations storage.InstallationRepository
+	// httpClient exchanges the code Slack sends to the callback for a token, and options configure the
+	// client of each workspace.
+	httpClient *http.Client
+	options    []goslack.Option
+
+	mu      sync.Mutex
+	clients map[string]slack.AppClient
+}
+
+// newSlackOAuth creates the OAuth flow for the settings, storing installations in the repository.
+func newSlackOAuth(cfg *config.Config, installations storage.InstallationRepository) (*slackOAuth, error) {
+	settings := cfg.Slack.OAuth
+	sealer, err := redaction.NewSealer(settings.TokenKey)
+	if err != nil {
+		return nil, fmt.Errorf("slack.oauth.token_key: %w", err)
+	}
+	return &slackOAuth{
+		clientID:      settings.ClientID,
+		clientSecret:  settings.ClientSecret,
+		redirectURL:   settings.RedirectURL,
+		sealer:        sealer,
+		installations: installations,
+		httpClient:    http.DefaultClient,
+		clients:       make(map[string]slack.AppClient),
+	}, nil
+}
+
+// client returns the client for the workspace, using the bot token it was given when it installed vox. Each
+// workspace has its own queue, as Slack rate limits each workspace separately.
+func (o *slackOAuth) client(teamID string) (slack.AppClient, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if client, ok := o.clients[teamID]; ok {
+		return client, nil
+	}
+
+	installation, err := o.installations.GetInstallation(teamID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", errNotInstalled, teamID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get installation: %w", err)
+	}
+	token, err := o.sealer.Open(installation.BotToken)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt bot token: %w", err)
+	}
+	client := slack.NewDispatcher(goslack.New(token, o.options...))
+	o.clients[teamID] = client
+	return client, nil
+}
+
+// install exchanges the code Slack sent to the callback for a bot token, and saves the installation.
+func (o *slackOAuth) install(r *http.Request, code string) (*domain.Installation, error) {
+	resp, err := goslack.GetOAuthV2ResponseContext(r.Context(), o.httpClient, o.clientID, o.clientSecret, code, o.redirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not exchange code: %w", err)
+	}
+	token, err := o.sealer.Seal(resp.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("could not encrypt bot token: %w", err)
+	}
+
+	installation := &domain.Installation{
+		TeamID:      resp.Team.ID,
+		TeamName:    resp.Team.Name,
+		BotUserID:   resp.BotUserID,
+		BotToken:    token,
+		Scope:       resp.Scope,
+		InstalledBy: resp.AuthedUser.ID,
+		InstalledAt: time.Now(),
+	}
+	if err := o.installations.SaveInstallation(installation); err != nil {
+		return nil, fmt.Errorf("could not save installation: %w", err)
+	}
+
+	// A reinstall comes with a new token.
+	o.mu.Lock()
+	delete(o.clients, installation.TeamID)
+	o.mu.Unlock()
+	return installation, nil
+}
+
+// uninstall forgets the workspace, once vox has been removed from it or its tokens have been revoked.
+func (o *slackOAuth) uninstall(teamID string) error {
+	o.mu.Lock()
+	delete(o.clients, teamID)
+	o.mu.Unlock()
+	return o.installations.DeleteInstallation(teamID)
+}
+
+// registerOAuthRoutes adds the handlers for the "Add to Slack" flow.
+func (s *Server) registerOAuthRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /slack/install", s.startInstall)
+	mux.HandleFunc("GET /slack/oauth/callback", s.finishInstall)
+}
+
+// startInstall sends the user to Slack to approve installing vox in their workspace.
+func (s *Server) startInstall(w http.ResponseWriter, r *http.Request) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		slog.Error("Error generating OAuth state", "error", err)
+		http.Error(w, "Could not start the installation", http.StatusInternalServerError)
+		return
+	}
+	state := base64.RawURLEncoding.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/slack/oauth",
+		MaxAge:   int(oauthStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := url.Values{
+		"client_id": {s.oauth.clientID},
+		"scope":     {strings.Join(botScopes, ",")},
+		"state":     {state},
+	}
+	if s.oauth.redirectURL != "" {
+		query.Set("redirect_uri", s.oauth.redirectURL)
+	}
+	http.Redirect(w, r, slackAuthorizeURL+"?"+query.Encode(), http.StatusFound)
+}
+
+// finishInstall handles Slack sending the user back once they have approved the installation, or declined it.
+func (s *Server) finishInstall(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(query.Get("state"))) != 1 {
+		slog.Warn("Rejecting Slack installation with an unexpected state")
+		http.Error(w, "The installation has expired. Please try again.", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oauthStateCookie, Path: "/slack/oauth", MaxAge: -1})
+
+	if reason := query.Get("error"); reason != "" {
+		slog.Info("Slack installation was declined", "reason", reason)
+		http.Error(w, "vox was not installed.", http.StatusBadRequest)
+		return
+	}
+
+	installation, err := s.oauth.install(r, query.Get("code"))
+	if err != nil {
+		slog.Error("Error installing in Slack", "error", err)
+		http.Error(w, "Could not install vox. Please try again.", http.StatusBadGateway)
+		return
+	}
+	slog.Info("Installed in Slack workspace", "team_id", installation.TeamID, "team_name", installation.TeamName, "user_id", installation.InstalledBy)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "vox is installed in %s. Start an interview with /vox interview start --topic <topic>.\n", installation.TeamName)
+}
+
+// forTeam returns the server as seen from a Slack workspace, using the workspace's client. Everything else is
+// shared with s. Workspaces that haven't installed vox with OAuth use the bot token, if there is one.
+func (s *Server) forTeam(teamID string) (*Server, error) {
+	if s.oauth == nil {
+		return s, nil
+	}
+	client, err := s.oauth.client(teamID)
+	if errors.Is(err, errNotInstalled) && s.slackClient != nil {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	team := *s
+	team.slackClient = client
+	team.teamID = teamID
+	return &team, nil
+}
+
+// uninstall forgets a workspace that has removed vox, or revoked its tokens.
+func (s *Server) uninstall(teamID string) {
+	if s.oauth == nil {
+		return
+	}
+	if err := s.oauth.uninstall(teamID); err != nil {
+		slog.Error("Error removing Slack installation", "error", err, "team_id", teamID)
+		return
+	}
+	slog.Info("Uninstalled from Slack workspace", "team_id", teamID)
+}
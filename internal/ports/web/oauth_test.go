@@ -0,0 +1,209 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	goslack "github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *memoryRepository) SaveInstallation(installation *domain.Installation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.teams == nil {
+		m.teams = make(map[string]domain.Installation)
+	}
+	m.teams[installation.TeamID] = *installation
+	return nil
+}
+
+func (m *memoryRepository) GetInstallation(teamID string) (*domain.Installation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	installation, ok := m.teams[teamID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &installation, nil
+}
+
+func (m *memoryRepository) DeleteInstallation(teamID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.teams, teamID)
+	return nil
+}
+
+// apiTransport sends requests meant for slack.com to the fake Slack API instead.
+type apiTransport struct {
+	target *url.URL
+}
+
+func (t apiTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.URL.Path = strings.TrimPrefix(r.URL.Path, "/api")
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// newTestOAuthServer creates a server that workspaces can install with OAuth, and that has no bot token of its
+// own.
+func newTestOAuthServer(t *testing.T) (*Server, *memoryRepository, *fakeSlackAPI, *httptest.Server) {
+	t.Helper()
+	s, repo, _ := newTestServer(t)
+	api, _ := newFakeSlackAPI(t)
+
+	cfg := &config.Config{}
+	cfg.Slack.OAuth.ClientID = "123.456"
+	cfg.Slack.OAuth.ClientSecret = "client-secret"
+	cfg.Slack.OAuth.RedirectURL = "https://vox.example.com/slack/oauth/callback"
+	cfg.Slack.OAuth.TokenKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	oauth, err := newSlackOAuth(cfg, repo)
+	require.NoError(t, err)
+	target, err := url.Parse(api.url)
+	require.NoError(t, err)
+	oauth.httpClient = &http.Client{Transport: apiTransport{target: target}}
+	oauth.options = []goslack.Option{goslack.OptionAPIURL(api.url + "/")}
+	s.oauth = oauth
+
+	mux := http.NewServeMux()
+	s.registerOAuthRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, repo, api, server
+}
+
+// noRedirects stops the client following redirects, so they can be checked.
+var noRedirects = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+// install goes through the "Add to Slack" flow, as a user approving the installation would.
+func install(t *testing.T, server *httptest.Server) *http.Response {
+	t.Helper()
+	resp, err := noRedirects.Get(server.URL + "/slack/install")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/slack/oauth/callback?code=abc&state="+location.Query().Get("state"), nil)
+	for _, cookie := range resp.Cookies() {
+		req.AddCookie(cookie)
+	}
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+func TestSlackOAuth(t *testing.T) {
+	t.Run("should send the user to Slack to approve the installation", func(t *testing.T) {
+		_, _, _, server := newTestOAuthServer(t)
+
+		resp, err := noRedirects.Get(server.URL + "/slack/install")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "slack.com", location.Host)
+		assert.Equal(t, "123.456", location.Query().Get("client_id"))
+		assert.Contains(t, location.Query().Get("scope"), "commands")
+		assert.Equal(t, "https://vox.example.com/slack/oauth/callback", location.Query().Get("redirect_uri"))
+		require.Len(t, resp.Cookies(), 1)
+		assert.Equal(t, location.Query().Get("state"), resp.Cookies()[0].Value)
+	})
+
+	t.Run("should save the workspace's bot token encrypted", func(t *testing.T) {
+		s, repo, api, server := newTestOAuthServer(t)
+
+		resp := install(t, server)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		exchanges := api.sent("oauth.v2.access")
+		require.Len(t, exchanges, 1)
+		assert.Equal(t, "abc", exchanges[0].Get("code"))
+
+		installation, err := repo.GetInstallation("T0ACME")
+		require.NoError(t, err)
+		assert.Equal(t, "Acme", installation.TeamName)
+		assert.Equal(t, "U123", installation.InstalledBy)
+		assert.NotContains(t, installation.BotToken, "xoxb-acme")
+		token, err := s.oauth.sealer.Open(installation.BotToken)
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-acme", token)
+	})
+
+	t.Run("should reject callbacks that weren't started here", func(t *testing.T) {
+		_, repo, api, server := newTestOAuthServer(t)
+
+		resp, err := http.Get(server.URL + "/slack/oauth/callback?code=abc&state=forged")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Empty(t, api.sent("oauth.v2.access"))
+		_, err = repo.GetInstallation("T0ACME")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestSlackOAuth_Routing(t *testing.T) {
+	s, repo, api, server := newTestOAuthServer(t)
+	require.Equal(t, http.StatusOK, install(t, server).StatusCode)
+
+	t.Run("should answer each workspace with its own bot token", func(t *testing.T) {
+		command := slashCommand(api, "U123", "interview start --topic missing")
+		command.TeamID = "T0ACME"
+		s.handleSlashCommand(command)
+
+		ephemerals := api.sent("chat.postEphemeral")
+		require.Len(t, ephemerals, 1)
+		assert.Equal(t, "xoxb-acme", ephemerals[0].Get("token"))
+	})
+
+	t.Run("should ignore workspaces that haven't installed vox", func(t *testing.T) {
+		command := slashCommand(api, "U123", "interview start --topic missing")
+		command.TeamID = "T0OTHER"
+		s.handleSlashCommand(command)
+		assert.Len(t, api.sent("chat.postEphemeral"), 1)
+	})
+
+	t.Run("should record the workspace of interviews in progress", func(t *testing.T) {
+		team, err := s.forTeam("T0ACME")
+		require.NoError(t, err)
+		command := goslack.SlashCommand{TeamID: "T0ACME", UserID: "U123", ChannelID: "C123"}
+		go team.startThreadInterview(command, findTopic(s.config, "feedback"), "", false)
+
+		key := threadKey("C123", "1700000000.000100")
+		require.Eventually(t, func() bool { _, ok := repo.activeInterview(key); return ok }, time.Second, time.Millisecond)
+		active, _ := repo.activeInterview(key)
+		assert.Equal(t, "T0ACME", active.TeamID)
+		team.cancelSlackInterviews(command)
+		require.Eventually(t, func() bool { return s.activeCount() == 0 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("should forget workspaces that uninstall vox", func(t *testing.T) {
+		s.handleCallbackEvent(slackevents.EventsAPIEvent{
+			Type:       slackevents.CallbackEvent,
+			TeamID:     "T0ACME",
+			InnerEvent: slackevents.EventsAPIInnerEvent{Data: &slackevents.AppUninstalledEvent{Type: "app_uninstalled"}},
+		})
+
+		_, err := repo.GetInstallation("T0ACME")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = s.forTeam("T0ACME")
+		assert.ErrorIs(t, err, errNotInstalled)
+	})
+}
@@ -0,0 +1,260 @@
+package web
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+)
+
+// Page sizes for listing interviews.
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// errInvalidCursor is returned for a cursor that was not issued by the server.
+var errInvalidCursor = errors.New("the cursor is not valid")
+
+// interviewList is a page of interviews in an API response.
+type interviewList struct {
+	Interviews []*domain.Interview `json:"interviews"`
+	// NextCursor fetches the next page. It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// feedRecord is a single line of the interview feed.
+type feedRecord struct {
+	Interview  *domain.Interview  `json:"interview"`
+	Transcript *domain.Transcript `json:"transcript"`
+	Summary    *domain.Summary    `json:"summary"`
+}
+
+// registerRepositoryRoutes adds the read-only handlers for the stored interviews.
+func (s *Server) registerRepositoryRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/interviews", s.authenticate(scopeInterviewsRead, s.listAPIInterviews))
+	mux.HandleFunc("GET /api/v1/interviews/feed", s.authenticate(scopeInterviewsRead, s.streamAPIInterviews))
+	mux.HandleFunc("GET /api/v1/interviews/{id}", s.authenticate(scopeInterviewsRead, s.getAPIInterview))
+	mux.HandleFunc("GET /api/v1/interviews/{id}/transcript", s.authenticate(scopeInterviewsRead, s.getAPITranscript))
+	mux.HandleFunc("GET /api/v1/interviews/{id}/summary", s.authenticate(scopeInterviewsRead, s.getAPIInterviewSummary))
+}
+
+// listAPIInterviews returns a page of the interviews that match the filters in the query, newest first.
+func (s *Server) listAPIInterviews(w http.ResponseWriter, r *http.Request, _ config.APIToken) {
+	query := r.URL.Query()
+	filter, err := parseInterviewFilter(query)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := defaultPageSize
+	if v := query.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxPageSize))
+			return
+		}
+	}
+
+	interviews, err := s.matchingInterviews(filter)
+	if err != nil {
+		slog.Error("Error listing interviews", "error", err)
+		writeAPIError(w, http.StatusInternalServerError, "could not list interviews")
+		return
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		createdAt, id, err := decodeCursor(v)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Skip everything up to and including the last interview of the previous page.
+		start, found := slices.BinarySearchFunc(interviews, &domain.Interview{ID: id, CreatedAt: createdAt}, compareInterviews)
+		if found {
+			start++
+		}
+		interviews = interviews[start:]
+	}
+
+	page := interviewList{Interviews: interviews}
+	if len(interviews) > limit {
+		page.Interviews = interviews[:limit]
+		page.NextCursor = encodeCursor(page.Interviews[limit-1])
+	}
+	if page.Interviews == nil {
+		page.Interviews = []*domain.Interview{}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// streamAPIInterviews writes every interview that matches the filters in the query as newline-delimited JSON,
+// with its transcript and summary.
+func (s *Server) streamAPIInterviews(w http.ResponseWriter, r *http.Request, _ config.APIToken) {
+	filter, err := parseInterviewFilter(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	interviews, err := s.matchingInterviews(filter)
+	if err != nil {
+		slog.Error("Error listing interviews", "error", err)
+		writeAPIError(w, http.StatusInternalServerError, "could not list interviews")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	encoder := json.NewEncoder(w)
+	for _, interview := range interviews {
+		if r.Context().Err() != nil {
+			return
+		}
+
+		record := feedRecord{Interview: interview}
+		if record.Transcript, err = s.repo.GetTranscript(interview.ID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			// The response has already started, so the stream can only be cut short.
+			slog.Error("Error getting transcript for feed", "error", err, "interview_id", interview.ID)
+			return
+		}
+		if record.Summary, err = s.repo.GetSummary(interview.ID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			slog.Error("Error getting summary for feed", "error", err, "interview_id", interview.ID)
+			return
+		}
+
+		if err := encoder.Encode(record); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// getAPIInterview returns the metadata of a stored interview.
+func (s *Server) getAPIInterview(w http.ResponseWriter, r *http.Request, _ config.APIToken) {
+	interview, err := s.repo.GetInterview(r.PathValue("id"))
+	writeRecord(w, interview, err)
+}
+
+// getAPITranscript returns the transcript of a stored interview.
+func (s *Server) getAPITranscript(w http.ResponseWriter, r *http.Request, _ config.APIToken) {
+	transcript, err := s.repo.GetTranscript(r.PathValue("id"))
+	writeRecord(w, transcript, err)
+}
+
+// getAPIInterviewSummary returns the summary of a stored interview.
+func (s *Server) getAPIInterviewSummary(w http.ResponseWriter, r *http.Request, _ config.APIToken) {
+	summary, err := s.repo.GetSummary(r.PathValue("id"))
+	writeRecord(w, summary, err)
+}
+
+// writeRecord writes a record read from the repository, or the error that occurred reading it.
+func writeRecord(w http.ResponseWriter, record any, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		writeAPIError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		slog.Error("Error reading from repository", "error", err)
+		writeAPIError(w, http.StatusInternalServerError, "could not read from the repository")
+	default:
+		writeJSON(w, http.StatusOK, record)
+	}
+}
+
+// matchingInterviews returns the stored interviews that match the filter, newest first.
+func (s *Server) matchingInterviews(filter storage.InterviewFilter) ([]*domain.Interview, error) {
+	all, err := s.repo.ListInterviews()
+	if err != nil {
+		return nil, err
+	}
+
+	var interviews []*domain.Interview
+	for _, interview := range all {
+		if filter.Matches(interview) {
+			interviews = append(interviews, interview)
+		}
+	}
+	slices.SortFunc(interviews, compareInterviews)
+	return interviews, nil
+}
+
+// compareInterviews orders interviews newest first. Interviews created at the same time are ordered by ID, so
+// the order is stable between requests.
+func compareInterviews(a, b *domain.Interview) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(b.ID, a.ID)
+}
+
+// parseInterviewFilter reads the filters from a query. Times are in RFC 3339 format, and each attribute is
+// given as key=value.
+func parseInterviewFilter(query url.Values) (storage.InterviewFilter, error) {
+	filter := storage.InterviewFilter{
+		ProjectID: query.Get("project"),
+		UserID:    query.Get("user"),
+	}
+
+	for name, t := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an RFC 3339 time, such as 2025-01-31T00:00:00Z", name)
+		}
+		*t = parsed
+	}
+
+	for _, attr := range query["attr"] {
+		k, v, ok := strings.Cut(attr, "=")
+		if !ok || k == "" {
+			return filter, fmt.Errorf("attr must be in the form key=value, not %q", attr)
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[k] = v
+	}
+	return filter, nil
+}
+
+// encodeCursor creates a cursor that continues after the interview.
+func encodeCursor(interview *domain.Interview) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(interview.CreatedAt.UnixNano(), 10) + ":" + interview.ID))
+}
+
+// decodeCursor returns the creation time and ID of the interview that a cursor continues after.
+func decodeCursor(cursor string) (time.Time, string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", errInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(b), ":")
+	if !ok {
+		return time.Time{}, "", errInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", errInvalidCursor
+	}
+	return time.Unix(0, n), id, nil
+}
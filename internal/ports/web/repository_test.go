@@ -0,0 +1,170 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepositoryServer creates a test server with five stored interviews, one created each day from the
+// first of March, alternating between two projects.
+func newTestRepositoryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s, repo, _ := newTestServer(t)
+	s.config.API.Tokens = []config.APIToken{
+		{Name: "analytics", Token: "analytics-token", Scopes: []string{scopeInterviewsRead}},
+		{Name: "portal", Token: "portal-token", Scopes: []string{scopeSessionsWrite}},
+	}
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		project := "feedback"
+		if i%2 == 1 {
+			project = "discovery"
+		}
+		interview := &domain.Interview{
+			ID:         fmt.Sprintf("interview-%d", i+1),
+			UserID:     fmt.Sprintf("user-%d", i+1),
+			ProjectID:  project,
+			CreatedAt:  start.Add(time.Duration(i) * 24 * time.Hour),
+			Attributes: map[string]string{"plan": []string{"free", "pro"}[i%2]},
+		}
+		transcript := &domain.Transcript{InterviewID: interview.ID}
+		transcript.Entries = append(transcript.Entries, struct {
+			Question string `json:"question"`
+			Answer   string `json:"answer"`
+		}{Question: "What do you like?", Answer: "Answer " + interview.ID})
+		_, err := repo.SaveInterview(interview, transcript, &domain.Summary{InterviewID: interview.ID, Text: "Summary " + interview.ID})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	s.registerAPIRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRepositoryAPI_List(t *testing.T) {
+	server := newTestRepositoryServer(t)
+
+	t.Run("should require the interviews:read scope", func(t *testing.T) {
+		resp := apiRequest(t, http.MethodGet, server.URL+"/api/v1/interviews", "portal-token", "", nil)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("should page through interviews newest first", func(t *testing.T) {
+		var ids []string
+		var pages int
+		url := server.URL + "/api/v1/interviews?limit=2"
+		for {
+			var page interviewList
+			resp := apiRequest(t, http.MethodGet, url, "analytics-token", "", &page)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			pages++
+			for _, interview := range page.Interviews {
+				ids = append(ids, interview.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			url = server.URL + "/api/v1/interviews?limit=2&cursor=" + page.NextCursor
+		}
+		assert.Equal(t, 3, pages)
+		assert.Equal(t, []string{"interview-5", "interview-4", "interview-3", "interview-2", "interview-1"}, ids)
+	})
+
+	t.Run("should filter interviews", func(t *testing.T) {
+		testCases := []struct {
+			query string
+			want  []string
+		}{
+			{query: "project=discovery", want: []string{"interview-4", "interview-2"}},
+			{query: "user=user-3", want: []string{"interview-3"}},
+			{query: "since=2025-03-02T00:00:00Z&until=2025-03-04T00:00:00Z", want: []string{"interview-3", "interview-2"}},
+			{query: "attr=plan=free&project=feedback", want: []string{"interview-5", "interview-3", "interview-1"}},
+			{query: "project=missing", want: nil},
+		}
+
+		for _, tc := range testCases {
+			var page interviewList
+			resp := apiRequest(t, http.MethodGet, server.URL+"/api/v1/interviews?"+tc.query, "analytics-token", "", &page)
+			require.Equal(t, http.StatusOK, resp.StatusCode, tc.query)
+			var ids []string
+			for _, interview := range page.Interviews {
+				ids = append(ids, interview.ID)
+			}
+			assert.Equal(t, tc.want, ids, tc.query)
+		}
+	})
+
+	t.Run("should reject invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"limit=0", "limit=1000", "since=yesterday", "attr=plan", "cursor=nope"} {
+			var body map[string]string
+			resp := apiRequest(t, http.MethodGet, server.URL+"/api/v1/interviews?"+query, "analytics-token", "", &body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+			assert.NotEmpty(t, body["error"], query)
+		}
+	})
+}
+
+func TestRepositoryAPI_Get(t *testing.T) {
+	server := newTestRepositoryServer(t)
+
+	var interview domain.Interview
+	resp := apiRequest(t, http.MethodGet, server.URL+"/api/v1/interviews/interview-2", "analytics-token", "", &interview)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "discovery", interview.ProjectID)
+
+	var transcript domain.Transcript
+	resp = apiRequest(t, http.MethodGet, server.URL+"/api/v1/interviews/interview-2/transcript", "analytics-token", "", &transcript)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, transcript.Entries, 1)
+	assert.Equal(t, "Answer interview-2", transcript.Entries[0].Answer)
+
+	var summary domain.Summary
+	resp = apiRequest(t, http.MethodGet, server.URL+"/api/v1/interviews/interview-2/summary", "analytics-token", "", &summary)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Summary interview-2", summary.Text)
+
+	for _, path := range []string{"/api/v1/interviews/missing", "/api/v1/interviews/missing/transcript", "/api/v1/interviews/missing/summary"} {
+		resp := apiRequest(t, http.MethodGet, server.URL+path, "analytics-token", "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
+
+func TestRepositoryAPI_Feed(t *testing.T) {
+	server := newTestRepositoryServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/interviews/feed?project=feedback", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer analytics-token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var records []feedRecord
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var record feedRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, records, 3)
+	assert.Equal(t, "interview-5", records[0].Interview.ID)
+	assert.Equal(t, "Answer interview-5", records[0].Transcript.Entries[0].Answer)
+	assert.Equal(t, "Summary interview-5", records[0].Summary.Text)
+}
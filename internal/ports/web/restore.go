@@ -0,0 +1,138 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+)
+
+// progressCheckpointer saves the progress of an interview in Slack or over email after each answer.
+type progressCheckpointer struct {
+	store  storage.ActiveInterviewRepository
+	active *domain.ActiveInterview
+}
+
+// Checkpoint saves the answers given so far. The question just answered is no longer pending.
+func (c *progressCheckpointer) Checkpoint(transcript *domain.Transcript) error {
+	c.active.Transcript = *transcript
+	c.active.Pending = ""
+	c.active.UpdatedAt = time.Now()
+	return c.store.SaveActiveInterview(c.active)
+}
+
+// continueSlackInterview runs an interview in Slack, which must already be the interview in progress under
+// active.ID, picking up after any answers already in its transcript. Its progress is saved after each answer,
+// so it can be restored by restoreSlackInterviews if the server restarts part way through.
+func (s *Server) continueSlackInterview(active *domain.ActiveInterview, ui *slack.UI, topic *config.Topic) {
+	var opts []interview.Option
+	if s.progress != nil {
+		if err := s.progress.SaveActiveInterview(active); err != nil {
+			slog.Error("Error saving interview progress", "error", err, "user_id", ui.UserID)
+		}
+		opts = append(opts, interview.WithCheckpointer(&progressCheckpointer{store: s.progress, active: active}))
+	}
+	if len(active.Transcript.Entries) > 0 {
+		opts = append(opts, interview.WithTranscript(&active.Transcript))
+	}
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.activeInterviews, active.ID)
+		s.mu.Unlock()
+		ui.Close()
+		if s.progress != nil {
+			if err := s.progress.DeleteActiveInterview(active.ID); err != nil {
+				slog.Error("Error removing interview progress", "error", err, "user_id", ui.UserID)
+			}
+		}
+		slog.Info("Interview finished for user", "user_id", ui.UserID, "channel_id", ui.ChannelID, "thread_ts", ui.ThreadTS)
+	}()
+
+	slog.Info("Starting interview for user", "user_id", ui.UserID, "channel_id", ui.ChannelID, "thread_ts", ui.ThreadTS)
+	interviewToRun, err := s.newInterview(topic, ui, opts...)
+	if err != nil {
+		slog.Error("Error creating interview", "error", err)
+		return
+	}
+	err = interviewToRun.Run(string(ui.UserID), topic.ID)
+	switch {
+	case errors.Is(err, interview.ErrCancelled):
+		slog.Info("Interview cancelled", "user_id", ui.UserID)
+	case err != nil:
+		slog.Error("Error running interview", "error", err, "user_id", ui.UserID)
+	case active.Replaces != "":
+		// The abandoned interview is only removed now the one picking it up has been saved, so the answers
+		// aren't lost if it doesn't finish.
+		if err := s.repo.DeleteInterview(active.Replaces); err != nil {
+			slog.Error("Error removing abandoned interview", "error", err, "interview_id", active.Replaces)
+		}
+	}
+}
+
+// restoreSlackInterviews picks up the interviews in Slack that were in progress when the server last stopped.
+// Each participant is told the interview is carrying on, then asked the next question.
+func (s *Server) restoreSlackInterviews() {
+	actives, err := s.progress.ListActiveInterviews()
+	if err != nil {
+		slog.Error("Error listing interviews in progress", "error", err)
+		return
+	}
+
+	for _, active := range actives {
+		if active.Via != "" {
+			continue
+		}
+		topic := findTopic(s.config, active.TopicID)
+		if topic == nil {
+			slog.Warn("Discarding interview in progress for a topic that no longer exists", "topic_id", active.TopicID, "user_id", active.UserID)
+			s.discardActiveInterview(active)
+			continue
+		}
+		team, err := s.forTeam(active.TeamID)
+		if err != nil {
+			slog.Warn("Discarding interview in progress in a workspace vox is no longer installed in", "error", err, "team_id", active.TeamID, "user_id", active.UserID)
+			s.discardActiveInterview(active)
+			continue
+		}
+
+		opts := slackOptions(topic, slack.WithQuestionsAsked(len(active.Transcript.Entries)))
+		if active.ThreadTS != "" {
+			opts = append(opts, slack.WithThread(active.ThreadTS))
+		}
+		for _, observer := range active.Observers {
+			opts = append(opts, slack.WithObserver(slack.ChannelID(observer)))
+		}
+		ui := slack.New(team.slackClient, slack.ChannelID(active.ChannelID), slack.UserID(active.UserID), opts...)
+		if !s.claimInterview(active.ID, ui) {
+			slog.Warn("Not restoring interview, as another is already in progress", "user_id", active.UserID)
+			continue
+		}
+
+		text := fmt.Sprintf("Sorry, where were we? vox restarted part way through your interview about *%s*, so let's pick up where we left off.", topicName(topic))
+		if err := ui.Say(text); err != nil {
+			slog.Error("Discarding interview in progress that could not be restored", "error", err, "user_id", active.UserID)
+			s.mu.Lock()
+			delete(s.activeInterviews, active.ID)
+			s.mu.Unlock()
+			s.discardActiveInterview(active)
+			continue
+		}
+
+		slog.Info("Restoring interview in progress", "user_id", active.UserID, "topic_id", active.TopicID, "answers", len(active.Transcript.Entries))
+		go team.continueSlackInterview(active, ui, topic)
+	}
+}
+
+// discardActiveInterview removes an interview in progress that can't be restored.
+func (s *Server) discardActiveInterview(active *domain.ActiveInterview) {
+	if err := s.progress.DeleteActiveInterview(active.ID); err != nil {
+		slog.Error("Error removing interview progress", "error", err, "user_id", active.UserID)
+	}
+}
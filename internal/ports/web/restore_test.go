@@ -0,0 +1,86 @@
+package web
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *memoryRepository) SaveActiveInterview(active *domain.ActiveInterview) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.actives == nil {
+		m.actives = make(map[string]domain.ActiveInterview)
+	}
+	m.actives[active.ID] = *active
+	return nil
+}
+
+func (m *memoryRepository) ListActiveInterviews() ([]*domain.ActiveInterview, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var actives []*domain.ActiveInterview
+	for _, active := range slices.Collect(maps.Values(m.actives)) {
+		actives = append(actives, &active)
+	}
+	return actives, nil
+}
+
+func (m *memoryRepository) DeleteActiveInterview(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.actives, id)
+	return nil
+}
+
+// activeInterview returns the saved progress of the interview, if any.
+func (m *memoryRepository) activeInterview(id string) (domain.ActiveInterview, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	active, ok := m.actives[id]
+	return active, ok
+}
+
+func TestRestoreSlackInterviews(t *testing.T) {
+	s, repo, _ := newTestServer(t)
+	api, client := newFakeSlackAPI(t)
+	s.slackClient = slack.NewDispatcher(client)
+
+	key := threadKey("C123", "1.1")
+	active := &domain.ActiveInterview{ID: key, TopicID: "feedback", UserID: "U123", ChannelID: "C123", ThreadTS: "1.1", StartedAt: time.Now()}
+	active.Transcript.Entries = append(active.Transcript.Entries, struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}{Question: "What do you like?", Answer: "The speed."})
+	require.NoError(t, repo.SaveActiveInterview(active))
+	require.NoError(t, repo.SaveActiveInterview(&domain.ActiveInterview{ID: "U456", TopicID: "retired", UserID: "U456", ChannelID: "D456"}))
+
+	s.restoreSlackInterviews()
+
+	_, ok := repo.activeInterview("U456")
+	assert.False(t, ok, "interviews for topics that no longer exist should be discarded")
+
+	require.Eventually(t, func() bool { return s.findSlackInterview("U123", "C123", "1.1") != nil }, time.Second, time.Millisecond)
+	posts := api.sent("chat.postMessage")
+	require.NotEmpty(t, posts)
+	assert.Contains(t, posts[0].Get("text"), "Sorry, where were we?")
+	assert.Equal(t, "1.1", posts[0].Get("thread_ts"))
+
+	s.handleCallbackEvent(threadMessage("U123", "C123", "1.1", "Nothing."))
+
+	require.Eventually(t, func() bool {
+		_, ok := repo.activeInterview(key)
+		return !ok
+	}, time.Second, time.Millisecond)
+	transcript, err := repo.GetTranscript("interview-1")
+	require.NoError(t, err)
+	require.Len(t, transcript.Entries, 2)
+	assert.Equal(t, "The speed.", transcript.Entries[0].Answer)
+	assert.Equal(t, "Nothing.", transcript.Entries[1].Answer)
+}
@@ -2,78 +2,206 @@ package web
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
-	"github.com/andrewhowdencom/vox/internal/config"
-	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/andrewhowdencom/vox/internal/adapters/providers/fallback"
 	"github.com/andrewhowdencom/vox/internal/adapters/providers/gemini"
+	"github.com/andrewhowdencom/vox/internal/adapters/providers/openai"
 	"github.com/andrewhowdencom/vox/internal/adapters/providers/static"
 	"github.com/andrewhowdencom/vox/internal/adapters/storage/bbolt"
-	"github.com/andrewhowdencom/vox/internal/domain/storage"
 	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain/interview"
+	"github.com/andrewhowdencom/vox/internal/domain/invite"
+	"github.com/andrewhowdencom/vox/internal/domain/lint"
+	"github.com/andrewhowdencom/vox/internal/domain/redaction"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
 
 	goslack "github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 // Server is the HTTP server for the web port.
 type Server struct {
-	slackClient      *goslack.Client
-	signingSecret    string
-	apiKey           string
-	config           *config.Config
-	activeInterviews map[string]*slack.UI
-	mu               sync.Mutex
-	repo             storage.Repository
+	// slackClient queues messages so bursts of them, such as when a topic launches, stay within Slack's rate
+	// limits.
+	slackClient slack.AppClient
+	// botClient uses the configured bot token. Unlike slackClient, it isn't replaced by forTeam.
+	botClient slack.AppClient
+	// teamID is the Slack workspace the server is acting for, if it was installed there with OAuth. See
+	// forTeam.
+	teamID string
+	// oauth is nil unless workspaces can install vox with OAuth.
+	oauth *slackOAuth
+	// socketMode is set when Slack events are received over Socket Mode instead of webhooks.
+	socketMode    *socketmode.Client
+	signingSecret string
+	apiKey        string
+	config        *config.Config
+	// activeInterviews are the interviews in progress in chat tools, keyed by the conversation they are held
+	// in. See threadKey, teamsKey, discordKey, emailKey and smsKey.
+	activeInterviews map[string]chatInterview
+	sessions         map[string]*remoteSession
+	// mu is shared with the copies made by forTeam.
+	mu   *sync.Mutex
+	repo storage.Repository
+	// invites is nil if invite links are not configured.
+	invites *invite.Service
+	// progress saves the interviews in Slack and over email that are in progress, so they survive a restart.
+	// It is nil if they aren't saved.
+	progress storage.ActiveInterviewRepository
+	// cards records the summary cards posted to Slack, so reactions to them can triage the interview. It is
+	// nil if they aren't recorded.
+	cards storage.SummaryCardRepository
+	// blobs stores the files participants send with their answers. It is nil if only their names are kept.
+	blobs storage.BlobRepository
+	// events remembers the Slack events handled recently, so retries are ignored.
+	events *eventLog
+	// eventQueue handles the events and messages in each conversation, from Slack, Teams or by SMS, in the order
+	// they arrived.
+	eventQueue *eventQueue
+	// teams is nil unless interviews can be held in Microsoft Teams.
+	teams *teamsBot
+	// discord is nil unless interviews can be held in Discord.
+	discord *discordBot
+	// email is nil unless interviews can be held over email.
+	email *emailChannel
+	// sms is nil unless interviews can be held by text message and WhatsApp.
+	sms *smsChannel
+	// optOuts records the numbers that have opted out of messages. It is nil if opt-outs aren't recorded.
+	optOuts storage.OptOutRepository
+	// health is shared by every interview, so a provider that is down is skipped by new interviews too.
+	health *fallback.Health
 }
 
 // NewServeCmd creates a new cobra command for the "serve" command.
 func NewServeCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "serve",
-		Short: "Starts a server to handle Slack events",
-		Long:  `Starts a server to handle Slack events and run interviews.`,
+		Short: "Starts a server to handle Slack events and browser interviews",
+		Long: `Starts a server to handle Slack events and run interviews. Topics with browser enabled can also be
+taken in a web browser at /interview/<topic>, and interviews can be held in Microsoft Teams, Discord, over
+email, and by text message and WhatsApp when the teams, discord, email and sms sections of the configuration are
+set.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			port := viper.GetInt("port")
 			botToken := viper.GetString("slack-bot-token")
 			signingSecret := viper.GetString("slack-signing-secret")
+			socketMode := viper.GetBool("slack-socket-mode")
+			appToken := viper.GetString("slack-app-token")
 			apiKey := viper.GetString("api-key")
 
-			if botToken == "" || signingSecret == "" {
-				slog.Error("slack-bot-token and slack-signing-secret are required")
-				os.Exit(1)
-			}
-
 			var cfg config.Config
 			if err := viper.Unmarshal(&cfg); err != nil {
 				slog.Error("Error unmarshalling config", "error", err)
 				os.Exit(1)
 			}
 
+			// With OAuth, each workspace that installs vox has its own bot token.
+			oauth := cfg.Slack.OAuth.ClientID != ""
+			switch {
+			case socketMode && (appToken == "" || (botToken == "" && !oauth)):
+				slog.Error("slack-socket-mode requires slack-app-token, and slack-bot-token or slack.oauth")
+				os.Exit(1)
+			case !socketMode && (botToken != "" || oauth) != (signingSecret != ""):
+				slog.Error("slack-signing-secret must be set with slack-bot-token or slack.oauth")
+				os.Exit(1)
+			}
+
 			repo, err := bbolt.NewRepository()
 			if err != nil {
 				slog.Error("could not create repository", "error", err)
 				os.Exit(1)
 			}
 
-			slackClient := goslack.New(botToken)
+			var invites *invite.Service
+			if cfg.Invites.Key != "" {
+				signer, err := invite.NewSigner(cfg.Invites.Key)
+				if err != nil {
+					slog.Error("could not create invite signer", "error", err)
+					os.Exit(1)
+				}
+				invites = invite.NewService(repo, signer)
+			}
+
+			var slackClient slack.AppClient
+			if botToken != "" {
+				slackClient = slack.NewDispatcher(goslack.New(botToken))
+			}
+			var socketModeClient *socketmode.Client
+			if socketMode {
+				socketModeClient = socketmode.New(goslack.New(botToken, goslack.OptionAppLevelToken(appToken)))
+			}
+
+			var installer *slackOAuth
+			if oauth {
+				installer, err = newSlackOAuth(&cfg, repo)
+				if err != nil {
+					slog.Error("could not configure Slack OAuth", "error", err)
+					os.Exit(1)
+				}
+			}
+
+			var teamsBot *teamsBot
+			if cfg.Teams.AppID != "" {
+				teamsBot = newTeamsBot(&cfg)
+			}
+
+			var discordBot *discordBot
+			if cfg.Discord.ApplicationID != "" {
+				discordBot, err = newDiscordBot(&cfg)
+				if err != nil {
+					slog.Error("could not configure Discord", "error", err)
+					os.Exit(1)
+				}
+			}
+
+			var emailChannel *emailChannel
+			if cfg.Email.From != "" {
+				emailChannel = newEmailChannel(&cfg)
+			}
+
+			var smsChannel *smsChannel
+			if cfg.SMS.AccountSID != "" {
+				smsChannel = newSMSChannel(&cfg)
+			}
 
 			server := &Server{
 				slackClient:      slackClient,
+				botClient:        slackClient,
+				socketMode:       socketModeClient,
 				signingSecret:    signingSecret,
 				apiKey:           apiKey,
 				config:           &cfg,
-				activeInterviews: make(map[string]*slack.UI),
+				oauth:            installer,
+				activeInterviews: make(map[string]chatInterview),
+				sessions:         make(map[string]*remoteSession),
+				mu:               &sync.Mutex{},
 				repo:             repo,
+				invites:          invites,
+				progress:         repo,
+				cards:            repo,
+				blobs:            repo,
+				events:           newEventLog(eventTTL),
+				eventQueue:       newEventQueue(),
+				teams:            teamsBot,
+				discord:          discordBot,
+				email:            emailChannel,
+				sms:              smsChannel,
+				optOuts:          repo,
+				health:           fallback.NewHealth(fallback.DefaultCooldown, fallback.DefaultMaxCooldown),
 			}
 
 			server.Run(port)
@@ -83,6 +211,8 @@ func NewServeCmd() *cobra.Command {
 	cmd.Flags().Int("port", 8080, "The port to listen on for Slack events")
 	cmd.Flags().String("slack-bot-token", "", "The Slack bot token")
 	cmd.Flags().String("slack-signing-secret", "", "The Slack signing secret")
+	cmd.Flags().Bool("slack-socket-mode", false, "Receive Slack events over Socket Mode, so no public URL is needed")
+	cmd.Flags().String("slack-app-token", "", "The Slack app-level token, required for Socket Mode")
 	cmd.Flags().String("api-key", "", "The API key for the gemini provider")
 	viper.BindPFlags(cmd.Flags())
 
@@ -91,9 +221,51 @@ func NewServeCmd() *cobra.Command {
 
 // Run starts the HTTP server.
 func (s *Server) Run(port int) {
-	http.HandleFunc("/slack/events", s.createSlackEventHandler())
-	http.HandleFunc("/slack/commands", s.createSlashCommandHandler())
-
+	slackEnabled := s.slackClient != nil || s.oauth != nil
+	switch {
+	case s.socketMode != nil:
+		go s.runSocketMode(context.Background(), s.socketMode)
+	case slackEnabled:
+		http.HandleFunc("/slack/events", s.createSlackEventHandler())
+		http.HandleFunc("/slack/commands", s.createSlashCommandHandler())
+		http.HandleFunc("/slack/interactions", s.createInteractionHandler())
+	default:
+		slog.Info("Slack is not configured, so interviews can't be held there")
+	}
+	if s.oauth != nil {
+		s.registerOAuthRoutes(http.DefaultServeMux)
+	}
+	if slackEnabled && s.progress != nil {
+		s.restoreSlackInterviews()
+	}
+	s.registerBrowserRoutes(http.DefaultServeMux)
+	if s.teams != nil {
+		s.registerTeamsRoutes(http.DefaultServeMux)
+	}
+	if s.discord != nil {
+		if err := s.discord.client.RegisterCommands(s.discord.applicationID, discordCommands); err != nil {
+			slog.Error("Error registering Discord commands", "error", err)
+		}
+		s.registerDiscordRoutes(http.DefaultServeMux)
+	}
+	if s.email != nil {
+		s.registerEmailRoutes(http.DefaultServeMux)
+		if s.progress != nil {
+			s.restoreEmailInterviews()
+		}
+		if s.email.imap != nil {
+			go s.watchEmail(context.Background())
+		}
+	}
+	if s.sms != nil {
+		s.registerSMSRoutes(http.DefaultServeMux)
+	}
+	if s.invites != nil {
+		s.registerInviteRoutes(http.DefaultServeMux)
+	}
+	if len(s.config.API.Tokens) > 0 {
+		s.registerAPIRoutes(http.DefaultServeMux)
+	}
 
 	slog.Info("Server starting", "port", port)
 	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil); err != nil {
@@ -137,9 +309,21 @@ func (s *Server) createSlashCommandHandler() http.HandlerFunc {
 }
 
 func (s *Server) handleSlashCommand(command goslack.SlashCommand) {
+	s, err := s.forTeam(command.TeamID)
+	if err != nil {
+		slog.Error("Ignoring slash command from workspace", "error", err, "team_id", command.TeamID)
+		return
+	}
+	s.runSlashCommand(command, false)
+}
+
+// runSlashCommand parses and runs the slash command. If replace is set, the output of commands that respond
+// with a message replaces the message the command was run from, such as when moving to the next page of a list.
+func (s *Server) runSlashCommand(command goslack.SlashCommand, replace bool) {
 	slog.Debug("Handling slash command", "command", command.Command, "text", command.Text, "user_id", command.UserID, "channel_id", command.ChannelID)
 
-	var topicID string
+	var topicID, participant string
+	var inThread, observe bool
 	var interviewCmd = &cobra.Command{Use: "interview"}
 	var startCmd = &cobra.Command{
 		Use: "start",
@@ -152,32 +336,19 @@ func (s *Server) handleSlashCommand(command goslack.SlashCommand) {
 				return
 			}
 
-			var selectedTopic *config.Topic
-			for i, t := range s.config.Interviews {
-				if strings.EqualFold(t.ID, topicID) {
-					selectedTopic = &s.config.Interviews[i]
-					break
-				}
-			}
-
+			selectedTopic := findTopic(s.config, topicID)
 			if selectedTopic == nil {
 				slog.Warn("Topic not found", "topic_id", topicID)
 				s.slackClient.PostEphemeral(command.ChannelID, command.UserID, goslack.MsgOptionText(fmt.Sprintf("Error: topic '%s' not found", topicID), false))
 				return
 			}
 
-			s.mu.Lock()
-			if _, ok := s.activeInterviews[command.UserID]; ok {
-				slog.Warn("Interview already in progress for user", "user_id", command.UserID)
-				s.slackClient.PostEphemeral(command.ChannelID, command.UserID, goslack.MsgOptionText("You already have an interview in progress.", false))
-				s.mu.Unlock()
+			if inThread {
+				s.startThreadInterview(command, selectedTopic, participant, observe)
 				return
 			}
-			s.mu.Unlock()
-
-			questionProvider, err := newQuestionProvider(s.config, selectedTopic, s.apiKey, viper.GetString("model"))
-			if err != nil {
-				slog.Error("Error creating question provider", "error", err)
+			if participant != "" || observe {
+				s.slackClient.PostEphemeral(command.ChannelID, command.UserID, goslack.MsgOptionText("--participant and --observe can only be used with --thread.", false))
 				return
 			}
 
@@ -190,31 +361,35 @@ func (s *Server) handleSlashCommand(command goslack.SlashCommand) {
 			}
 			slog.Debug("Conversation opened", "channel_id", channel.ID)
 
-			ui := slack.New(s.slackClient, slack.ChannelID(channel.ID), slack.UserID(command.UserID))
-			s.mu.Lock()
-			s.activeInterviews[command.UserID] = ui
-			s.mu.Unlock()
-
-			defer func() {
-				s.mu.Lock()
-				delete(s.activeInterviews, command.UserID)
-				s.mu.Unlock()
-				slog.Info("Interview finished for user", "user_id", command.UserID)
-			}()
-
-			slog.Info("Starting interview for user", "user_id", command.UserID)
-			interviewToRun := interview.NewInterview(questionProvider, ui, s.repo)
-			if err := interviewToRun.Run(command.UserID, topicID); err != nil {
-				slog.Error("Error running interview", "error", err, "user_id", command.UserID)
+			ui := slack.New(s.slackClient, slack.ChannelID(channel.ID), slack.UserID(command.UserID), slackOptions(selectedTopic)...)
+			if !s.claimInterview(command.UserID, ui) {
+				slog.Warn("Interview already in progress for user", "user_id", command.UserID)
+				s.slackClient.PostEphemeral(command.ChannelID, command.UserID, goslack.MsgOptionText("You already have an interview in progress. Use `/vox interview cancel` to cancel it.", false))
+				return
 			}
+			s.runSlackInterview(command.UserID, ui, selectedTopic)
 		},
 	}
 	startCmd.Flags().StringVar(&topicID, "topic", "", "The ID of the interview topic")
+	startCmd.Flags().BoolVar(&inThread, "thread", false, "Hold the interview in a thread in this channel, instead of a direct message")
+	startCmd.Flags().StringVar(&participant, "participant", "", "Who to interview in the thread, such as @ada (defaults to you)")
+	startCmd.Flags().BoolVar(&observe, "observe", false, "Send yourself a link to the thread, and the summary when it finishes")
 	interviewCmd.AddCommand(startCmd)
 
+	var cancelCmd = &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel your interviews in progress, without saving them",
+		Run: func(cmd *cobra.Command, args []string) {
+			s.cancelSlackInterviews(command)
+		},
+	}
+	interviewCmd.AddCommand(cancelCmd)
+	interviewCmd.AddCommand(s.newSlackRepositoryCmd(command, replace))
+
 	// Create a root command to mimic the actual command structure for parsing
 	rootCmd := &cobra.Command{Use: "vox"}
 	rootCmd.AddCommand(interviewCmd)
+	rootCmd.AddCommand(s.newSlackTopicsCmd(command, replace))
 	rootCmd.SilenceErrors = true
 	rootCmd.SilenceUsage = true
 
@@ -227,6 +402,9 @@ func (s *Server) handleSlashCommand(command goslack.SlashCommand) {
 
 	if err := rootCmd.Execute(); err != nil {
 		slog.Warn("Invalid slash command usage", "text", command.Text, "error", err)
+		if buf.Len() == 0 {
+			fmt.Fprintf(&buf, "Error: %s", err)
+		}
 		s.slackClient.PostEphemeral(command.ChannelID, command.UserID, goslack.MsgOptionText(buf.String(), false))
 		return
 	}
@@ -284,7 +462,9 @@ func (s *Server) createSlackEventHandler() http.HandlerFunc {
 		}
 
 		if eventsAPIEvent.Type == slackevents.CallbackEvent {
-			s.handleCallbackEvent(eventsAPIEvent)
+			// Slack sets the retry number when it thinks an earlier attempt wasn't acknowledged.
+			retryAttempt, _ := strconv.Atoi(r.Header.Get("X-Slack-Retry-Num"))
+			s.dispatchEvent(eventsAPIEvent, retryAttempt)
 		}
 		w.WriteHeader(http.StatusOK)
 	}
@@ -292,7 +472,23 @@ func (s *Server) createSlackEventHandler() http.HandlerFunc {
 
 func (s *Server) handleCallbackEvent(eventsAPIEvent slackevents.EventsAPIEvent) {
 	innerEvent := eventsAPIEvent.InnerEvent
-	slog.Debug("Handling callback event", "type", innerEvent.Type)
+	slog.Debug("Handling callback event", "type", innerEvent.Type, "team_id", eventsAPIEvent.TeamID)
+	switch ev := innerEvent.Data.(type) {
+	case *slackevents.AppUninstalledEvent:
+		s.uninstall(eventsAPIEvent.TeamID)
+		return
+	case *slackevents.TokensRevokedEvent:
+		if len(ev.Tokens.Bot) > 0 {
+			s.uninstall(eventsAPIEvent.TeamID)
+		}
+		return
+	}
+
+	s, err := s.forTeam(eventsAPIEvent.TeamID)
+	if err != nil {
+		slog.Error("Ignoring event from workspace", "error", err, "team_id", eventsAPIEvent.TeamID)
+		return
+	}
 	switch ev := innerEvent.Data.(type) {
 	case *slackevents.MessageEvent:
 		slog.Debug("Received message event", "user_id", ev.User, "text", ev.Text, "bot_id", ev.BotID)
@@ -300,22 +496,96 @@ func (s *Server) handleCallbackEvent(eventsAPIEvent slackevents.EventsAPIEvent)
 			slog.Debug("Ignoring message from bot")
 			return
 		}
-		s.mu.Lock()
-		ui, ok := s.activeInterviews[ev.User]
-		s.mu.Unlock()
 
-		if ok {
+		if ui := s.findSlackInterview(ev.User, ev.Channel, ev.ThreadTimeStamp); ui != nil {
 			slog.Debug("Found active interview for user", "user_id", ev.User)
-			ui.AnswerChan <- ev.Text
+			var files []goslack.File
+			if ev.Message != nil {
+				files = ev.Message.Files
+			}
+			if !ui.AnswerWithFiles(ev.Text, files) {
+				slog.Debug("Dropping message for an interview that has finished", "user_id", ev.User)
+			}
 		} else {
 			slog.Debug("No active interview found for user", "user_id", ev.User)
 		}
+	case *slackevents.AppHomeOpenedEvent:
+		if ev.Tab == "home" {
+			s.publishHome(ev.User)
+		}
+	case *slackevents.ReactionAddedEvent:
+		s.triageInterview(ev.Item.Channel, ev.Item.Timestamp, ev.Reaction, ev.User, false)
+	case *slackevents.ReactionRemovedEvent:
+		s.triageInterview(ev.Item.Channel, ev.Item.Timestamp, ev.Reaction, ev.User, true)
+	}
+}
+
+// findTopic returns the topic with the given ID, or nil if there is none.
+func findTopic(cfg *config.Config, topicID string) *config.Topic {
+	for i, t := range cfg.Interviews {
+		if strings.EqualFold(t.ID, topicID) {
+			return &cfg.Interviews[i]
+		}
 	}
+	return nil
 }
 
-// newQuestionProvider creates a QuestionProvider based on the selected topic.
-func newQuestionProvider(cfg *config.Config, topic *config.Topic, apiKey, model string) (interview.QuestionProvider, error) {
-	switch strings.ToLower(topic.Provider) {
+// slackOptions returns the options for the UI of an interview in Slack about the topic, followed by opts.
+func slackOptions(topic *config.Topic, opts ...slack.Option) []slack.Option {
+	return append([]slack.Option{slack.WithIdle(topic.Idle.RemindAfter, topic.Idle.AbandonAfter)}, opts...)
+}
+
+// newInterview creates an interview for the selected topic, conducted through the given UI.
+func (s *Server) newInterview(topic *config.Topic, ui interview.InterviewUI, opts ...interview.Option) (*interview.Interview, error) {
+	questionProvider, err := newQuestionProvider(s.config, topic, s.health, s.apiKey, viper.GetString("model"))
+	if err != nil {
+		return nil, fmt.Errorf("could not create question provider: %w", err)
+	}
+	if topic.Lint.Guard {
+		questionProvider = lint.NewGuard(questionProvider, lint.New(topic.Lint.Jargon...))
+	}
+
+	if s.config.Redaction.Enabled {
+		redactor, err := newRedactor(s.config)
+		if err != nil {
+			return nil, fmt.Errorf("could not create redactor: %w", err)
+		}
+		opts = append(opts, interview.WithRedactor(redactor))
+		if s.config.Redaction.Attachments {
+			opts = append(opts, interview.WithUnredactedAttachments())
+		}
+	}
+	if s.blobs != nil {
+		opts = append(opts, interview.WithBlobs(s.blobs))
+	}
+	if (s.slackClient != nil || s.oauth != nil) && len(topic.SummaryChannels) > 0 {
+		opts = append(opts, interview.WithPublisher(summaryPublisher{s: s, topic: topic}))
+	}
+	return interview.NewInterview(questionProvider, ui, s.repo, opts...), nil
+}
+
+// newQuestionProvider creates a QuestionProvider based on the selected topic. If the topic lists fallback
+// providers, they are chained behind the topic's provider.
+func newQuestionProvider(cfg *config.Config, topic *config.Topic, health *fallback.Health, apiKey, model string) (interview.QuestionProvider, error) {
+	p, err := newProvider(cfg, topic, topic.Provider, apiKey, model)
+	if err != nil || len(topic.Fallback) == 0 {
+		return p, err
+	}
+
+	members := []fallback.Member{{Name: strings.ToLower(topic.Provider), Provider: p}}
+	for _, name := range topic.Fallback {
+		p, err := newProvider(cfg, topic, name, apiKey, model)
+		if err != nil {
+			return nil, fmt.Errorf("could not create fallback provider '%s': %w", name, err)
+		}
+		members = append(members, fallback.Member{Name: strings.ToLower(name), Provider: p})
+	}
+	return fallback.New(health, members...), nil
+}
+
+// newProvider creates the named QuestionProvider for the selected topic.
+func newProvider(cfg *config.Config, topic *config.Topic, name, apiKey, model string) (interview.QuestionProvider, error) {
+	switch strings.ToLower(name) {
 	case "static":
 		return static.New(topic.Questions), nil
 	case "gemini":
@@ -324,10 +594,26 @@ func newQuestionProvider(cfg *config.Config, topic *config.Topic, apiKey, model
 		}
 
 		finalPrompt := buildGeminiPrompt(cfg, topic.Prompt)
-		return gemini.New(cfg, gemini.Model(model), gemini.APIKey(apiKey), gemini.Prompt(finalPrompt))
+		return gemini.New(cfg, gemini.Model(model), gemini.APIKey(apiKey), gemini.Prompt(finalPrompt), gemini.WithHistory(topic.History.Strategy, topic.History.Turns), gemini.WithGeneration(cfg.Providers.Gemini.Generation.Merge(topic.Generation)))
+	case "openai":
+		finalPrompt := buildGeminiPrompt(cfg, topic.Prompt)
+		return openai.New(cfg, openai.BaseURL(cfg.Providers.OpenAI.BaseURL), openai.Model(cfg.Providers.OpenAI.Model), openai.APIKey(cfg.Providers.OpenAI.APIKey), openai.Prompt(finalPrompt), topic.Generation)
 	default:
-		return nil, fmt.Errorf("unknown provider '%s'", topic.Provider)
+		return nil, fmt.Errorf("unknown provider '%s'", name)
+	}
+}
+
+// newRedactor creates a Redactor for a single interview from the redaction configuration.
+func newRedactor(cfg *config.Config) (*redaction.Redactor, error) {
+	var sealer *redaction.Sealer
+	if cfg.Redaction.Key != "" {
+		var err error
+		sealer, err = redaction.NewSealer(cfg.Redaction.Key)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return redaction.New(cfg.Redaction.Detectors, cfg.Redaction.Dictionaries, sealer)
 }
 
 // buildGeminiPrompt constructs the final prompt for the Gemini provider.
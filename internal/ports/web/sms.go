@@ -0,0 +1,191 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/sms"
+	"github.com/andrewhowdencom/vox/internal/config"
+)
+
+// Channels an interview by message can be held on.
+const (
+	smsChannelSMS      = "sms"
+	smsChannelWhatsApp = "whatsapp"
+)
+
+// phoneNumber matches a phone number in E.164 format, such as "+15550100".
+var phoneNumber = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// emptyTwiML is the response to an incoming message, telling Twilio not to reply to it.
+const emptyTwiML = `<?xml version="1.0" encoding="UTF-8"?><Response></Response>`
+
+// smsChannel holds interviews by text message and WhatsApp.
+type smsChannel struct {
+	client       sms.Messenger
+	from         string
+	whatsAppFrom string
+}
+
+// smsKey identifies an interview held with a phone number, or a WhatsApp address, in activeInterviews.
+func smsKey(address string) string {
+	return "sms/" + address
+}
+
+// newSMSChannel creates the channel configured in the sms section of the configuration.
+func newSMSChannel(cfg *config.Config) *smsChannel {
+	var opts []sms.ClientOption
+	if cfg.SMS.APIURL != "" {
+		opts = append(opts, sms.WithAPIURL(cfg.SMS.APIURL))
+	}
+	channel := &smsChannel{
+		client: sms.NewClient(cfg.SMS.AccountSID, cfg.SMS.AuthToken, opts...),
+		from:   cfg.SMS.From,
+	}
+	if cfg.SMS.WhatsAppFrom != "" {
+		channel.whatsAppFrom = sms.WhatsAppPrefix + strings.TrimPrefix(cfg.SMS.WhatsAppFrom, sms.WhatsAppPrefix)
+	}
+	return channel
+}
+
+// registerSMSRoutes adds the handlers for starting interviews by message, and for the messages Twilio delivers.
+func (s *Server) registerSMSRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v1/sms-interviews", s.authenticate(scopeSessionsWrite, s.createSMSInterview))
+	mux.HandleFunc("POST /sms/inbound", s.createInboundSMSHandler())
+}
+
+// createSMSInterview starts an interview about a topic with the participant at a phone number, by text message
+// or WhatsApp. Numbers that have opted out of messages aren't sent any.
+func (s *Server) createSMSInterview(w http.ResponseWriter, r *http.Request, client config.APIToken) {
+	var body struct {
+		Topic   string `json:"topic"`
+		Phone   string `json:"phone"`
+		Channel string `json:"channel"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnswerSize)).Decode(&body); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "the request body must be a JSON object")
+		return
+	}
+	if !phoneNumber.MatchString(body.Phone) {
+		writeAPIError(w, http.StatusBadRequest, "phone must be a number in E.164 format, such as +15550100")
+		return
+	}
+
+	to, from := body.Phone, s.sms.from
+	switch body.Channel {
+	case "", smsChannelSMS:
+		body.Channel = smsChannelSMS
+	case smsChannelWhatsApp:
+		to, from = sms.WhatsAppPrefix+body.Phone, s.sms.whatsAppFrom
+	default:
+		writeAPIError(w, http.StatusBadRequest, "channel must be sms or whatsapp")
+		return
+	}
+	if from == "" {
+		writeAPIError(w, http.StatusBadRequest, "no number is configured to send "+body.Channel+" messages from")
+		return
+	}
+	topic := findTopic(s.config, body.Topic)
+	if topic == nil {
+		writeAPIError(w, http.StatusNotFound, "topic '"+body.Topic+"' not found")
+		return
+	}
+	if s.optOuts != nil {
+		optedOut, err := s.optOuts.IsOptedOut(to)
+		if err != nil {
+			slog.Error("Error checking opt-out", "error", err, "to", to)
+			writeAPIError(w, http.StatusInternalServerError, "could not check whether the number has opted out")
+			return
+		}
+		if optedOut {
+			writeAPIError(w, http.StatusConflict, "the participant has opted out of messages")
+			return
+		}
+	}
+
+	key := smsKey(to)
+	ui := sms.New(s.sms.client, from, to)
+	if !s.claimInterview(key, ui) {
+		writeAPIError(w, http.StatusConflict, "the participant already has an interview in progress")
+		return
+	}
+	slog.Info("Starting interview by message", "to", to, "topic_id", topic.ID, "client", client.Name)
+	go func() {
+		intro := "Hi! We'd like to ask you a few questions about " + topicName(topic) + ". Reply HELP for help or STOP to opt out."
+		if err := ui.Say(intro); err != nil {
+			slog.Error("Error sending introduction", "error", err, "to", to)
+		}
+		s.runChatInterview(key, ui, to, topic)
+	}()
+
+	writeJSON(w, http.StatusCreated, map[string]string{"topic": topic.ID, "phone": body.Phone, "channel": body.Channel})
+}
+
+// createInboundSMSHandler checks that each incoming message was sent by Twilio, then handles it in the
+// background, after the others from the same number. Twilio is told not to reply, since any reply is sent as a
+// separate message.
+func (s *Server) createInboundSMSHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		err := sms.Verify(s.config.SMS.AuthToken, s.config.SMS.WebhookURL, r.PostForm, r.Header.Get("X-Twilio-Signature"))
+		if err != nil {
+			slog.Error("Error verifying incoming message", "error", err)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		from, to, body := r.PostForm.Get("From"), r.PostForm.Get("To"), r.PostForm.Get("Body")
+		s.eventQueue.push(smsKey(from), func() { s.handleSMS(from, to, body) })
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(emptyTwiML))
+	}
+}
+
+// handleSMS handles a message from the participant at an address, sent to one of vox's numbers. Opting out
+// cancels their interview in progress and stops any more being started; otherwise, the message is passed to
+// their interview in progress.
+func (s *Server) handleSMS(from, to, body string) {
+	s.mu.Lock()
+	ui, active := s.activeInterviews[smsKey(from)].(*sms.UI)
+	s.mu.Unlock()
+
+	switch {
+	case sms.IsOptOut(body):
+		slog.Info("Participant opted out of messages", "from", from)
+		if s.optOuts != nil {
+			if err := s.optOuts.SaveOptOut(from); err != nil {
+				slog.Error("Error saving opt-out", "error", err, "from", from)
+			}
+		}
+		if active {
+			ui.OptOut()
+		}
+		return
+	case sms.IsHelp(body):
+		if err := s.sms.client.SendMessage(to, from, sms.HelpText); err != nil {
+			slog.Error("Error sending help", "error", err, "to", from)
+		}
+		return
+	case !active && sms.IsOptIn(body):
+		if s.optOuts != nil {
+			if err := s.optOuts.DeleteOptOut(from); err != nil {
+				slog.Error("Error removing opt-out", "error", err, "from", from)
+			}
+		}
+		slog.Info("Participant opted back in to messages", "from", from)
+		return
+	case !active:
+		slog.Debug("Ignoring message outside of an interview", "from", from)
+		return
+	}
+
+	if !ui.Answer(body) {
+		slog.Debug("Dropping message for an interview that has finished", "from", from)
+	}
+}
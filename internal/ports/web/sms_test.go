@@ -0,0 +1,175 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/sms"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *memoryRepository) SaveOptOut(number string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.optOuts == nil {
+		m.optOuts = make(map[string]bool)
+	}
+	m.optOuts[number] = true
+	return nil
+}
+
+func (m *memoryRepository) IsOptedOut(number string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.optOuts[number], nil
+}
+
+func (m *memoryRepository) DeleteOptOut(number string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.optOuts, number)
+	return nil
+}
+
+// fakeTwilio is a local stand-in for the Twilio REST API, that records the messages sent through it.
+type fakeTwilio struct {
+	mu   sync.Mutex
+	sent []url.Values
+}
+
+func (f *fakeTwilio) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	f.mu.Lock()
+	f.sent = append(f.sent, r.PostForm)
+	f.mu.Unlock()
+	w.WriteHeader(http.StatusCreated)
+}
+
+// bodies returns the bodies of the messages sent so far.
+func (f *fakeTwilio) bodies() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var bodies []string
+	for _, message := range f.sent {
+		bodies = append(bodies, message.Get("Body"))
+	}
+	return bodies
+}
+
+// newTestSMSServer creates a server that sends messages through a fake Twilio, and takes them on /sms/inbound.
+func newTestSMSServer(t *testing.T) (*Server, *memoryRepository, *fakeTwilio, *httptest.Server) {
+	t.Helper()
+	s, repo, _ := newTestServer(t)
+	twilio := &fakeTwilio{}
+	api := httptest.NewServer(twilio)
+	t.Cleanup(api.Close)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	s.config.API.Tokens = []config.APIToken{{Name: "crm", Token: "crm-token", Scopes: []string{scopeSessionsWrite}}}
+	s.config.SMS.AccountSID = "AC123"
+	s.config.SMS.AuthToken = "auth-token"
+	s.config.SMS.From = "+15550100"
+	s.config.SMS.WebhookURL = server.URL + "/sms/inbound"
+	s.config.SMS.APIURL = api.URL
+	s.sms = newSMSChannel(s.config)
+	s.optOuts = repo
+	s.registerSMSRoutes(mux)
+	return s, repo, twilio, server
+}
+
+// deliverSMS delivers a signed message from the number to /sms/inbound, returning the status.
+func deliverSMS(t *testing.T, s *Server, server *httptest.Server, from, body string) int {
+	t.Helper()
+	form := url.Values{"From": {from}, "To": {"+15550100"}, "Body": {body}, "MessageSid": {"SM123"}}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/sms/inbound", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Twilio-Signature", sms.Sign(s.config.SMS.AuthToken, s.config.SMS.WebhookURL, form))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestSMSInterview(t *testing.T) {
+	s, repo, twilio, server := newTestSMSServer(t)
+	waitFor := func(n int) {
+		t.Helper()
+		require.Eventually(t, func() bool { return len(twilio.bodies()) >= n }, 2*time.Second, 5*time.Millisecond)
+	}
+	start := func() int {
+		t.Helper()
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sms-interviews", "crm-token", `{"topic":"feedback","phone":"+15550123"}`, nil)
+		return resp.StatusCode
+	}
+
+	t.Run("should reject numbers that aren't in E.164 format", func(t *testing.T) {
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sms-interviews", "crm-token", `{"topic":"feedback","phone":"555-0123"}`, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should reject WhatsApp interviews without a WhatsApp number", func(t *testing.T) {
+		resp := apiRequest(t, http.MethodPost, server.URL+"/api/v1/sms-interviews", "crm-token", `{"topic":"feedback","phone":"+15550123","channel":"whatsapp"}`, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	require.Equal(t, http.StatusCreated, start())
+	waitFor(2)
+	assert.Contains(t, twilio.bodies()[1], "What do you like?")
+	assert.Equal(t, http.StatusConflict, start())
+
+	t.Run("should reject unsigned messages", func(t *testing.T) {
+		resp, err := http.PostForm(server.URL+"/sms/inbound", url.Values{"From": {"+15550123"}, "Body": {"Nothing."}})
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	assert.Equal(t, http.StatusOK, deliverSMS(t, s, server, "+15550123", "The reports."))
+	waitFor(3)
+	assert.Contains(t, twilio.bodies()[2], "What would you change?")
+	assert.Equal(t, http.StatusOK, deliverSMS(t, s, server, "+15550123", "Faster exports."))
+	require.Eventually(t, func() bool { return s.activeCount() == 0 }, 2*time.Second, 5*time.Millisecond)
+
+	transcript, err := repo.GetTranscript("interview-1")
+	require.NoError(t, err)
+	require.Len(t, transcript.Entries, 2)
+	assert.Equal(t, "The reports.", transcript.Entries[0].Answer)
+	assert.Equal(t, "Faster exports.", transcript.Entries[1].Answer)
+
+	t.Run("should cancel the interview and send nothing more after STOP", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, start())
+		require.Eventually(t, func() bool { return s.activeCount() == 1 }, 2*time.Second, 5*time.Millisecond)
+		sent := len(twilio.bodies())
+		assert.Equal(t, http.StatusOK, deliverSMS(t, s, server, "+15550123", "Stop"))
+		require.Eventually(t, func() bool { return s.activeCount() == 0 }, 2*time.Second, 5*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		for _, body := range twilio.bodies()[sent:] {
+			assert.NotContains(t, body, "cancelled")
+		}
+
+		optedOut, err := repo.IsOptedOut("+15550123")
+		require.NoError(t, err)
+		assert.True(t, optedOut)
+		assert.Equal(t, http.StatusConflict, start())
+	})
+
+	t.Run("should take interviews again after START", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, deliverSMS(t, s, server, "+15550123", "START"))
+		require.Eventually(t, func() bool {
+			optedOut, _ := repo.IsOptedOut("+15550123")
+			return !optedOut
+		}, 2*time.Second, 5*time.Millisecond)
+		assert.Equal(t, http.StatusCreated, start())
+	})
+}
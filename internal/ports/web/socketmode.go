@@ -0,0 +1,80 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+
+	goslack "github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// socketModeAcker acknowledges requests received over a Socket Mode connection.
+type socketModeAcker interface {
+	Ack(req socketmode.Request, payload ...interface{})
+}
+
+// runSocketMode receives Slack events, slash commands and interactions over a Socket Mode connection. The
+// connection is opened from our side, so the server doesn't need a public URL. It returns once the context is
+// cancelled.
+func (s *Server) runSocketMode(ctx context.Context, client *socketmode.Client) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-client.Events:
+				s.handleSocketModeEvent(event, client)
+			}
+		}
+	}()
+
+	if err := client.RunContext(ctx); err != nil && ctx.Err() == nil {
+		slog.Error("Socket Mode connection failed", "error", err)
+	}
+}
+
+// handleSocketModeEvent acknowledges a request received over Socket Mode, then handles it in the same way
+// as the equivalent webhook.
+func (s *Server) handleSocketModeEvent(event socketmode.Event, acker socketModeAcker) {
+	switch event.Type {
+	case socketmode.EventTypeConnecting:
+		slog.Info("Connecting to Slack with Socket Mode")
+	case socketmode.EventTypeConnected:
+		slog.Info("Connected to Slack with Socket Mode")
+	case socketmode.EventTypeConnectionError, socketmode.EventTypeInvalidAuth:
+		slog.Error("Error connecting to Slack with Socket Mode", "type", event.Type, "data", event.Data)
+	case socketmode.EventTypeEventsAPI:
+		eventsAPIEvent, ok := event.Data.(slackevents.EventsAPIEvent)
+		if !ok {
+			slog.Warn("Ignoring unexpected Socket Mode event", "type", event.Type)
+			return
+		}
+		acker.Ack(*event.Request)
+
+		slog.Debug("Received slack event over Socket Mode", "type", eventsAPIEvent.Type)
+		if eventsAPIEvent.Type == slackevents.CallbackEvent {
+			s.dispatchEvent(eventsAPIEvent, event.Request.RetryAttempt)
+		}
+	case socketmode.EventTypeSlashCommand:
+		command, ok := event.Data.(goslack.SlashCommand)
+		if !ok {
+			slog.Warn("Ignoring unexpected Socket Mode event", "type", event.Type)
+			return
+		}
+		acker.Ack(*event.Request)
+
+		go s.handleSlashCommand(command)
+	case socketmode.EventTypeInteractive:
+		callback, ok := event.Data.(goslack.InteractionCallback)
+		if !ok {
+			slog.Warn("Ignoring unexpected Socket Mode event", "type", event.Type)
+			return
+		}
+		acker.Ack(*event.Request)
+
+		if callback.Type == goslack.InteractionTypeBlockActions {
+			go s.handleBlockActions(callback)
+		}
+	}
+}
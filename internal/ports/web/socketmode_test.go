@@ -0,0 +1,58 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAcker records the requests that are acknowledged.
+type recordingAcker struct {
+	acked []string
+}
+
+func (a *recordingAcker) Ack(req socketmode.Request, _ ...interface{}) {
+	a.acked = append(a.acked, req.EnvelopeID)
+}
+
+func TestHandleSocketModeEvent(t *testing.T) {
+	s, _, _ := newTestServer(t)
+	ui := slack.New(nil, "D123", "U123")
+	s.activeInterviews["U123"] = ui
+
+	t.Run("should acknowledge events and route messages to the active interview", func(t *testing.T) {
+		acker := &recordingAcker{}
+		event := socketmode.Event{
+			Type: socketmode.EventTypeEventsAPI,
+			Data: slackevents.EventsAPIEvent{
+				Type: slackevents.CallbackEvent,
+				InnerEvent: slackevents.EventsAPIInnerEvent{
+					Type: string(slackevents.Message),
+					Data: &slackevents.MessageEvent{User: "U123", Channel: "D123", Text: "The speed."},
+				},
+			},
+			Request: &socketmode.Request{Type: socketmode.RequestTypeEventsAPI, EnvelopeID: "envelope-1"},
+		}
+
+		go s.handleSocketModeEvent(event, acker)
+
+		select {
+		case answer := <-ui.AnswerChan:
+			assert.Equal(t, "The speed.", answer)
+		case <-time.After(time.Second):
+			require.Fail(t, "the answer was not routed to the interview")
+		}
+		assert.Equal(t, []string{"envelope-1"}, acker.acked)
+	})
+
+	t.Run("should not acknowledge connection events", func(t *testing.T) {
+		acker := &recordingAcker{}
+		s.handleSocketModeEvent(socketmode.Event{Type: socketmode.EventTypeConnected}, acker)
+		assert.Empty(t, acker.acked)
+	})
+}
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	goslack "github.com/slack-go/slack"
+)
+
+// slackUserID matches the ID of a Slack user, as opposed to a participant from outside Slack.
+var slackUserID = regexp.MustCompile(`^[UW][A-Z0-9]+$`)
+
+// actionSummaryView is the action ID of the button on a summary card that shows the interview's transcript to
+// whoever pressed it. Its value is the ID of the interview.
+const actionSummaryView = "vox_summary_view"
+
+// defaultTriage is used if no triage reactions are configured.
+var defaultTriage = map[string]string{"white_check_mark": "reviewed"}
+
+// summaryPublisher posts a card with the summary of each interview about a topic to the topic's summary
+// channels.
+type summaryPublisher struct {
+	s     *Server
+	topic *config.Topic
+}
+
+// Publish posts the summary card to each channel, and records where it was posted so reactions to it can be
+// used to triage the interview.
+func (p summaryPublisher) Publish(event domain.InterviewCompleted) {
+	client, err := p.s.summaryClient()
+	if err != nil {
+		slog.Error("Error finding the workspace to post summary cards to", "error", err, "interview_id", event.Interview.ID)
+		return
+	}
+	blocks := p.s.summaryCardBlocks(p.topic, event)
+	text := fmt.Sprintf("New interview about %s", topicName(p.topic))
+	for _, channel := range p.topic.SummaryChannels {
+		channelID, timestamp, err := client.PostMessage(channel, goslack.MsgOptionText(text, false), goslack.MsgOptionBlocks(blocks...))
+		if err != nil {
+			slog.Error("Error posting summary card", "error", err, "channel", channel, "interview_id", event.Interview.ID)
+			continue
+		}
+		if p.s.cards == nil {
+			continue
+		}
+		card := &domain.SummaryCard{InterviewID: event.Interview.ID, ChannelID: channelID, Timestamp: timestamp}
+		if err := p.s.cards.SaveSummaryCard(card); err != nil {
+			slog.Error("Error saving summary card", "error", err, "channel", channel, "interview_id", event.Interview.ID)
+		}
+	}
+}
+
+// summaryClient returns the client for the workspace the summary channels are in, which may not be the one the
+// interview was taken in: the workspace named by slack.summary_team, or else the one with the bot token.
+func (s *Server) summaryClient() (slack.AppClient, error) {
+	if s.oauth != nil && s.config.Slack.SummaryTeam != "" {
+		return s.oauth.client(s.config.Slack.SummaryTeam)
+	}
+	if s.botClient == nil {
+		return nil, fmt.Errorf("%w: set slack.summary_team to the workspace the summary channels are in", errNotInstalled)
+	}
+	return s.botClient, nil
+}
+
+// summaryCardBlocks describes the interview for the team: who took it, its summary, and how to triage it.
+func (s *Server) summaryCardBlocks(topic *config.Topic, event domain.InterviewCompleted) []goslack.Block {
+	interview := event.Interview
+	heading := fmt.Sprintf("*%s* with %s\n%s · %d answers", topicName(topic), participantName(interview.UserID), interviewStatus(interview), len(event.Transcript.Entries))
+	blocks := []goslack.Block{goslack.NewSectionBlock(markdownText(heading), nil, nil)}
+	if event.Summary.Text != "" {
+		blocks = append(blocks, goslack.NewSectionBlock(markdownText(truncate(event.Summary.Text, maxTextLength)), nil, nil))
+	}
+
+	triage := s.triageLabels()
+	var hints []string
+	for _, reaction := range slices.Sorted(maps.Keys(triage)) {
+		hints = append(hints, fmt.Sprintf(":%s: %s", reaction, triage[reaction]))
+	}
+	blocks = append(blocks,
+		goslack.NewContextBlock("", markdownText("React to triage: "+strings.Join(hints, " · "))),
+		goslack.NewActionBlock("", goslack.NewButtonBlockElement(actionSummaryView, interview.ID, plainText("View transcript"))),
+	)
+	return blocks
+}
+
+// handleSummaryActions shows the transcript of the interview whose summary card had its button pressed, in a
+// modal, so the card shared with the channel is left as it is. Only the participant and PMs are shown it. It
+// reports whether any such buttons were pressed.
+func (s *Server) handleSummaryActions(callback goslack.InteractionCallback) bool {
+	var handled bool
+	for _, action := range callback.ActionCallback.BlockActions {
+		if action.ActionID != actionSummaryView {
+			continue
+		}
+		handled = true
+		interview, err := s.allowedInterview(callback.User.ID, action.Value)
+		if err != nil {
+			_, err := s.slackClient.PostEphemeral(callback.Channel.ID, callback.User.ID, goslack.MsgOptionText("Only the participant and PMs can read the transcript.", false))
+			if err != nil {
+				slog.Error("Error responding to summary card", "error", err, "user_id", callback.User.ID)
+			}
+			continue
+		}
+		if err := s.openTranscript(callback.TriggerID, interview); err != nil {
+			slog.Error("Error showing interview", "error", err, "interview_id", interview.ID)
+		}
+	}
+	return handled
+}
+
+// participantName mentions the participant if they took the interview in Slack.
+func participantName(userID string) string {
+	if slackUserID.MatchString(userID) {
+		return fmt.Sprintf("<@%s>", userID)
+	}
+	return userID
+}
+
+// triageLabels returns the labels given to interviews by each reaction.
+func (s *Server) triageLabels() map[string]string {
+	if len(s.config.Slack.Triage) > 0 {
+		return s.config.Slack.Triage
+	}
+	return defaultTriage
+}
+
+// triageInterview labels the interview whose summary card was reacted to, or removes the label if the
+// reaction was removed. Reactions to other messages, and reactions without a label, are ignored.
+func (s *Server) triageInterview(channelID, timestamp, reaction, userID string, removed bool) {
+	label, ok := s.triageLabels()[reaction]
+	if !ok || s.cards == nil {
+		return
+	}
+	card, err := s.cards.GetSummaryCard(channelID, timestamp)
+	if errors.Is(err, storage.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		slog.Error("Error finding summary card", "error", err, "channel_id", channelID)
+		return
+	}
+
+	_, err = s.repo.UpdateInterview(card.InterviewID, func(interview *domain.Interview) error {
+		given := func(t domain.Triage) bool { return t.Label == label && t.UserID == userID }
+		switch {
+		case removed:
+			interview.Triage = slices.DeleteFunc(interview.Triage, given)
+		case !slices.ContainsFunc(interview.Triage, given):
+			interview.Triage = append(interview.Triage, domain.Triage{Label: label, UserID: userID, At: time.Now()})
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Error triaging interview", "error", err, "interview_id", card.InterviewID)
+		return
+	}
+	slog.Info("Triaged interview", "interview_id", card.InterviewID, "label", label, "user_id", userID, "removed", removed)
+}
@@ -0,0 +1,173 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	goslack "github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *memoryRepository) SaveSummaryCard(card *domain.SummaryCard) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cards == nil {
+		m.cards = make(map[string]domain.SummaryCard)
+	}
+	m.cards[card.ChannelID+"/"+card.Timestamp] = *card
+	return nil
+}
+
+func (m *memoryRepository) GetSummaryCard(channelID, timestamp string) (*domain.SummaryCard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	card, ok := m.cards[channelID+"/"+timestamp]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &card, nil
+}
+
+func TestSummaryCards(t *testing.T) {
+	s, repo, api := newTestHomeServer(t)
+	s.cards = repo
+	s.botClient = s.slackClient
+	s.signingSecret = testSigningSecret
+	topic := findTopic(s.config, "feedback")
+	topic.SummaryChannels = []string{"C0PRODUCT"}
+
+	t.Run("should be published by interviews about topics with summary channels", func(t *testing.T) {
+		i, err := s.newInterview(topic, slack.New(nil, "D123", "U123"))
+		require.NoError(t, err)
+		assert.NotNil(t, i.Publisher)
+
+		i, err = s.newInterview(findTopic(s.config, "internal"), slack.New(nil, "D123", "U123"))
+		require.NoError(t, err)
+		assert.Nil(t, i.Publisher)
+	})
+
+	interview, err := repo.GetInterview("completed")
+	require.NoError(t, err)
+	transcript, err := repo.GetTranscript("completed")
+	require.NoError(t, err)
+	summary, err := repo.GetSummary("completed")
+	require.NoError(t, err)
+	summaryPublisher{s: s, topic: topic}.Publish(domain.InterviewCompleted{Interview: interview, Transcript: transcript, Summary: summary})
+
+	t.Run("should post the summary with a link to the transcript", func(t *testing.T) {
+		posts := api.sent("chat.postMessage")
+		require.Len(t, posts, 1)
+		assert.Equal(t, "C0PRODUCT", posts[0].Get("channel"))
+		blocks := posts[0].Get("blocks")
+		// The blocks are encoded as JSON, which escapes "<" and ">".
+		assert.Contains(t, blocks, `*Product Feedback* with \u003c@U123\u003e`)
+		assert.Contains(t, blocks, "2 answers")
+		assert.Contains(t, blocks, `Likes the speed.\nWould change nothing.`)
+		assert.Contains(t, blocks, ":white_check_mark: reviewed")
+		assert.Contains(t, blocks, `"action_id":"vox_summary_view","value":"completed"`)
+	})
+
+	t.Run("should show the transcript to PMs who press the button, without replacing the card", func(t *testing.T) {
+		press := func(userID string) {
+			var callback goslack.InteractionCallback
+			callback.Type = goslack.InteractionTypeBlockActions
+			callback.User.ID = userID
+			callback.Channel.ID = "C0PRODUCT"
+			callback.TriggerID = "trigger-1"
+			callback.ResponseURL = api.url + "/response"
+			callback.ActionCallback.BlockActions = []*goslack.BlockAction{{ActionID: actionSummaryView, Value: "completed"}}
+			s.handleBlockActions(callback)
+		}
+
+		press("U456")
+		assert.Empty(t, api.sent("views.open"))
+		ephemeral := api.sent("chat.postEphemeral")
+		require.Len(t, ephemeral, 1)
+		assert.Equal(t, "U456", ephemeral[0].Get("user"))
+
+		press("UPM")
+		opened := api.sent("views.open")
+		require.Len(t, opened, 1)
+		assert.Contains(t, opened[0].Get("body"), `"trigger_id":"trigger-1"`)
+		assert.Contains(t, opened[0].Get("body"), `*What would you change?*\nNothing.`)
+		assert.Empty(t, api.sent("response"), "the card should not be replaced")
+	})
+
+	t.Run("should triage the interview with reactions to the card", func(t *testing.T) {
+		server := httptest.NewServer(s.createSlackEventHandler())
+		t.Cleanup(server.Close)
+
+		resp := replaySlackFixture(t, server.URL, "reaction_added.json", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Eventually(t, func() bool {
+			interview, err := repo.GetInterview("completed")
+			return err == nil && len(interview.Triage) == 1
+		}, time.Second, time.Millisecond)
+
+		interview, err := repo.GetInterview("completed")
+		require.NoError(t, err)
+		assert.Equal(t, "reviewed", interview.Triage[0].Label)
+		assert.Equal(t, "UPM", interview.Triage[0].UserID)
+		assert.Contains(t, interviewStatus(interview), "· reviewed")
+
+		s.handleCallbackEvent(reaction(&slackevents.ReactionRemovedEvent{User: "UPM", Reaction: "white_check_mark", Item: slackevents.Item{Channel: "C0PRODUCT", Timestamp: "1700000000.000100"}}))
+		interview, err = repo.GetInterview("completed")
+		require.NoError(t, err)
+		assert.Empty(t, interview.Triage)
+	})
+
+	t.Run("should ignore other reactions and messages", func(t *testing.T) {
+		s.handleCallbackEvent(reaction(&slackevents.ReactionAddedEvent{User: "UPM", Reaction: "tada", Item: slackevents.Item{Channel: "C0PRODUCT", Timestamp: "1700000000.000100"}}))
+		s.handleCallbackEvent(reaction(&slackevents.ReactionAddedEvent{User: "UPM", Reaction: "white_check_mark", Item: slackevents.Item{Channel: "C0PRODUCT", Timestamp: "1.1"}}))
+
+		interview, err := repo.GetInterview("completed")
+		require.NoError(t, err)
+		assert.Empty(t, interview.Triage)
+	})
+}
+
+func TestSummaryCards_OAuth(t *testing.T) {
+	s, repo, _ := newTestHomeServer(t)
+	participantAPI, participantClient := newFakeSlackAPI(t)
+	operatorAPI, operatorClient := newFakeSlackAPI(t)
+	s.oauth = &slackOAuth{clients: map[string]slack.AppClient{
+		"TPARTICIPANT": slack.NewDispatcher(participantClient),
+		"TOPERATOR":    slack.NewDispatcher(operatorClient),
+	}}
+	s.config.Slack.SummaryTeam = "TOPERATOR"
+	topic := findTopic(s.config, "feedback")
+	topic.SummaryChannels = []string{"C0PRODUCT"}
+
+	interview, err := repo.GetInterview("completed")
+	require.NoError(t, err)
+	transcript, err := repo.GetTranscript("completed")
+	require.NoError(t, err)
+	summary, err := repo.GetSummary("completed")
+	require.NoError(t, err)
+
+	team, err := s.forTeam("TPARTICIPANT")
+	require.NoError(t, err)
+	summaryPublisher{s: team, topic: topic}.Publish(domain.InterviewCompleted{Interview: interview, Transcript: transcript, Summary: summary})
+
+	t.Run("should post the card in the workspace with the summary channels", func(t *testing.T) {
+		posts := operatorAPI.sent("chat.postMessage")
+		require.Len(t, posts, 1)
+		assert.Equal(t, "C0PRODUCT", posts[0].Get("channel"))
+		assert.Empty(t, participantAPI.sent("chat.postMessage"))
+	})
+}
+
+// reaction creates the event sent when a reaction is added to, or removed from, a message.
+func reaction(data any) slackevents.EventsAPIEvent {
+	return slackevents.EventsAPIEvent{
+		Type:       slackevents.CallbackEvent,
+		InnerEvent: slackevents.EventsAPIInnerEvent{Data: data},
+	}
+}
@@ -0,0 +1,176 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/teams"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// maxActivitySize is the largest activity accepted by /teams/messages. Files sent with a message are linked to,
+// rather than included.
+const maxActivitySize = 1 << 20
+
+// teamsBot holds interviews in Microsoft Teams.
+type teamsBot struct {
+	client   teams.Sender
+	verifier *teams.Verifier
+}
+
+// teamsKey identifies an interview held in a conversation in Teams in activeInterviews.
+func teamsKey(conversationID string) string {
+	return "teams/" + conversationID
+}
+
+// newTeamsBot creates the bot configured in the teams section of the configuration.
+func newTeamsBot(cfg *config.Config) *teamsBot {
+	var clientOpts []teams.ClientOption
+	if cfg.Teams.TokenURL != "" {
+		clientOpts = append(clientOpts, teams.WithTokenURL(cfg.Teams.TokenURL))
+	}
+	var verifierOpts []teams.VerifierOption
+	if cfg.Teams.OpenIDURL != "" {
+		verifierOpts = append(verifierOpts, teams.WithOpenIDURL(cfg.Teams.OpenIDURL))
+	}
+	return &teamsBot{
+		client:   teams.NewClient(cfg.Teams.AppID, cfg.Teams.AppPassword, clientOpts...),
+		verifier: teams.NewVerifier(cfg.Teams.AppID, verifierOpts...),
+	}
+}
+
+// registerTeamsRoutes adds the handler for the activities the Bot Framework sends the bot.
+func (s *Server) registerTeamsRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /teams/messages", s.createTeamsMessageHandler())
+}
+
+// createTeamsMessageHandler checks that each activity was sent by the Bot Framework, then handles it in the
+// background, after the others in its conversation. Replies are sent as separate messages, rather than in the
+// response.
+func (s *Server) createTeamsMessageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var activity teams.Activity
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxActivitySize)).Decode(&activity); err != nil {
+			slog.Error("Error parsing Teams activity", "error", err)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := s.teams.verifier.Verify(r.Header.Get("Authorization"), activity); err != nil {
+			slog.Error("Error verifying Teams activity", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		s.eventQueue.push(teamsKey(activity.Conversation.ID), func() { s.handleTeamsActivity(activity) })
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// handleTeamsActivity passes a message to the interview in progress in its conversation. Otherwise, messages
+// in a one-to-one conversation with the bot are run as commands.
+func (s *Server) handleTeamsActivity(activity teams.Activity) {
+	if activity.Type != teams.ActivityMessage {
+		slog.Debug("Ignoring Teams activity", "type", activity.Type)
+		return
+	}
+	text := activity.PlainText()
+	slog.Debug("Received Teams message", "user_id", activity.From.ID, "conversation_id", activity.Conversation.ID, "text", text)
+
+	s.mu.Lock()
+	ui, ok := s.activeInterviews[teamsKey(activity.Conversation.ID)].(*teams.UI)
+	s.mu.Unlock()
+	if ok && ui.UserID == activity.From.ID && !strings.HasPrefix(text, "interview cancel") {
+		if !ui.Answer(text) {
+			slog.Debug("Dropping message for an interview that has finished", "user_id", activity.From.ID)
+		}
+		return
+	}
+	if !activity.Personal() {
+		slog.Debug("Ignoring Teams message outside of an interview", "conversation_id", activity.Conversation.ID)
+		return
+	}
+	s.runTeamsCommand(activity, text)
+}
+
+// runTeamsCommand parses and runs a command sent to the bot in a one-to-one conversation, such as
+// "interview start --topic <topic>".
+func (s *Server) runTeamsCommand(activity teams.Activity, text string) {
+	reply := func(text string) {
+		if err := s.teams.client.SendToConversation(activity.Reference(), teams.Activity{Type: teams.ActivityMessage, Text: text}); err != nil {
+			slog.Error("Error replying in Teams", "error", err, "conversation_id", activity.Conversation.ID)
+		}
+	}
+
+	var topicID string
+	var interviewCmd = &cobra.Command{Use: "interview"}
+	var startCmd = &cobra.Command{
+		Use:   "start",
+		Short: "Start an interview about a topic",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if topicID == "" {
+				return errors.New("--topic is required")
+			}
+			topic := findTopic(s.config, topicID)
+			if topic == nil {
+				return fmt.Errorf("topic '%s' not found", topicID)
+			}
+
+			key := teamsKey(activity.Conversation.ID)
+			ui := teams.New(s.teams.client, activity.Reference(), activity.From.ID)
+			if !s.claimInterview(key, ui) {
+				return errors.New("you already have an interview in progress. Send `interview cancel` to cancel it")
+			}
+			go s.runChatInterview(key, ui, activity.From.ID, topic)
+			return nil
+		},
+	}
+	startCmd.Flags().StringVar(&topicID, "topic", "", "The ID of the interview topic")
+	interviewCmd.AddCommand(startCmd)
+
+	var cancelCmd = &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel your interview in progress, without saving it",
+		Run: func(cmd *cobra.Command, args []string) {
+			s.mu.Lock()
+			ui, ok := s.activeInterviews[teamsKey(activity.Conversation.ID)]
+			s.mu.Unlock()
+			if !ok {
+				cmd.Print("You don't have an interview in progress.")
+				return
+			}
+			slog.Info("Cancelling Teams interview", "user_id", activity.From.ID, "conversation_id", activity.Conversation.ID)
+			ui.Cancel()
+		},
+	}
+	interviewCmd.AddCommand(cancelCmd)
+
+	rootCmd := &cobra.Command{Use: "vox"}
+	rootCmd.AddCommand(interviewCmd)
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs(strings.Fields(text))
+
+	if err := rootCmd.Execute(); err != nil {
+		slog.Warn("Invalid Teams command", "text", text, "error", err)
+		buf.Reset()
+		fmt.Fprintf(&buf, "Error: %s", err)
+	}
+	if buf.Len() > 0 {
+		reply(buf.String())
+	}
+}
@@ -0,0 +1,207 @@
+package web
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/teams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const teamsAppID = "00000000-0000-0000-0000-000000000001"
+
+// fakeBotFramework stands in for the Bot Framework: it publishes the key it signs activities with, issues
+// tokens for the Bot Connector, and records the activities the bot sends to each conversation.
+type fakeBotFramework struct {
+	url string
+	key *rsa.PrivateKey
+
+	mu       sync.Mutex
+	received map[string][]teams.Activity
+}
+
+func newFakeBotFramework(t *testing.T) *fakeBotFramework {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f := &fakeBotFramework{key: key, received: make(map[string][]teams.Activity)}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	f.url = server.URL
+	mux.HandleFunc("GET /openid", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]any{{
+			"kty":          "RSA",
+			"kid":          "key-1",
+			"n":            base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			"endorsements": []string{"msteams"},
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "connector-token", "expires_in": 3600})
+	})
+	mux.HandleFunc("POST /v3/conversations/{id}/activities", func(w http.ResponseWriter, r *http.Request) {
+		var activity teams.Activity
+		json.NewDecoder(r.Body).Decode(&activity)
+		f.mu.Lock()
+		f.received[r.PathValue("id")] = append(f.received[r.PathValue("id")], activity)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	})
+	return f
+}
+
+// texts returns the text of each message the bot sent to the conversation.
+func (f *fakeBotFramework) texts(conversationID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var texts []string
+	for _, activity := range f.received[conversationID] {
+		texts = append(texts, activity.Text)
+	}
+	return texts
+}
+
+// send sends the bot a message from the user in the conversation, signed for the bot, returning the status
+// of the response.
+func (f *fakeBotFramework) send(t *testing.T, server *httptest.Server, conversationType, conversationID, userID, text string) int {
+	t.Helper()
+	activity := teams.Activity{
+		Type:         teams.ActivityMessage,
+		ServiceURL:   f.url,
+		ChannelID:    "msteams",
+		From:         teams.ChannelAccount{ID: userID},
+		Conversation: teams.ConversationAccount{ID: conversationID, ConversationType: conversationType},
+		Text:         text,
+	}
+	body, err := json.Marshal(activity)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/teams/messages", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+f.token(t, map[string]any{
+		"iss":        teams.Issuer,
+		"aud":        teamsAppID,
+		"exp":        time.Now().Add(time.Hour).Unix(),
+		"nbf":        time.Now().Unix(),
+		"serviceurl": f.url,
+	}))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// token signs the claims with the key.
+func (f *fakeBotFramework) token(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(map[string]string{"alg": "RS256", "kid": "key-1"}) + "." + segment(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// newTestTeamsServer creates a server with Teams configured against the fake Bot Framework.
+func newTestTeamsServer(t *testing.T) (*Server, *memoryRepository, *fakeBotFramework, *httptest.Server) {
+	t.Helper()
+	framework := newFakeBotFramework(t)
+	s, repo, _ := newTestServer(t)
+	s.config.Teams.AppID = teamsAppID
+	s.config.Teams.AppPassword = "secret"
+	s.config.Teams.TokenURL = framework.url + "/token"
+	s.config.Teams.OpenIDURL = framework.url + "/openid"
+	s.teams = newTeamsBot(s.config)
+
+	mux := http.NewServeMux()
+	s.registerTeamsRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, repo, framework, server
+}
+
+func TestTeamsInterview(t *testing.T) {
+	s, repo, framework, server := newTestTeamsServer(t)
+	waitFor := func(conversationID string, n int) {
+		t.Helper()
+		require.Eventually(t, func() bool { return len(framework.texts(conversationID)) >= n }, 2*time.Second, 5*time.Millisecond)
+	}
+
+	t.Run("should reject activities that aren't signed by the Bot Framework", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/teams/messages", "application/json", bytes.NewBufferString(`{"type":"message","text":"interview start --topic feedback"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("should reject activities that are too large", func(t *testing.T) {
+		text := strings.Repeat("a", maxActivitySize)
+		resp, err := http.Post(server.URL+"/teams/messages", "application/json", bytes.NewBufferString(`{"type":"message","text":"`+text+`"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+
+	t.Run("should report an unknown topic", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, framework.send(t, server, teams.ConversationPersonal, "a:unknown", "29:ada", "interview start --topic missing"))
+		waitFor("a:unknown", 1)
+		assert.Equal(t, "Error: topic 'missing' not found", framework.texts("a:unknown")[0])
+	})
+
+	t.Run("should interview the user in their personal conversation", func(t *testing.T) {
+		framework.send(t, server, teams.ConversationPersonal, "a:ada", "29:ada", "<at>vox</at> interview start --topic feedback")
+		waitFor("a:ada", 1)
+		assert.Equal(t, "What do you like?\n\n_Question 1 of 2_", framework.texts("a:ada")[0])
+
+		framework.send(t, server, teams.ConversationPersonal, "a:ada", "29:ada", "The reports")
+		waitFor("a:ada", 2)
+		framework.send(t, server, teams.ConversationPersonal, "a:ada", "29:ada", "Faster exports")
+		require.Eventually(t, func() bool { return s.activeCount() == 0 }, 2*time.Second, 5*time.Millisecond)
+
+		transcript, err := repo.GetTranscript("interview-1")
+		require.NoError(t, err)
+		require.Len(t, transcript.Entries, 2)
+		assert.Equal(t, "The reports", transcript.Entries[0].Answer)
+		assert.Equal(t, "Faster exports", transcript.Entries[1].Answer)
+		interview, err := repo.GetInterview("interview-1")
+		require.NoError(t, err)
+		assert.Equal(t, "29:ada", interview.UserID)
+	})
+
+	t.Run("should cancel the interview in progress", func(t *testing.T) {
+		framework.send(t, server, teams.ConversationPersonal, "a:grace", "29:grace", "interview start --topic feedback")
+		waitFor("a:grace", 1)
+		framework.send(t, server, teams.ConversationPersonal, "a:grace", "29:grace", "interview cancel")
+		waitFor("a:grace", 2)
+		assert.Equal(t, "This interview was cancelled, and your answers have been discarded.", framework.texts("a:grace")[1])
+	})
+
+	t.Run("should only take commands in personal conversations", func(t *testing.T) {
+		framework.send(t, server, "channel", "19:general", "29:ada", "<at>vox</at> interview start --topic feedback")
+		time.Sleep(50 * time.Millisecond)
+		assert.Empty(t, framework.texts("19:general"))
+	})
+}
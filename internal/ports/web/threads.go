@@ -0,0 +1,137 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/config"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	goslack "github.com/slack-go/slack"
+)
+
+// userMention matches a user mentioned in a slash command, such as <@U123|ada>.
+var userMention = regexp.MustCompile(`^<@([A-Z0-9]+)(\|[^>]*)?>$`)
+
+// topicName returns the name of the topic to show participants.
+func topicName(topic *config.Topic) string {
+	if topic.Name != "" {
+		return topic.Name
+	}
+	return topic.ID
+}
+
+// threadKey identifies an interview held in a thread in activeInterviews. Interviews in direct messages are
+// identified by the participant's user ID instead.
+func threadKey(channelID, threadTS string) string {
+	return channelID + "/" + threadTS
+}
+
+// findSlackInterview returns the active interview that a message or button press from the user belongs to,
+// or nil if there is none. In a thread held for an interview, only the participant can answer. Their interview
+// in direct messages only takes messages sent there, not those they send in channels vox is in.
+func (s *Server) findSlackInterview(userID, channelID, threadTS string) *slack.UI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if threadTS != "" {
+		if ui, ok := s.activeInterviews[threadKey(channelID, threadTS)].(*slack.UI); ok {
+			if string(ui.UserID) != userID {
+				return nil
+			}
+			return ui
+		}
+	}
+	ui, ok := s.activeInterviews[userID].(*slack.UI)
+	if !ok || string(ui.ChannelID) != channelID {
+		return nil
+	}
+	return ui
+}
+
+// runSlackInterview starts an interview in Slack, which must already be the interview in progress under the
+// key, and runs it until it finishes.
+func (s *Server) runSlackInterview(key string, ui *slack.UI, topic *config.Topic) {
+	active := &domain.ActiveInterview{
+		ID:        key,
+		TopicID:   topic.ID,
+		UserID:    string(ui.UserID),
+		TeamID:    s.teamID,
+		ChannelID: string(ui.ChannelID),
+		ThreadTS:  ui.ThreadTS,
+		StartedAt: time.Now(),
+	}
+	for _, observer := range ui.Observers() {
+		active.Observers = append(active.Observers, string(observer))
+	}
+	s.continueSlackInterview(active, ui, topic)
+}
+
+// startThreadInterview holds an interview in a new thread in the channel the command was used in, such as a
+// Slack Connect channel shared with a customer. The participant defaults to whoever used the command, and a
+// user can hold several interviews at once in different threads. If observe is set, whoever used the command
+// is sent a link to the thread and, once it finishes, the summary.
+func (s *Server) startThreadInterview(command goslack.SlashCommand, topic *config.Topic, participant string, observe bool) {
+	ephemeral := func(text string) {
+		s.slackClient.PostEphemeral(command.ChannelID, command.UserID, goslack.MsgOptionText(text, false))
+	}
+
+	participantID := command.UserID
+	if participant != "" {
+		match := userMention.FindStringSubmatch(participant)
+		if match == nil {
+			ephemeral(fmt.Sprintf("Error: mention the participant, such as @ada, instead of '%s'", participant))
+			return
+		}
+		participantID = match[1]
+	}
+
+	name := topicName(topic)
+	intro := fmt.Sprintf("<@%s>, <@%s> would like to interview you about *%s*. Reply in this thread to answer each question.", participantID, command.UserID, name)
+	if participantID == command.UserID {
+		intro = fmt.Sprintf("<@%s> is being interviewed about *%s*. Reply in this thread to answer each question.", participantID, name)
+	}
+	_, threadTS, err := s.slackClient.PostMessage(command.ChannelID, goslack.MsgOptionText(intro, false))
+	if err != nil {
+		slog.Error("Failed to start interview thread", "error", err, "channel_id", command.ChannelID)
+		ephemeral("Error: the interview could not be started in this channel. Is vox a member of it?")
+		return
+	}
+
+	opts := slackOptions(topic, slack.WithThread(threadTS))
+	if observe {
+		if observer, err := s.notifyObserver(command, participantID, threadTS); err != nil {
+			slog.Error("Failed to notify observer", "error", err, "user_id", command.UserID)
+		} else {
+			opts = append(opts, slack.WithObserver(observer))
+		}
+	}
+
+	ui := slack.New(s.slackClient, slack.ChannelID(command.ChannelID), slack.UserID(participantID), opts...)
+	key := threadKey(command.ChannelID, threadTS)
+	if !s.claimInterview(key, ui) {
+		ephemeral("Error: an interview is already in progress in this thread.")
+		return
+	}
+	s.runSlackInterview(key, ui, topic)
+}
+
+// notifyObserver sends whoever started an interview a link to its thread, returning their direct message
+// channel so they can be sent the summary.
+func (s *Server) notifyObserver(command goslack.SlashCommand, participantID, threadTS string) (slack.ChannelID, error) {
+	channel, _, _, err := s.slackClient.OpenConversation(&goslack.OpenConversationParameters{Users: []string{command.UserID}})
+	if err != nil {
+		return "", fmt.Errorf("could not open conversation: %w", err)
+	}
+
+	text := fmt.Sprintf("You're observing the interview with <@%s>.", participantID)
+	if link, err := s.slackClient.GetPermalink(&goslack.PermalinkParameters{Channel: command.ChannelID, Ts: threadTS}); err == nil {
+		text += " Follow along in " + link
+	}
+	if _, _, err := s.slackClient.PostMessage(channel.ID, goslack.MsgOptionText(text, false)); err != nil {
+		return "", fmt.Errorf("could not send message: %w", err)
+	}
+	return slack.ChannelID(channel.ID), nil
+}
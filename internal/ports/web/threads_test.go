@@ -0,0 +1,248 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewhowdencom/vox/internal/adapters/ui/slack"
+	"github.com/andrewhowdencom/vox/internal/domain"
+	"github.com/andrewhowdencom/vox/internal/domain/storage"
+	goslack "github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSlackAPI serves the parts of the Slack Web API used by the server, recording the requests made to each
+// method. Every member of a user group is "UPM". Responses to slash commands can be sent to url + "/response", and
+// files shared by participants are downloaded from url + "/files/".
+type fakeSlackAPI struct {
+	url      string
+	mu       sync.Mutex
+	requests map[string][]url.Values
+}
+
+func newFakeSlackAPI(t *testing.T) (*fakeSlackAPI, *goslack.Client) {
+	t.Helper()
+	api := &fakeSlackAPI{requests: make(map[string][]url.Values)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			// Views and responses to slash commands are sent as JSON, and recorded under "body".
+			body, _ := io.ReadAll(r.Body)
+			r.Form = url.Values{"body": {string(body)}}
+		} else if file, _, err := r.FormFile("file"); err == nil {
+			// Uploaded files are recorded under "file".
+			content, _ := io.ReadAll(file)
+			r.Form = url.Values{"file": {string(content)}}
+		} else {
+			r.ParseForm()
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			// The bot token is recorded under "token".
+			r.Form.Set("token", strings.TrimPrefix(auth, "Bearer "))
+		}
+		api.mu.Lock()
+		api.requests[r.URL.Path] = append(api.requests[r.URL.Path], r.Form)
+		api.mu.Unlock()
+
+		if strings.HasPrefix(r.URL.Path, "/files/") {
+			w.Write([]byte("\x89PNG"))
+			return
+		}
+		response := map[string]any{"ok": true}
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			response["channel"] = r.Form.Get("channel")
+			response["ts"] = "1700000000.000100"
+		case "/conversations.open":
+			response["channel"] = map[string]string{"id": "D999"}
+		case "/chat.getPermalink":
+			response["permalink"] = "https://example.slack.com/archives/C123/p1700000000000100"
+		case "/usergroups.users.list":
+			response["users"] = []string{"UPM"}
+		case "/files.getUploadURLExternal":
+			response["upload_url"] = api.url + "/upload"
+			response["file_id"] = "F123"
+		case "/files.completeUploadExternal":
+			response["files"] = []map[string]string{{"id": "F123"}}
+		case "/oauth.v2.access":
+			response["access_token"] = "xoxb-acme"
+			response["scope"] = "commands,chat:write"
+			response["bot_user_id"] = "U0BOT"
+			response["team"] = map[string]string{"id": "T0ACME", "name": "Acme"}
+			response["authed_user"] = map[string]string{"id": "U123"}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	api.url = server.URL
+	return api, goslack.New("xoxb-test", goslack.OptionAPIURL(server.URL+"/"))
+}
+
+// sent returns the requests made to the method so far.
+func (f *fakeSlackAPI) sent(method string) []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]url.Values(nil), f.requests["/"+method]...)
+}
+
+func (m *memoryRepository) SaveBlob(data []byte) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.blobs == nil {
+		m.blobs = make(map[string][]byte)
+	}
+	id := fmt.Sprintf("blob-%d", len(m.blobs)+1)
+	m.blobs[id] = data
+	return id, nil
+}
+
+func (m *memoryRepository) GetBlob(id string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.blobs[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return data, nil
+}
+
+// threadMessage creates a message event, as sent when a user replies in a thread.
+func threadMessage(userID, channelID, threadTS, text string) slackevents.EventsAPIEvent {
+	return slackevents.EventsAPIEvent{
+		Type: slackevents.CallbackEvent,
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Type: string(slackevents.Message),
+			Data: &slackevents.MessageEvent{User: userID, Channel: channelID, ThreadTimeStamp: threadTS, Text: text},
+		},
+	}
+}
+
+func TestFindSlackInterview(t *testing.T) {
+	s, _, _ := newTestServer(t)
+	direct := slack.New(nil, "D123", "U123")
+	first := slack.New(nil, "C123", "U123", slack.WithThread("1.1"))
+	second := slack.New(nil, "C123", "U123", slack.WithThread("2.2"))
+	s.activeInterviews["U123"] = direct
+	s.activeInterviews[threadKey("C123", "1.1")] = first
+	s.activeInterviews[threadKey("C123", "2.2")] = second
+
+	t.Run("should route replies in a thread to the interview held in it", func(t *testing.T) {
+		assert.Same(t, first, s.findSlackInterview("U123", "C123", "1.1"))
+		assert.Same(t, second, s.findSlackInterview("U123", "C123", "2.2"))
+	})
+
+	t.Run("should route other messages to the interview in direct messages", func(t *testing.T) {
+		assert.Same(t, direct, s.findSlackInterview("U123", "D123", ""))
+		assert.Same(t, direct, s.findSlackInterview("U123", "D123", "3.3"))
+	})
+
+	t.Run("should ignore replies in the thread from anyone but the participant", func(t *testing.T) {
+		assert.Nil(t, s.findSlackInterview("U456", "C123", "1.1"))
+	})
+
+	t.Run("should ignore the participant's messages in other channels and threads", func(t *testing.T) {
+		assert.Nil(t, s.findSlackInterview("U123", "C123", ""))
+		assert.Nil(t, s.findSlackInterview("U123", "C999", "9.9"))
+	})
+}
+
+func TestStartThreadInterview(t *testing.T) {
+	s, repo, _ := newTestServer(t)
+	api, client := newFakeSlackAPI(t)
+	s.slackClient = slack.NewDispatcher(client)
+
+	command := goslack.SlashCommand{UserID: "U123", ChannelID: "C123"}
+	done := make(chan struct{})
+	go func() {
+		s.startThreadInterview(command, findTopic(s.config, "feedback"), "<@U456|ada>", true)
+		close(done)
+	}()
+
+	key := threadKey("C123", "1700000000.000100")
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, ok := s.activeInterviews[key]
+		return ok
+	}, time.Second, time.Millisecond)
+
+	s.handleCallbackEvent(threadMessage("U123", "C123", "1700000000.000100", "Not my interview."))
+	s.handleCallbackEvent(threadMessage("U456", "C123", "1700000000.000100", "The speed."))
+	s.handleCallbackEvent(threadMessage("U456", "C123", "1700000000.000100", "Nothing."))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "the interview did not finish")
+	}
+	_, ok := repo.activeInterview(key)
+	assert.False(t, ok, "the interview's progress should be removed once it finishes")
+
+	posts := api.sent("chat.postMessage")
+	require.NotEmpty(t, posts)
+	assert.Equal(t, "C123", posts[0].Get("channel"))
+	assert.Contains(t, posts[0].Get("text"), "<@U456>, <@U123> would like to interview you about *Product Feedback*")
+	assert.Equal(t, "D999", posts[1].Get("channel"))
+	assert.Contains(t, posts[1].Get("text"), "https://example.slack.com/archives/C123/p1700000000000100")
+	for _, post := range posts[2:] {
+		assert.Equal(t, "1700000000.000100", post.Get("thread_ts"))
+	}
+
+	transcript, err := repo.GetTranscript("interview-1")
+	require.NoError(t, err)
+	require.Len(t, transcript.Entries, 2)
+	assert.Equal(t, "The speed.", transcript.Entries[0].Answer)
+	assert.Equal(t, "Nothing.", transcript.Entries[1].Answer)
+}
+
+func TestThreadInterview_Attachments(t *testing.T) {
+	s, repo, _ := newTestServer(t)
+	api, client := newFakeSlackAPI(t)
+	s.slackClient = slack.NewDispatcher(client)
+
+	command := goslack.SlashCommand{UserID: "U123", ChannelID: "C123"}
+	done := make(chan struct{})
+	go func() {
+		s.startThreadInterview(command, findTopic(s.config, "feedback"), "", false)
+		close(done)
+	}()
+	key := threadKey("C123", "1700000000.000100")
+	require.Eventually(t, func() bool { _, ok := repo.activeInterview(key); return ok }, time.Second, time.Millisecond)
+
+	// Files are shared in a message like any other, with the files alongside the text.
+	shared := threadMessage("U123", "C123", "1700000000.000100", "")
+	shared.InnerEvent.Data.(*slackevents.MessageEvent).Message = &goslack.Msg{Files: []goslack.File{
+		{Name: "dashboard.png", Mimetype: "image/png", URLPrivateDownload: api.url + "/files/dashboard.png"},
+	}}
+	s.handleCallbackEvent(shared)
+	s.handleCallbackEvent(threadMessage("U123", "C123", "1700000000.000100", "Nothing."))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "the interview did not finish")
+	}
+
+	downloads := api.sent("files/dashboard.png")
+	require.Len(t, downloads, 1)
+	assert.Equal(t, "xoxb-test", downloads[0].Get("token"))
+
+	transcript, err := repo.GetTranscript("interview-1")
+	require.NoError(t, err)
+	assert.Equal(t, "(attached dashboard.png)", transcript.Entries[0].Answer)
+	require.Len(t, transcript.Attachments, 1)
+	attachment := transcript.Attachments[0]
+	assert.Equal(t, domain.Attachment{Entry: 0, Name: "dashboard.png", MimeType: "image/png", Size: 4, BlobID: "blob-1"}, attachment)
+	data, err := repo.GetBlob(attachment.BlobID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x89PNG"), data)
+}